@@ -0,0 +1,394 @@
+// Package api serves the progress kept in the store as read-only JSON, for
+// dashboards and shell widgets that should not reach into the database.
+//
+// Endpoints:
+//
+//	GET /api/stats     per-character statistics and mastery
+//	GET /api/rows      row mastery
+//	GET /api/sessions  session history, newest first
+//	GET /api/settings  the saved settings
+//
+// Stats and rows take ?track=recognition (the default) or ?track=production;
+// sessions take ?limit=N (default 50, 0 for every session). When the server
+// has a token, requests must send it as "Authorization: Bearer <token>" or
+// as ?token=.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"kana/kanacore"
+	"kana/store"
+)
+
+// DefaultAddr is the address the API listens on unless another is given:
+// this machine only.
+const DefaultAddr = "127.0.0.1:7878"
+
+// defaultSessionLimit is how many sessions /api/sessions returns without a
+// limit.
+const defaultSessionLimit = 50
+
+// server answers the API's requests from the store.
+type server struct {
+	store *store.Store
+	token string
+	clock kanacore.Clock // grades mastery, which fades with time
+}
+
+// NewHandler returns the API's handler over st. A non-empty token must
+// accompany every request.
+func NewHandler(st *store.Store, token string) http.Handler {
+	s := &server{store: st, token: token, clock: kanacore.SystemClock}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/stats", s.handleStats)
+	mux.HandleFunc("GET /api/rows", s.handleRows)
+	mux.HandleFunc("GET /api/sessions", s.handleSessions)
+	mux.HandleFunc("GET /api/settings", s.handleSettings)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests without the server's token, if it has one.
+func (s *server) authorize(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			given = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="kana"`)
+			writeError(w, http.StatusUnauthorized, "missing or wrong token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// charStats is one character's statistics on a track.
+type charStats struct {
+	Char     string                `json:"char"`
+	Romaji   string                `json:"romaji"`
+	Row      string                `json:"row"`
+	Correct  int                   `json:"correct"`
+	Missed   int                   `json:"missed"`
+	Streak   int                   `json:"streak"`
+	Accuracy float64               `json:"accuracy"`
+	LastSeen time.Time             `json:"last_seen,omitzero"`
+	Mastery  kanacore.MasteryLevel `json:"mastery"`
+}
+
+// rowMastery is how far one row is mastered on a track.
+type rowMastery struct {
+	ID       string                        `json:"id"`
+	Label    string                        `json:"label"`
+	Mastered bool                          `json:"mastered"`
+	Levels   map[kanacore.MasteryLevel]int `json:"levels"` // characters per mastery level
+}
+
+// session is one finished session.
+type session struct {
+	ID        int64     `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Mode      string    `json:"mode"`
+	TimeLimit int       `json:"time_limit"`
+	Reason    string    `json:"reason"`
+	Score     int       `json:"score"`
+	Missed    int       `json:"missed"`
+	Hits      int       `json:"hits"`
+	Wrong     int       `json:"wrong"`
+	MaxCombo  int       `json:"max_combo"`
+	Points    points    `json:"points"`
+}
+
+// points breaks a session's score down.
+type points struct {
+	Base    int `json:"base"`
+	Combo   int `json:"combo"`
+	Speed   int `json:"speed"`
+	Penalty int `json:"penalty"`
+}
+
+// masteryPolicy is the rules for mastering a character.
+type masteryPolicy struct {
+	MinCorrect  int     `json:"min_correct"`
+	MinAccuracy float64 `json:"min_accuracy"`
+	MinStreak   int     `json:"min_streak"`
+	MaxAgeDays  int     `json:"max_age_days"` // 0 when mastery never fades
+}
+
+// settings is the saved settings, with the defaults the apps apply filled in.
+type settings struct {
+	SelectedRows   []string      `json:"selected_rows"`
+	SelectedChars  []string      `json:"selected_chars"`
+	AutoProgress   bool          `json:"auto_progress"`
+	Curriculum     string        `json:"curriculum"`
+	Mastery        masteryPolicy `json:"mastery"`
+	GameMode       string        `json:"game_mode"`
+	TimeLimit      int           `json:"time_limit"`
+	ScoreLimit     int           `json:"score_limit"`
+	ZenGoal        string        `json:"zen_goal"`
+	DailyGoal      string        `json:"daily_goal"`
+	TargetPolicy   string        `json:"target_policy"`
+	LookalikeGroup string        `json:"lookalike_group"`
+	GhostRace      bool          `json:"ghost_race"`
+}
+
+// handleStats lists every hiragana's statistics on the requested track, in
+// row order, graded under the saved mastery policy.
+func (s *server) handleStats(w http.ResponseWriter, r *http.Request) {
+	track, ok := parseTrack(w, r)
+	if !ok {
+		return
+	}
+	records, policy, err := s.records(track)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := s.clock.Now()
+	set := kanacore.Hiragana()
+	chars := []charStats{}
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			rec := records[char]
+			romaji, _ := set.GetRomaji(char)
+			chars = append(chars, charStats{
+				Char:     char,
+				Romaji:   romaji,
+				Row:      row.ID,
+				Correct:  rec.Correct,
+				Missed:   rec.Missed,
+				Streak:   rec.Streak,
+				Accuracy: rec.Accuracy(),
+				LastSeen: rec.LastSeen,
+				Mastery:  policy.Level(rec, now),
+			})
+		}
+	}
+	writeJSON(w, map[string]any{"track": track, "chars": chars})
+}
+
+// handleRows reports how far each row is mastered on the requested track.
+func (s *server) handleRows(w http.ResponseWriter, r *http.Request) {
+	track, ok := parseTrack(w, r)
+	if !ok {
+		return
+	}
+	records, policy, err := s.records(track)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := s.clock.Now()
+	rows := make([]rowMastery, 0, len(kanacore.AllKanaRows))
+	for _, row := range kanacore.AllKanaRows {
+		levels := make(map[kanacore.MasteryLevel]int)
+		rowRecords := make([]kanacore.CharRecord, 0, len(row.Characters))
+		for _, char := range row.Characters {
+			levels[policy.Level(records[char], now)]++
+			rowRecords = append(rowRecords, records[char])
+		}
+		rows = append(rows, rowMastery{
+			ID:       row.ID,
+			Label:    row.Label,
+			Mastered: policy.RowMastered(rowRecords, now),
+			Levels:   levels,
+		})
+	}
+	writeJSON(w, map[string]any{"track": track, "policy": policy.Label(), "rows": rows})
+}
+
+// handleSessions lists the most recent sessions, newest first.
+func (s *server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	limit := defaultSessionLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be a whole number, not %q", raw))
+			return
+		}
+		limit = n
+	}
+	records, err := s.store.Sessions(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sessions := make([]session, 0, len(records))
+	for _, rec := range records {
+		sessions = append(sessions, session{
+			ID:        rec.ID,
+			StartedAt: rec.StartedAt,
+			EndedAt:   rec.EndedAt,
+			Mode:      rec.Mode,
+			TimeLimit: rec.TimeLimit,
+			Reason:    rec.Reason,
+			Score:     rec.Score,
+			Missed:    rec.Missed,
+			Hits:      rec.Hits,
+			Wrong:     rec.Wrong,
+			MaxCombo:  rec.MaxCombo,
+			Points: points{
+				Base:    rec.BasePoints,
+				Combo:   rec.ComboPoints,
+				Speed:   rec.SpeedPoints,
+				Penalty: rec.PenaltyPoints,
+			},
+		})
+	}
+	writeJSON(w, map[string]any{"sessions": sessions})
+}
+
+// handleSettings reports the saved settings.
+func (s *server) handleSettings(w http.ResponseWriter, r *http.Request) {
+	out, err := s.settings()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, out)
+}
+
+// settings reads the saved settings, filling in the defaults the apps use
+// for those never saved.
+func (s *server) settings() (settings, error) {
+	var out settings
+	var err error
+	if out.SelectedRows, err = s.store.SelectedRows(); err != nil {
+		return out, err
+	}
+	if len(out.SelectedRows) == 0 {
+		out.SelectedRows = kanacore.DefaultRowIDs()
+	}
+	if out.SelectedChars, err = s.store.SelectedChars(); err != nil {
+		return out, err
+	}
+	if out.SelectedChars == nil {
+		out.SelectedChars = []string{}
+	}
+	if out.AutoProgress, err = s.store.AutoProgress(); err != nil {
+		return out, err
+	}
+	if out.Curriculum, err = s.store.Curriculum(); err != nil {
+		return out, err
+	}
+	if out.Curriculum == "" {
+		out.Curriculum = kanacore.DefaultCurriculumID
+	}
+	policy, err := s.masteryPolicy()
+	if err != nil {
+		return out, err
+	}
+	out.Mastery = masteryPolicy{
+		MinCorrect:  policy.MinCorrect,
+		MinAccuracy: policy.MinAccuracy,
+		MinStreak:   policy.MinStreak,
+		MaxAgeDays:  int(policy.MaxAge / (24 * time.Hour)),
+	}
+	mode, err := s.store.GameMode()
+	if err != nil {
+		return out, err
+	}
+	out.GameMode = string(kanacore.ParseGameMode(mode))
+	seconds, err := s.store.TimeLimit()
+	if err != nil {
+		return out, err
+	}
+	out.TimeLimit = int(kanacore.NormalizeTimeLimit(time.Duration(seconds)*time.Second) / time.Second)
+	if out.ScoreLimit, err = s.store.ScoreLimit(); err != nil {
+		return out, err
+	}
+	zen, err := s.store.ZenGoal()
+	if err != nil {
+		return out, err
+	}
+	out.ZenGoal = kanacore.ParseGoal(zen).String()
+	daily, err := s.store.DailyGoal()
+	if err != nil {
+		return out, err
+	}
+	out.DailyGoal = kanacore.ParseDailyGoal(daily).String()
+	target, err := s.store.TargetPolicy()
+	if err != nil {
+		return out, err
+	}
+	out.TargetPolicy = string(kanacore.ParseTargetPolicy(target))
+	if out.LookalikeGroup, err = s.store.LookalikeGroup(); err != nil {
+		return out, err
+	}
+	if out.GhostRace, err = s.store.GhostRace(); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// masteryPolicy returns the saved mastery policy, or the default.
+func (s *server) masteryPolicy() (kanacore.MasteryPolicy, error) {
+	value, err := s.store.MasteryPolicy()
+	if err != nil {
+		return kanacore.MasteryPolicy{}, err
+	}
+	if value == "" {
+		return kanacore.DefaultMasteryPolicy(), nil
+	}
+	return kanacore.ParseMasteryPolicy(value), nil
+}
+
+// records returns each character's record on track and the mastery policy to
+// grade them under.
+func (s *server) records(track store.StatsTrack) (map[string]kanacore.CharRecord, kanacore.MasteryPolicy, error) {
+	policy, err := s.masteryPolicy()
+	if err != nil {
+		return nil, policy, err
+	}
+	stats, err := s.store.TrackStatistics(track)
+	if err != nil {
+		return nil, policy, err
+	}
+	records := make(map[string]kanacore.CharRecord, len(stats))
+	for char, st := range stats {
+		records[char] = kanacore.CharRecord{
+			Correct:  st.CorrectCount,
+			Missed:   st.MissCount,
+			Streak:   st.Streak,
+			LastSeen: st.LastSeen,
+		}
+	}
+	return records, policy, nil
+}
+
+// parseTrack reads the ?track= parameter, answering with an error for an
+// unknown track.
+func parseTrack(w http.ResponseWriter, r *http.Request) (store.StatsTrack, bool) {
+	switch track := store.StatsTrack(r.URL.Query().Get("track")); track {
+	case "", store.TrackRecognition:
+		return store.TrackRecognition, true
+	case store.TrackProduction:
+		return track, true
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("track must be %q or %q, not %q",
+			store.TrackRecognition, store.TrackProduction, track))
+		return "", false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kana/store"
+)
+
+// newTestStore opens a store in a temporary directory holding a little
+// practice: あ mastered, い missed last, and two sessions.
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	st, err := store.Open(filepath.Join(t.TempDir(), "kana.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	now := time.Now()
+	if err := st.SaveTrackStats(store.TrackRecognition, "あ", 5, 0, 5, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SaveTrackStats(store.TrackRecognition, "い", 1, 2, 0, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SaveTrackStats(store.TrackProduction, "う", 2, 0, 2, now); err != nil {
+		t.Fatal(err)
+	}
+	for i, score := range []int{120, 340} {
+		start := now.Add(time.Duration(i-2) * time.Hour)
+		if _, err := st.SaveSession(store.SessionRecord{
+			StartedAt: start,
+			EndedAt:   start.Add(time.Minute),
+			Mode:      "classic",
+			Reason:    "misses",
+			Score:     score,
+			Hits:      score / 10,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := st.SaveGameMode("time_attack"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SaveSelectedRows([]string{"vowels", "k"}); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+// get requests path from h and decodes the JSON answer into v, returning the
+// status code.
+func get(t *testing.T, h http.Handler, path string, header http.Header, v any) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("%s: expected JSON, got %q: %s", path, ct, rec.Body)
+	}
+	if v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestStatsGradesEveryCharacter(t *testing.T) {
+	h := NewHandler(newTestStore(t), "")
+
+	var body struct {
+		Track string      `json:"track"`
+		Chars []charStats `json:"chars"`
+	}
+	if code := get(t, h, "/api/stats", nil, &body); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if body.Track != "recognition" || len(body.Chars) != 46 {
+		t.Fatalf("expected all 46 hiragana on the recognition track, got %s with %d", body.Track, len(body.Chars))
+	}
+	byChar := make(map[string]charStats)
+	for _, c := range body.Chars {
+		byChar[c.Char] = c
+	}
+	if a := byChar["あ"]; a.Correct != 5 || a.Romaji != "a" || a.Row != "vowels" || a.Mastery != "green" || a.LastSeen.IsZero() {
+		t.Errorf("unexpected あ: %+v", a)
+	}
+	if i := byChar["い"]; i.Mastery != "red" || i.Missed != 2 {
+		t.Errorf("expected い to be red, got %+v", i)
+	}
+	if u := byChar["う"]; u.Mastery != "unseen" || !u.LastSeen.IsZero() {
+		t.Errorf("expected う unseen on the recognition track, got %+v", u)
+	}
+
+	if code := get(t, h, "/api/stats?track=production", nil, &body); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	for _, c := range body.Chars {
+		if c.Char == "う" && c.Correct != 2 {
+			t.Errorf("expected う's production stats, got %+v", c)
+		}
+	}
+
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if code := get(t, h, "/api/stats?track=listening", nil, &failure); code != http.StatusBadRequest || failure.Error == "" {
+		t.Errorf("expected an unknown track to be a bad request, got %d %q", code, failure.Error)
+	}
+}
+
+func TestRowsReportMastery(t *testing.T) {
+	st := newTestStore(t)
+	now := time.Now()
+	for _, char := range []string{"い", "う", "え", "お"} {
+		if err := st.SaveTrackStats(store.TrackRecognition, char, 4, 0, 4, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewHandler(st, "")
+
+	var body struct {
+		Policy string       `json:"policy"`
+		Rows   []rowMastery `json:"rows"`
+	}
+	if code := get(t, h, "/api/rows", nil, &body); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if body.Policy == "" || len(body.Rows) == 0 {
+		t.Fatalf("expected the policy and rows, got %+v", body)
+	}
+	vowels, k := body.Rows[0], body.Rows[1]
+	if vowels.ID != "vowels" || !vowels.Mastered || vowels.Levels["green"] != 5 {
+		t.Errorf("expected the vowels mastered, got %+v", vowels)
+	}
+	if k.Mastered || k.Levels["unseen"] != 5 {
+		t.Errorf("expected the k row unseen, got %+v", k)
+	}
+}
+
+func TestSessionsNewestFirst(t *testing.T) {
+	h := NewHandler(newTestStore(t), "")
+
+	var body struct {
+		Sessions []session `json:"sessions"`
+	}
+	if code := get(t, h, "/api/sessions", nil, &body); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(body.Sessions) != 2 || body.Sessions[0].Score != 340 || body.Sessions[0].Hits != 34 {
+		t.Fatalf("expected both sessions, newest first, got %+v", body.Sessions)
+	}
+	if code := get(t, h, "/api/sessions?limit=1", nil, &body); code != http.StatusOK || len(body.Sessions) != 1 {
+		t.Errorf("expected one session with limit=1, got %d %+v", code, body.Sessions)
+	}
+	if code := get(t, h, "/api/sessions?limit=lots", nil, nil); code != http.StatusBadRequest {
+		t.Errorf("expected a bad limit to be a bad request, got %d", code)
+	}
+}
+
+func TestSettingsFillInDefaults(t *testing.T) {
+	h := NewHandler(newTestStore(t), "")
+
+	var body settings
+	if code := get(t, h, "/api/settings", nil, &body); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if body.GameMode != "time_attack" || len(body.SelectedRows) != 2 {
+		t.Errorf("expected the saved mode and rows, got %+v", body)
+	}
+	if body.ScoreLimit != store.DefaultScoreLimit || body.Curriculum == "" || body.Mastery.MinCorrect == 0 || body.TimeLimit == 0 {
+		t.Errorf("expected defaults for settings never saved, got %+v", body)
+	}
+}
+
+func TestTokenGuardsEveryEndpoint(t *testing.T) {
+	h := NewHandler(newTestStore(t), "s3cret")
+
+	for _, path := range []string{"/api/stats", "/api/rows", "/api/sessions", "/api/settings"} {
+		if code := get(t, h, path, nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without the token, got %d", path, code)
+		}
+		wrong := http.Header{"Authorization": {"Bearer nope"}}
+		if code := get(t, h, path, wrong, nil); code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 with a wrong token, got %d", path, code)
+		}
+		right := http.Header{"Authorization": {"Bearer s3cret"}}
+		if code := get(t, h, path, right, nil); code != http.StatusOK {
+			t.Errorf("%s: expected 200 with the bearer token, got %d", path, code)
+		}
+		if code := get(t, h, path+"?token=s3cret", nil, nil); code != http.StatusOK {
+			t.Errorf("%s: expected 200 with the token parameter, got %d", path, code)
+		}
+	}
+}
+
+func TestEndpointsAreReadOnly(t *testing.T) {
+	h := NewHandler(newTestStore(t), "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/settings", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST to be refused, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// showAchievementsDialog shows every achievement as a card: earned badges
+// with the date they were earned, the rest greyed out with how to earn them.
+func showAchievementsDialog(gs *GameState, win fyne.Window) {
+	earned := gs.Achievements()
+
+	cards := container.NewGridWithColumns(2)
+	for _, a := range kanacore.Achievements {
+		cards.Add(newAchievementCard(a, earned))
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("%d of %d badges earned", len(earned), len(kanacore.Achievements)))
+	content := container.NewBorder(summary, nil, nil, nil, container.NewVScroll(cards))
+	d := dialog.NewCustom("Achievements", "Close", content, win)
+	d.Resize(fyne.NewSize(520, 460))
+	d.Show()
+}
+
+func newAchievementCard(a kanacore.Achievement, earned map[string]time.Time) fyne.CanvasObject {
+	icon := canvas.NewText("🏅", WarmPaperTheme().kanaColor(colorKanaText))
+	icon.TextSize = 32
+	title := widget.NewLabelWithStyle(a.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	desc := widget.NewLabel(a.Description)
+	desc.Wrapping = fyne.TextWrapWord
+
+	status := widget.NewLabel("Not earned yet")
+	if at, ok := earned[a.ID]; ok {
+		status.SetText("Earned " + at.Format("2 Jan 2006"))
+		status.Importance = widget.SuccessImportance
+	} else {
+		icon.Text = "🔒"
+		title.Importance = widget.LowImportance
+		desc.Importance = widget.LowImportance
+		status.Importance = widget.LowImportance
+	}
+	return widget.NewCard("", "", container.NewBorder(nil, nil, icon, nil,
+		container.NewVBox(title, desc, status)))
+}
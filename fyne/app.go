@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
@@ -13,13 +15,13 @@ import (
 	"kana/store"
 )
 
-func buildWindow(a fyne.App, st *store.Store) fyne.Window {
+func buildWindow(a fyne.App, st *store.Store, curricula []kanacore.Curriculum) fyne.Window {
 	a.Settings().SetTheme(WarmPaperTheme())
 
 	w := a.NewWindow("Kana")
 	w.Resize(fyne.NewSize(900, 620))
 
-	gs := NewGameState(st)
+	gs := NewGameState(st, curricula)
 
 	statsPanel := newStatsPanel()
 	gameCanvas := newGameCanvas(gs)
@@ -27,6 +29,7 @@ func buildWindow(a fyne.App, st *store.Store) fyne.Window {
 	gs.canvas = gameCanvas
 
 	inputBar := newInputBar(gs, statsPanel, gameCanvas, w)
+	gs.inputBar = inputBar
 
 	statsContainer := container.NewPadded(statsPanel)
 
@@ -40,17 +43,26 @@ func buildWindow(a fyne.App, st *store.Store) fyne.Window {
 
 	w.SetContent(layout)
 
-	// Initial stats render
-	gs.mu.Lock()
-	snap := gs.snapshot()
-	gs.mu.Unlock()
-	statsPanel.Update(snap)
+	start := func() {
+		// Initial stats render
+		gs.mu.Lock()
+		snap := gs.snapshot()
+		gs.mu.Unlock()
+		statsPanel.Update(snap)
+		inputBar.Update(snap)
 
-	// Start game loop
-	gs.Start(gameCanvas)
+		// Start game loop
+		gs.Start(gameCanvas)
 
-	// Watch for game events
-	go watchEvents(gs, statsPanel, gameCanvas, inputBar, w)
+		// Watch for game events
+		go watchEvents(gs, statsPanel, gameCanvas, inputBar, w)
+	}
+	// First launch: place the player before the first kana falls.
+	if gs.NeedsPlacement() {
+		showPlacementDialog(gs, w, start)
+	} else {
+		start()
+	}
 
 	w.SetOnClosed(func() {
 		gs.Stop() // closes stopCh; safe if already stopped
@@ -78,14 +90,45 @@ func watchEvents(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameCanvas,
 	}
 }
 
+// highScoreText compares the session with the best earlier result for its mode.
+func highScoreText(snap StatsSnapshot) string {
+	if !snap.HasStore {
+		return ""
+	}
+	if snap.Mode == kanacore.ModeDaily {
+		hits := snap.Breakdown.Hits
+		if hits > snap.PreviousBest {
+			return fmt.Sprintf("Daily challenge %s: %d correct kana (new best today!)", snap.SessionDay, hits)
+		}
+		return fmt.Sprintf("Daily challenge %s: %d correct kana (best today: %d)", snap.SessionDay, hits, snap.PreviousBest)
+	}
+	if snap.Mode.Timed() {
+		secs := int(snap.TimeLimit / time.Second)
+		hits := snap.Breakdown.Hits
+		if hits > snap.PreviousBest {
+			return fmt.Sprintf("Correct kana: %d in %ds (new time attack record!)", hits, secs)
+		}
+		return fmt.Sprintf("Correct kana: %d in %ds (best: %d)", hits, secs, snap.PreviousBest)
+	}
+	if snap.Score > snap.PreviousBest {
+		return "New high score!"
+	}
+	return fmt.Sprintf("High score: %d", snap.PreviousBest)
+}
+
 func showGameOverDialog(gs *GameState, snap StatsSnapshot, reason string, statsPanel *StatsPanel, gameCanvas *GameCanvas, inputBar *InputBar, w fyne.Window) {
 	title := "GAME OVER"
-	if reason == "score" {
+	switch reason {
+	case "score":
 		title = "SESSION COMPLETE"
+	case "time":
+		title = "TIME'S UP"
+	case "goal":
+		title = "GOAL REACHED"
 	}
 
 	scoreText := fmt.Sprintf("Score: %d", snap.Score)
-	if snap.ScoreLimit > 0 {
+	if snap.ScoreLimit > 0 && !snap.Mode.Timed() {
 		scoreText = fmt.Sprintf("Score: %d/%d", snap.Score, snap.ScoreLimit)
 	}
 
@@ -95,6 +138,13 @@ func showGameOverDialog(gs *GameState, snap StatsSnapshot, reason string, statsP
 		reasonText = "You reached your target score!"
 	case "misses":
 		reasonText = "10 kana slipped through."
+	case "time":
+		reasonText = "The clock ran out."
+	case "goal":
+		reasonText = fmt.Sprintf("You practised %s. Well done!", snap.ZenGoal.Label())
+		if snap.Mode == kanacore.ModeDrill || snap.Mode == kanacore.ModeLookalike {
+			reasonText = "Drill complete. Well done!"
+		}
 	default:
 		reasonText = "Session ended."
 	}
@@ -122,25 +172,72 @@ func showGameOverDialog(gs *GameState, snap StatsSnapshot, reason string, statsP
 		missedText = strings.Join(missedParts, ", ")
 	}
 
+	b := snap.Breakdown
+	breakdownText := strings.Join([]string{
+		fmt.Sprintf("Correct answers: %+d (%d hits)", b.Base, b.Hits),
+		fmt.Sprintf("Combo bonus: %+d (best combo %d)", b.Combo, b.MaxCombo),
+		fmt.Sprintf("Speed bonus: %+d", b.Speed),
+		fmt.Sprintf("Wrong answers: %+d (%d wrong)", -b.Penalty, b.Wrong),
+	}, "\n")
+
+	ghostLabel := widget.NewLabel(fmt.Sprintf("👻 Ghost: %d — %s", snap.GhostFinal, snap.GhostResult))
+	if snap.GhostResult == "" {
+		ghostLabel.Hide()
+	}
+
 	content := container.NewVBox(
 		widget.NewLabelWithStyle(title, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
 		widget.NewLabel(scoreText),
-		widget.NewLabel(fmt.Sprintf("Missed: %d/10", snap.Missed)),
+		widget.NewLabel("Missed: "+formatMissed(snap)),
+		widget.NewLabel(highScoreText(snap)),
+		ghostLabel,
 		widget.NewLabel(reasonText),
 		widget.NewSeparator(),
+		widget.NewLabel("Score breakdown:"),
+		widget.NewLabel(breakdownText),
+		widget.NewSeparator(),
 		widget.NewLabel("Characters missed:"),
 		widget.NewLabel(missedText),
 	)
 
-	dialog.ShowCustomConfirm("", "Play Again", "Quit", content, func(playAgain bool) {
-		if !playAgain {
-			gs.mu.Lock()
-			gs.mergeSessionStats()
-			gs.mu.Unlock()
-			w.Close()
-			return
+	if len(snap.Badges) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Badges earned:"))
+		for _, a := range snap.Badges {
+			content.Add(widget.NewLabel("🏅 " + a.Title + " — " + a.Description))
+		}
+	}
+
+	if len(unique) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Memory hints (leave a hint empty to restore the original):"))
+		content.Add(newMissedHints(gs, snap.MissedKanas, w))
+	}
+
+	if snap.Mode == kanacore.ModeDrill {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Drill results (before → after):"))
+		content.Add(widget.NewLabel(formatDrillResults(snap.DrillResults)))
+	}
+
+	if len(snap.Mixups) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(widget.NewLabel("Look-alikes you mixed up:"))
+		for i, mix := range snap.Mixups {
+			if i == maxMixupComparisons {
+				break
+			}
+			content.Add(newMixupComparison(mix))
+		}
+	}
+
+	var d *dialog.CustomDialog
+	restart := func(drill bool) {
+		d.Hide()
+		if !drill || !gs.StartDrill() {
+			gs.EndDrill()
+			gs.Reset() // closes old stopCh and eventCh; old watcher exits
 		}
-		gs.Reset()                                              // closes old stopCh and eventCh; old watcher exits
 		gs.Start(gameCanvas)                                    // launches new tick/spawn goroutines
 		go watchEvents(gs, statsPanel, gameCanvas, inputBar, w) // new watcher on new eventCh
 
@@ -150,5 +247,66 @@ func showGameOverDialog(gs *GameState, snap StatsSnapshot, reason string, statsP
 		statsPanel.Update(snap)
 		inputBar.Update(snap)
 		gameCanvas.Refresh()
-	}, w)
+	}
+
+	quitBtn := widget.NewButton("Quit", func() {
+		d.Hide()
+		gs.mu.Lock()
+		gs.mergeSessionStats()
+		gs.mu.Unlock()
+		w.Close()
+	})
+	againBtn := widget.NewButton("Play Again", func() { restart(false) })
+	againBtn.Importance = widget.HighImportance
+	buttons := []fyne.CanvasObject{quitBtn}
+	if replay := gs.LastReplay(); replay != nil {
+		buttons = append(buttons, widget.NewButton("Watch replay", func() { showReplayDialog(replay, w) }))
+	}
+	buttons = append(buttons, widget.NewButton("Split screen", func() { showVersusSetup(gs, gs.store, w) }))
+	buttons = append(buttons, widget.NewButton("LAN race", func() { showLANSetup(gs, gs.store, w) }))
+	if len(unique) > 0 {
+		buttons = append(buttons, widget.NewButton("Drill missed", func() { restart(true) }))
+	}
+	buttons = append(buttons, againBtn)
+
+	d = dialog.NewCustomWithoutButtons("", content, w)
+	d.SetButtons(buttons)
+	d.Show()
+}
+
+// maxMixupComparisons caps how many look-alike pairs the game-over dialog compares.
+const maxMixupComparisons = 3
+
+// newMixupComparison shows a confused look-alike pair side by side in large
+// type, each kana above its romaji.
+func newMixupComparison(mix kanacore.LookalikeMixup) fyne.CanvasObject {
+	hiragana := kanacore.Hiragana()
+	kanaCard := func(char string) fyne.CanvasObject {
+		romaji, _ := hiragana.GetRomaji(char)
+		text := canvas.NewText(char, WarmPaperTheme().kanaColor(colorKanaText))
+		text.TextSize = 48
+		text.Alignment = fyne.TextAlignCenter
+		return container.NewVBox(text, widget.NewLabelWithStyle(romaji, fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	givenRomaji, _ := hiragana.GetRomaji(mix.Given)
+	note := widget.NewLabel(fmt.Sprintf("%s answered as %s ×%d", mix.Shown, givenRomaji, mix.Count))
+	return container.NewHBox(
+		kanaCard(mix.Shown),
+		container.NewCenter(widget.NewLabel("≠")),
+		kanaCard(mix.Given),
+		container.NewCenter(note),
+	)
+}
+
+// formatDrillResults lists each drilled character's accuracy before and during the drill.
+func formatDrillResults(results []kanacore.DrillComparison) string {
+	lines := make([]string, 0, len(results))
+	for _, r := range results {
+		mark := ""
+		if r.Improved() {
+			mark = " ↑"
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s → %s%s", r.Char, r.Before, r.After, mark))
+	}
+	return strings.Join(lines, "\n")
 }
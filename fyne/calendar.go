@@ -0,0 +1,70 @@
+package main
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// calendarCellSize is the side length of one day in the practice calendar.
+const calendarCellSize float32 = 16
+
+// heatColors shade practice calendar days by kanacore.HeatLevel.
+var heatColors = [kanacore.HeatLevels]color.Color{
+	color.RGBA{R: 0xe8, G: 0xdf, B: 0xcc, A: 0xff},
+	color.RGBA{R: 0xc6, G: 0xe4, B: 0x8b, A: 0xff},
+	color.RGBA{R: 0x7b, G: 0xc9, B: 0x6f, A: 0xff},
+	color.RGBA{R: 0x23, G: 0x9a, B: 0x3b, A: 0xff},
+	color.RGBA{R: 0x19, G: 0x61, B: 0x27, A: 0xff},
+}
+
+// showCalendarDialog shows a heatmap of the last weeks' practice, one column
+// per week and one row per weekday, with today's progress and day streaks.
+func showCalendarDialog(gs *GameState, win fyne.Window) {
+	now := time.Now()
+	weeks, levels := gs.practiceCalendar(now)
+	gs.mu.Lock()
+	snap := gs.snapshot()
+	gs.mu.Unlock()
+
+	grid := container.NewGridWithColumns(len(weeks) + 1)
+	for day, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		grid.Add(widget.NewLabel(name))
+		for _, week := range weeks {
+			if week[day] == "" {
+				grid.Add(container.NewCenter(newHeatCell(color.Transparent)))
+				continue
+			}
+			grid.Add(container.NewCenter(newHeatCell(heatColors[levels[week[day]]])))
+		}
+	}
+
+	legend := container.NewHBox(widget.NewLabel("Less"))
+	for _, c := range heatColors {
+		legend.Add(container.NewCenter(newHeatCell(c)))
+	}
+	legend.Add(widget.NewLabel("More (darkest: goal met)"))
+
+	content := container.NewVBox(
+		widget.NewLabel("Daily goal: "+snap.DailyGoal.DailyLabel()),
+		grid,
+		legend,
+		widget.NewSeparator(),
+		widget.NewLabel(snap.DailyProgress),
+		widget.NewLabel(formatStreak(snap)),
+	)
+	dialog.ShowCustom("Practice calendar", "Close", content, win)
+}
+
+func newHeatCell(c color.Color) *canvas.Rectangle {
+	r := canvas.NewRectangle(c)
+	r.CornerRadius = 3
+	r.SetMinSize(fyne.NewSize(calendarCellSize, calendarCellSize))
+	return r
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+	"kana/store"
+)
+
+// charPicker is the settings section for practising individual characters
+// instead of whole rows, with named custom sets such as "my trouble kana".
+type charPicker struct {
+	enabled   *widget.Check
+	checks    map[string]*widget.Check
+	grid      *fyne.Container
+	sets      map[string][]string
+	setList   *widget.Select
+	setName   *widget.Entry
+	st        *store.Store
+	win       fyne.Window
+	Container fyne.CanvasObject
+}
+
+func newCharPicker(gs *GameState, current []string, win fyne.Window) *charPicker {
+	p := &charPicker{
+		checks: make(map[string]*widget.Check),
+		sets:   make(map[string][]string),
+		st:     gs.store,
+		win:    win,
+	}
+
+	picked := make(map[string]bool, len(current))
+	for _, char := range current {
+		picked[char] = true
+	}
+	cells := make([]fyne.CanvasObject, 0, len(kanacore.CharToRow))
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			check := widget.NewCheck(char, nil)
+			check.SetChecked(picked[char])
+			p.checks[char] = check
+			cells = append(cells, check)
+		}
+	}
+	p.grid = container.NewGridWithColumns(5, cells...)
+
+	p.enabled = widget.NewCheck("Practise only these characters", func(on bool) {
+		p.setGridEnabled(on)
+	})
+	p.enabled.SetChecked(len(current) > 0)
+	p.setGridEnabled(len(current) > 0)
+
+	p.setList = widget.NewSelect(nil, func(name string) {
+		chars, ok := p.sets[name]
+		if !ok {
+			return
+		}
+		p.setName.SetText(name)
+		p.pick(chars)
+	})
+	p.setList.PlaceHolder = "Load a custom set"
+	p.setName = widget.NewEntry()
+	p.setName.SetPlaceHolder("Set name, e.g. my trouble kana")
+	saveBtn := widget.NewButton("Save set", p.saveSet)
+	deleteBtn := widget.NewButton("Delete set", p.deleteSet)
+	if p.st == nil {
+		p.setList.Disable()
+		p.setName.Disable()
+		saveBtn.Disable()
+		deleteBtn.Disable()
+	}
+	p.reloadSets()
+
+	content := container.NewVBox(
+		p.enabled,
+		p.grid,
+		p.setList,
+		p.setName,
+		container.NewGridWithColumns(2, saveBtn, deleteBtn),
+	)
+	item := widget.NewAccordionItem("Individual characters", content)
+	item.Open = len(current) > 0
+	p.Container = widget.NewAccordion(item)
+	return p
+}
+
+// Selected returns the picked characters in row order, or nil when the
+// player practises whole rows.
+func (p *charPicker) Selected() []string {
+	if !p.enabled.Checked {
+		return nil
+	}
+	return p.checkedChars()
+}
+
+func (p *charPicker) checkedChars() []string {
+	chars := make([]string, 0, len(p.checks))
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			if p.checks[char].Checked {
+				chars = append(chars, char)
+			}
+		}
+	}
+	return chars
+}
+
+// pick checks exactly the given characters and turns the picker on.
+func (p *charPicker) pick(chars []string) {
+	wanted := make(map[string]bool, len(chars))
+	for _, char := range chars {
+		wanted[char] = true
+	}
+	for char, check := range p.checks {
+		check.SetChecked(wanted[char])
+	}
+	p.enabled.SetChecked(true)
+}
+
+func (p *charPicker) setGridEnabled(on bool) {
+	for _, check := range p.checks {
+		if on {
+			check.Enable()
+		} else {
+			check.Disable()
+		}
+	}
+}
+
+func (p *charPicker) reloadSets() {
+	if p.st == nil {
+		return
+	}
+	sets, err := p.st.CustomSets()
+	if err != nil {
+		return
+	}
+	p.sets = make(map[string][]string, len(sets))
+	names := make([]string, 0, len(sets))
+	for _, set := range sets {
+		p.sets[set.Name] = set.Chars
+		names = append(names, set.Name)
+	}
+	p.setList.SetOptions(names)
+}
+
+func (p *charPicker) saveSet() {
+	name := strings.TrimSpace(p.setName.Text)
+	chars := p.checkedChars()
+	if name == "" || len(chars) == 0 {
+		dialog.ShowInformation("Custom sets", "Pick some characters and enter a name first.", p.win)
+		return
+	}
+	if err := p.st.SaveCustomSet(name, chars); err != nil {
+		dialog.ShowError(err, p.win)
+		return
+	}
+	p.reloadSets()
+	p.setList.SetSelected(name)
+}
+
+func (p *charPicker) deleteSet() {
+	name := p.setList.Selected
+	if name == "" {
+		return
+	}
+	if err := p.st.DeleteCustomSet(name); err != nil {
+		dialog.ShowError(err, p.win)
+		return
+	}
+	p.setList.ClearSelected()
+	p.reloadSets()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// drawPadSize is the minimum side length of the handwriting pad.
+const drawPadSize float32 = 180
+
+// DrawPad is a square surface the player draws kana on with the mouse.
+// Each drag from press to release becomes one stroke.
+type DrawPad struct {
+	widget.BaseWidget
+
+	strokes  kanacore.Drawing
+	inStroke bool
+	bg       *canvas.Rectangle
+	ink      *fyne.Container
+}
+
+func newDrawPad() *DrawPad {
+	p := &DrawPad{
+		bg:  canvas.NewRectangle(color.RGBA{R: 0xfa, G: 0xf3, B: 0xe3, A: 0xff}),
+		ink: container.NewWithoutLayout(),
+	}
+	p.bg.StrokeColor = color.RGBA{R: 0xb8, G: 0x95, B: 0x6a, A: 0xff}
+	p.bg.StrokeWidth = 2
+	p.bg.SetMinSize(fyne.NewSize(drawPadSize, drawPadSize))
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+func (p *DrawPad) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(p.bg, p.ink))
+}
+
+// Dragged extends the current stroke, starting a new one on the first event of a drag.
+func (p *DrawPad) Dragged(e *fyne.DragEvent) {
+	pos := e.Position
+	if !p.inStroke {
+		start := pos.Subtract(e.Dragged)
+		p.strokes = append(p.strokes, kanacore.Stroke{{X: float64(start.X), Y: float64(start.Y)}})
+		p.inStroke = true
+	}
+	i := len(p.strokes) - 1
+	last := p.strokes[i][len(p.strokes[i])-1]
+	p.strokes[i] = append(p.strokes[i], kanacore.Point{X: float64(pos.X), Y: float64(pos.Y)})
+
+	p.ink.Add(newInkLine(fyne.NewPos(float32(last.X), float32(last.Y)), pos))
+}
+
+// DragEnd finishes the current stroke.
+func (p *DrawPad) DragEnd() {
+	p.inStroke = false
+}
+
+// Drawing returns a copy of the strokes drawn so far.
+func (p *DrawPad) Drawing() kanacore.Drawing {
+	d := make(kanacore.Drawing, len(p.strokes))
+	for i, s := range p.strokes {
+		d[i] = append(kanacore.Stroke(nil), s...)
+	}
+	return d
+}
+
+// Undo removes the most recent stroke.
+func (p *DrawPad) Undo() {
+	if len(p.strokes) == 0 {
+		return
+	}
+	p.strokes = p.strokes[:len(p.strokes)-1]
+	p.redraw()
+}
+
+// Clear wipes the pad.
+func (p *DrawPad) Clear() {
+	p.strokes = nil
+	p.inStroke = false
+	p.redraw()
+}
+
+func (p *DrawPad) redraw() {
+	p.ink.RemoveAll()
+	for _, s := range p.strokes {
+		for j := 1; j < len(s); j++ {
+			from := fyne.NewPos(float32(s[j-1].X), float32(s[j-1].Y))
+			to := fyne.NewPos(float32(s[j].X), float32(s[j].Y))
+			p.ink.Add(newInkLine(from, to))
+		}
+	}
+}
+
+// newInkLine draws one segment of a stroke.
+func newInkLine(from, to fyne.Position) *canvas.Line {
+	line := canvas.NewLine(color.RGBA{R: 0x2c, G: 0x1a, B: 0x0e, A: 0xff})
+	line.StrokeWidth = 4
+	line.Position1 = from
+	line.Position2 = to
+	return line
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func drag(p *DrawPad, points ...fyne.Position) {
+	for i := 1; i < len(points); i++ {
+		p.Dragged(&fyne.DragEvent{
+			PointEvent: fyne.PointEvent{Position: points[i]},
+			Dragged:    fyne.NewDelta(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y),
+		})
+	}
+	p.DragEnd()
+}
+
+func TestDrawPadRecordsStrokes(t *testing.T) {
+	test.NewApp()
+	p := newDrawPad()
+	drag(p, fyne.NewPos(10, 10), fyne.NewPos(50, 10), fyne.NewPos(90, 12))
+	drag(p, fyne.NewPos(40, 5), fyne.NewPos(40, 80))
+
+	d := p.Drawing()
+	if len(d) != 2 {
+		t.Fatalf("expected 2 strokes, got %d", len(d))
+	}
+	if len(d[0]) != 3 || d[0][0].X != 10 || d[0][2].X != 90 {
+		t.Errorf("unexpected first stroke %v", d[0])
+	}
+
+	p.Undo()
+	if len(p.Drawing()) != 1 {
+		t.Errorf("expected Undo to leave 1 stroke, got %d", len(p.Drawing()))
+	}
+	p.Clear()
+	if len(p.Drawing()) != 0 {
+		t.Errorf("expected Clear to empty the pad")
+	}
+}
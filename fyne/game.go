@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,28 +23,78 @@ type gameEvent struct {
 	kind gameEventType
 }
 
+// unlockMessageDuration is how long unlock, regression and badge messages
+// stay in the stats panel.
+const unlockMessageDuration = 5 * time.Second
+
+// hintDuration is how long a missed kana's memory hint stays above the answer box.
+const hintDuration = 4 * time.Second
+
 // GameState holds all game state for the Fyne desktop game.
 type GameState struct {
 	mu sync.Mutex
 
-	tiles       []*KanaTile
-	score       int
-	scoreLimit  int
-	missed      int
-	over        bool
-	overReason  string
-	missedKanas []kanacore.Kana
+	tiles        []*KanaTile
+	score        int
+	scorer       kanacore.Scorer
+	scoreLimit   int
+	mode         kanacore.GameMode
+	timeLimit    time.Duration
+	previousBest int
+	sessionStart time.Time
+	missed       int
+	over         bool
+	overReason   string
+	missedKanas  []kanacore.Kana
+	zenGoal      kanacore.Goal
+	reveals      []*MissReveal
+	choiceTile   *KanaTile // tile the multiple-choice answers are for
+	choices      []string
 
 	sessionStats  map[string]store.KanaStats
 	overallStats  map[string]store.KanaStats
 	currentStreak map[string]int
 	sessionDirty  bool
+	confusions    map[string]map[string]int     // wrong romaji given per kana
+	sessionMixups map[string]map[string]int     // wrong romaji given per kana this session
+	tallies       map[string]kanacore.CharTally // this session's results per kana
+
+	drillChars  []string                      // characters practised in drill mode
+	drillBefore map[string]kanacore.CharTally // drilled characters' results in the session that was drilled
+	drillReturn kanacore.GameMode             // mode to restore when leaving a drill
+
+	lookalikeChoice string                  // configured look-alike group ID; "" drills the most confused group
+	lookalike       kanacore.LookalikeGroup // group dropped in look-alike drills
+	prevLookalike   string                  // last kana dropped in a look-alike drill
+
+	seed     int64              // seeds this session's tile spawning
+	rng      *rand.Rand         // draws tile characters, positions and speeds
+	clock    kanacore.Clock     // time source of the game logic
+	recorder *kanacore.Recorder // records the running session for replay
+	replay   *kanacore.Replay   // recording of the session that just ended
+
+	ghostRace   bool            // race a ghost of the best recorded run
+	ghost       *kanacore.Ghost // recorded run the session is racing
+	ghostResult string          // how the session finished against its ghost
 
 	selectedRows  map[string]bool
+	selectedChars map[string]bool // individually picked characters; overrides selectedRows when non-empty
 	autoProgress  bool
+	masteryPolicy kanacore.MasteryPolicy
+	targetPolicy  kanacore.TargetPolicy
+	curricula     []kanacore.Curriculum
+	curriculum    kanacore.Curriculum // drives auto-progression
 	newlyUnlocked []string
+	rowHistory    kanacore.RowHistory
+	regression    *kanacore.Regression // active auto-regression narrowing selectedRows
 	unlockMessage string
 	unlockAt      time.Time
+	mnemonics     map[string]string // the player's own memory hints, overriding the bundled ones
+	hint          string            // memory hint for the kana missed last
+	hintAt        time.Time
+	practice      *kanacore.PracticeLog // per-day totals towards the daily goal
+	achievements  *kanacore.AchievementTracker
+	sessionBadges []kanacore.Achievement // achievements earned this session
 
 	store *store.Store
 
@@ -56,23 +108,45 @@ type GameState struct {
 	canvasH float32
 
 	charSet kanacore.CharacterSet
+	words   []kanacore.Word
 
 	canvas     *GameCanvas
 	statsPanel *StatsPanel
+	inputBar   *InputBar
 }
 
-// NewGameState constructs a new GameState, loading persisted state if store is non-nil.
-func NewGameState(st *store.Store) *GameState {
+// NewGameState constructs a new GameState, loading persisted state if store
+// is non-nil. curricula are the curricula to choose from; nil offers the
+// bundled ones.
+func NewGameState(st *store.Store, curricula []kanacore.Curriculum) *GameState {
 	gs := &GameState{
 		sessionStats:  make(map[string]store.KanaStats),
 		overallStats:  make(map[string]store.KanaStats),
 		currentStreak: make(map[string]int),
+		confusions:    make(map[string]map[string]int),
+		sessionMixups: make(map[string]map[string]int),
+		tallies:       make(map[string]kanacore.CharTally),
+		rowHistory:    make(kanacore.RowHistory),
 		selectedRows:  make(map[string]bool),
+		selectedChars: make(map[string]bool),
+		mnemonics:     make(map[string]string),
 		eventCh:       make(chan gameEvent, 4),
 		stopCh:        make(chan struct{}),
 		scoreLimit:    store.DefaultScoreLimit,
+		scorer:        kanacore.Scorer{Rules: kanacore.DefaultScoreRules()},
+		sessionStart:  time.Now(),
+		mode:          kanacore.DefaultGameMode,
+		timeLimit:     kanacore.DefaultTimeAttackDuration,
+		zenGoal:       kanacore.DefaultZenGoal,
+		targetPolicy:  kanacore.DefaultTargetPolicy,
+		masteryPolicy: kanacore.DefaultMasteryPolicy(),
+		curriculum:    kanacore.DefaultCurriculum(),
+		practice:      kanacore.NewPracticeLog(kanacore.DefaultDailyGoal, nil),
+		achievements:  kanacore.NewAchievementTracker(nil),
 		charSet:       kanacore.Hiragana(),
+		words:         kanacore.N5Words(),
 		store:         st,
+		clock:         kanacore.SystemClock,
 		canvasW:       400,
 		canvasH:       600,
 	}
@@ -80,28 +154,72 @@ func NewGameState(st *store.Store) *GameState {
 	for _, id := range kanacore.DefaultRowIDs() {
 		gs.selectedRows[id] = true
 	}
+	gs.curricula = curricula
+	if gs.curricula == nil {
+		gs.curricula = kanacore.DefaultCurricula()
+	}
 
 	if st != nil {
 		if rows, err := st.SelectedRows(); err == nil && len(rows) > 0 {
 			gs.applySelectedRows(rows)
 		}
+		if chars, err := st.SelectedChars(); err == nil {
+			gs.applySelectedChars(chars)
+		}
+		if value, err := st.Regression(); err == nil {
+			if r, ok := kanacore.ParseRegression(value); ok {
+				gs.regression = &r
+			}
+		}
 		if auto, err := st.AutoProgress(); err == nil {
 			gs.autoProgress = auto
 		}
+		if race, err := st.GhostRace(); err == nil {
+			gs.ghostRace = race
+		}
 		if limit, err := st.ScoreLimit(); err == nil {
 			if limit < 0 {
 				limit = 0
 			}
 			gs.scoreLimit = limit
 		}
-		if stats, err := st.KanaStatistics(); err == nil {
-			for _, stat := range stats {
-				copied := stat
-				gs.overallStats[stat.Char] = copied
-				gs.currentStreak[stat.Char] = stat.Streak
+		if policy, err := st.TargetPolicy(); err == nil {
+			gs.targetPolicy = kanacore.ParseTargetPolicy(policy)
+		}
+		if policy, err := st.MasteryPolicy(); err == nil && policy != "" {
+			gs.masteryPolicy = kanacore.ParseMasteryPolicy(policy)
+		}
+		if id, err := st.Curriculum(); err == nil {
+			if c, ok := kanacore.FindCurriculum(gs.curricula, id); ok {
+				gs.curriculum = c
 			}
 		}
+		if mode, err := st.GameMode(); err == nil {
+			gs.mode = kanacore.ParseGameMode(mode)
+		}
+		if seconds, err := st.TimeLimit(); err == nil {
+			gs.timeLimit = kanacore.NormalizeTimeLimit(time.Duration(seconds) * time.Second)
+		}
+		if goal, err := st.ZenGoal(); err == nil {
+			gs.zenGoal = kanacore.ParseGoal(goal)
+		}
+		if confusions, err := st.Confusions(); err == nil {
+			gs.confusions = confusions
+		}
+		if group, err := st.LookalikeGroup(); err == nil {
+			gs.lookalikeChoice = group
+		}
+		if hints, err := st.Mnemonics(); err == nil {
+			gs.mnemonics = hints
+		}
+		gs.practice = loadPracticeLog(st)
+		if earned, err := st.Achievements(); err == nil {
+			gs.achievements = kanacore.NewAchievementTracker(earned)
+		}
+		gs.loadOverallStats()
 	}
+	gs.resolveLookalike()
+	gs.reseed()
 
 	return gs
 }
@@ -110,6 +228,7 @@ func NewGameState(st *store.Store) *GameState {
 func (gs *GameState) Start(canvas *GameCanvas) {
 	gs.mu.Lock()
 	gs.canvas = canvas
+	gs.practice.Start(gs.now())
 	gs.mu.Unlock()
 	go gs.tickLoop()
 	go gs.spawnLoop()
@@ -140,28 +259,35 @@ func (gs *GameState) Reset() {
 
 	gs.tiles = nil
 	gs.score = 0
+	gs.scorer = kanacore.Scorer{Rules: kanacore.DefaultScoreRules()}
+	gs.sessionStart = gs.now()
+	gs.reseed()
+	gs.replay = nil
+	gs.ghostResult = ""
+	gs.previousBest = 0
 	gs.missed = 0
 	gs.over = false
 	gs.overReason = ""
 	gs.missedKanas = nil
+	gs.reveals = nil
+	gs.choiceTile = nil
+	gs.choices = nil
 	gs.sessionStats = make(map[string]store.KanaStats)
 	gs.currentStreak = make(map[string]int)
+	gs.tallies = make(map[string]kanacore.CharTally)
+	gs.sessionMixups = make(map[string]map[string]int)
 	gs.sessionDirty = false
 	gs.newlyUnlocked = nil
+	gs.rowHistory = make(kanacore.RowHistory)
 	gs.unlockMessage = ""
 	gs.unlockAt = time.Time{}
+	gs.hint = ""
+	gs.hintAt = time.Time{}
+	gs.sessionBadges = nil
 
 	// reload overall stats
 	gs.overallStats = make(map[string]store.KanaStats)
-	if gs.store != nil {
-		if stats, err := gs.store.KanaStatistics(); err == nil {
-			for _, stat := range stats {
-				copied := stat
-				gs.overallStats[stat.Char] = copied
-				gs.currentStreak[stat.Char] = stat.Streak
-			}
-		}
-	}
+	gs.loadOverallStats()
 
 	gs.buildSnapshot()
 	gs.mu.Unlock()
@@ -178,8 +304,11 @@ func (gs *GameState) Stop() {
 	}
 }
 
+// tickInterval is how often falling tiles move one step.
+const tickInterval = 100 * time.Millisecond
+
 func (gs *GameState) tickLoop() {
-	ticker := time.NewTicker(100 * time.Millisecond)
+	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
 
 	gs.mu.Lock()
@@ -197,19 +326,28 @@ func (gs *GameState) tickLoop() {
 }
 
 func (gs *GameState) spawnLoop() {
-	ticker := time.NewTicker(4 * time.Second)
-	defer ticker.Stop()
-
 	gs.mu.Lock()
 	stop := gs.stopCh
+	interval := gs.mode.SpawnInterval()
 	gs.mu.Unlock()
 
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-stop:
 			return
 		case <-ticker.C:
 			gs.spawnKana()
+			// Follow mode changes made in the settings mid-session.
+			gs.mu.Lock()
+			next := gs.mode.SpawnInterval()
+			gs.mu.Unlock()
+			if next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
 		}
 	}
 }
@@ -221,31 +359,104 @@ func (gs *GameState) tick() {
 		return
 	}
 
+	if gs.mode.Timed() && gs.timeRemaining() <= 0 {
+		gs.endGame("time")
+		gs.mu.Unlock()
+		return
+	}
+	if gs.goalReached() {
+		gs.endGame("goal")
+		gs.mu.Unlock()
+		return
+	}
+
+	now := gs.now()
+	kept := gs.reveals[:0]
+	for _, r := range gs.reveals {
+		if !r.Expired(now) {
+			kept = append(kept, r)
+		}
+	}
+	gs.reveals = kept
+	hintChanged := false
+	if gs.hint != "" && now.Sub(gs.hintAt) >= hintDuration {
+		gs.hint = ""
+		hintChanged = true
+	}
+	if gs.ghost != nil {
+		gs.spawnGhostTiles(now)
+	}
+	gs.recorder.Tick(now)
+
 	for i := len(gs.tiles) - 1; i >= 0; i-- {
 		tile := gs.tiles[i]
 		tile.Move(fyne.NewPos(tile.pos.X, tile.pos.Y+tile.kana.Speed))
 
 		if tile.pos.Y > gs.canvasH {
-			gs.recordMiss(tile.kana.Char)
+			gs.scorer.Miss()
+			for _, char := range kanacore.SplitKana(tile.kana.Char) {
+				gs.recordMiss(char)
+			}
 			gs.missedKanas = append(gs.missedKanas, tile.kana)
 			gs.tiles = append(gs.tiles[:i], gs.tiles[i+1:]...)
 			gs.missed++
+			gs.recorder.Miss(now, tile.kana.Seq)
+			gs.recordScore()
+			if gs.mode == kanacore.ModeZen {
+				gs.reveals = append(gs.reveals, newMissReveal(tile.kana, tile.pos.X, gs.canvasH, now))
+			}
+			if gs.showHint(tile.kana.Char, now) {
+				hintChanged = true
+			}
 			gs.checkMissedLimit()
 		}
 	}
 
+	choicesChanged := gs.refreshChoices()
 	gs.buildSnapshot()
 	canvas := gs.canvas
+	inputBar := gs.inputBar
+	refreshBar := gs.mode.Timed() || gs.mode.HasGoal() || gs.ghost != nil || choicesChanged || hintChanged
+	var snap StatsSnapshot
+	if refreshBar {
+		snap = gs.snapshot()
+	}
 	gs.mu.Unlock()
 
 	if canvas != nil {
 		fyne.Do(func() { canvas.Refresh() })
 	}
+	if refreshBar && inputBar != nil {
+		fyne.Do(func() { inputBar.Update(snap) })
+	}
+}
+
+// spawnGhostTiles drops the tiles the ghost's run had spawned by now, scaled
+// to the canvas. Must be called under lock.
+func (gs *GameState) spawnGhostTiles(now time.Time) {
+	for _, e := range gs.ghost.Due(now.Sub(gs.sessionStart)) {
+		kana := kanacore.Kana{
+			Char:    e.Char,
+			Romaji:  e.Romaji,
+			Speed:   float32(e.Speed) * gs.canvasH,
+			Reverse: gs.mode.Production(),
+		}
+		kana.Seq = gs.recorder.Spawn(now, kana, e.X, e.Speed)
+		tile := newKanaTile(kana)
+		tile.Move(fyne.NewPos(float32(e.X)*gs.canvasW, 0))
+		gs.tiles = append(gs.tiles, tile)
+	}
+}
+
+// racingGhost reports whether the ghost's run is still dropping the
+// session's tiles. Must be called under lock.
+func (gs *GameState) racingGhost() bool {
+	return gs.ghost != nil && gs.ghost.Running(gs.now().Sub(gs.sessionStart))
 }
 
 func (gs *GameState) spawnKana() {
 	gs.mu.Lock()
-	if gs.over {
+	if gs.over || gs.racingGhost() {
 		gs.mu.Unlock()
 		return
 	}
@@ -255,52 +466,90 @@ func (gs *GameState) spawnKana() {
 		gs.mu.Unlock()
 		return
 	}
-	char := chars[rand.Intn(len(chars))]
+	char := chars[gs.rng.Intn(len(chars))]
+	if gs.mode == kanacore.ModeLookalike {
+		char = kanacore.NextLookalike(gs.lookalike, gs.prevLookalike, gs.rng)
+		gs.prevLookalike = char
+	}
 	romaji, _ := gs.charSet.GetRomaji(char)
 
-	speed := 3.75 + rand.Float32()*2.5
-	maxX := gs.canvasW - tileW
-	if maxX < 0 {
-		maxX = 0
+	// Word mode drops a word built only from the selected characters,
+	// falling back to a single kana when no word fits the selection.
+	if gs.mode == kanacore.ModeWords {
+		if words := kanacore.FilterWords(gs.words, chars); len(words) > 0 {
+			word := words[gs.rng.Intn(len(words))]
+			char, romaji = word.Kana, word.Romaji
+		}
 	}
-	x := rand.Float32() * maxX
 
 	kana := kanacore.Kana{
-		Char:   char,
-		Romaji: romaji,
-		Speed:  speed,
+		Char:    char,
+		Romaji:  romaji,
+		Speed:   3.75 + gs.rng.Float32()*2.5,
+		Reverse: gs.mode.Production(),
 	}
+	maxX := gs.canvasW - tileWidthFor(kana.Display())
+	if maxX < 0 {
+		maxX = 0
+	}
+	x := gs.rng.Float32() * maxX
+	kana.Seq = gs.recorder.Spawn(gs.now(), kana, float64(x/gs.canvasW), float64(kana.Speed/gs.canvasH))
+
 	tile := newKanaTile(kana)
 	tile.Move(fyne.NewPos(x, 0))
 	gs.tiles = append(gs.tiles, tile)
 
+	choicesChanged := gs.refreshChoices()
 	gs.buildSnapshot()
 	canvas := gs.canvas
+	inputBar := gs.inputBar
+	var snap StatsSnapshot
+	if choicesChanged {
+		snap = gs.snapshot()
+	}
 	gs.mu.Unlock()
 
 	if canvas != nil {
 		fyne.Do(func() { canvas.Refresh() })
 	}
+	if choicesChanged && inputBar != nil {
+		fyne.Do(func() { inputBar.Update(snap) })
+	}
 }
 
 // checkAnswer processes an input string and removes a matching tile if found.
+// When several tiles match, targetPolicy picks the one to clear.
 // Acquires the lock itself; releases before triggering canvas.Refresh() so the
 // UI updates immediately on correct answers instead of waiting for the next tick.
 func (gs *GameState) checkAnswer(input string) {
 	gs.mu.Lock()
 	matched := false
-	for i, tile := range gs.tiles {
-		if tile.kana.Romaji == input {
-			gs.tiles = append(gs.tiles[:i], gs.tiles[i+1:]...)
-			gs.score += 10
-			gs.recordCorrect(tile.kana.Char)
-			if gs.scoreLimit > 0 && gs.score >= gs.scoreLimit {
-				gs.endGame("score")
-			}
-			gs.buildSnapshot()
-			matched = true
-			break
+	gs.recorder.Submit(gs.now(), input)
+	if i := gs.targetIndex(input); i >= 0 {
+		tile := gs.tiles[i]
+		gs.tiles = append(gs.tiles[:i], gs.tiles[i+1:]...)
+		gs.scorer.Hit(tile.kana, gs.canvasH)
+		gs.score = gs.scorer.Score()
+		gs.recorder.Clear(gs.now(), tile.kana.Seq)
+		gs.recordScore()
+		for _, char := range kanacore.SplitKana(tile.kana.Char) {
+			gs.recordCorrect(char)
+		}
+		if gs.mode.UsesScoreLimit() && gs.scoreLimit > 0 && gs.score >= gs.scoreLimit {
+			gs.endGame("score")
 		}
+		if gs.goalReached() {
+			gs.endGame("goal")
+		}
+		gs.refreshChoices()
+		gs.buildSnapshot()
+		matched = true
+	} else if strings.TrimSpace(input) != "" && !gs.over {
+		gs.scorer.Wrong()
+		gs.score = gs.scorer.Score()
+		gs.recordScore()
+		gs.noteConfusion(strings.TrimSpace(input))
+		gs.observeAchievements(kanacore.EventWrong)
 	}
 	canvas := gs.canvas
 	gs.mu.Unlock()
@@ -310,10 +559,137 @@ func (gs *GameState) checkAnswer(input string) {
 	}
 }
 
+// targetIndex returns the index of the tile input should clear, or -1.
+// In production modes any romaji left in input is converted to kana first.
+// Must be called with lock held.
+func (gs *GameState) targetIndex(input string) int {
+	if gs.mode.Production() {
+		input = kanacore.FinishRomaji(strings.TrimSpace(input))
+	}
+	candidates := make([]kanacore.Kana, len(gs.tiles))
+	for i, tile := range gs.tiles {
+		candidates[i] = tile.kana
+	}
+	return kanacore.SelectTarget(candidates, input, gs.canvasH, gs.targetPolicy)
+}
+
+// answerChoice handles a click on one of the multiple-choice answers. A
+// correct pick clears the matching tile like a typed answer; a wrong pick is
+// penalised and remembered as a confusion for the tile's kana.
+func (gs *GameState) answerChoice(romaji string) {
+	gs.mu.Lock()
+	question := gs.choiceTile
+	if question == nil || gs.over {
+		gs.mu.Unlock()
+		return
+	}
+	if romaji != question.kana.Romaji {
+		gs.recorder.Submit(gs.now(), romaji)
+		gs.scorer.Wrong()
+		gs.score = gs.scorer.Score()
+		gs.recordScore()
+		gs.recordConfusion(question.kana.Char, romaji)
+		gs.observeAchievements(kanacore.EventWrong)
+		gs.mu.Unlock()
+		return
+	}
+	gs.mu.Unlock()
+	gs.checkAnswer(romaji)
+}
+
+// checkDrawing recognises a handwritten kana and answers with it like a typed
+// kana. Only characters from the selected rows are considered. A drawing that
+// resembles none of them counts as a wrong answer.
+func (gs *GameState) checkDrawing(d kanacore.Drawing) {
+	if len(d) == 0 {
+		return
+	}
+	gs.mu.Lock()
+	allowed := gs.availableCharacters()
+	gs.mu.Unlock()
+
+	answer := "?"
+	if matches := kanacore.Recognize(d, allowed); len(matches) > 0 {
+		answer = matches[0].Char
+	}
+	gs.checkAnswer(answer)
+}
+
+// refreshChoices points the multiple-choice answers at the most urgent tile,
+// drawing new answers when that tile changes. Reports whether the choices
+// changed. Must be called under lock.
+func (gs *GameState) refreshChoices() bool {
+	if !gs.mode.MultipleChoice() {
+		changed := gs.choiceTile != nil
+		gs.choiceTile, gs.choices = nil, nil
+		return changed
+	}
+	candidates := make([]kanacore.Kana, len(gs.tiles))
+	for i, tile := range gs.tiles {
+		candidates[i] = tile.kana
+	}
+	var question *KanaTile
+	if i := kanacore.MostUrgent(candidates, gs.canvasH, gs.targetPolicy); i >= 0 {
+		question = gs.tiles[i]
+	}
+	if question == gs.choiceTile {
+		return false
+	}
+	gs.choiceTile = question
+	gs.choices = nil
+	if question != nil {
+		chars := gs.availableCharacters()
+		pool := make([]string, 0, len(chars))
+		for _, char := range chars {
+			if romaji, ok := gs.charSet.GetRomaji(char); ok {
+				pool = append(pool, romaji)
+			}
+		}
+		gs.choices = kanacore.Choices(question.kana.Romaji, pool, gs.confusions[question.kana.Char], kanacore.ChoiceCount, gs.rng)
+	}
+	return true
+}
+
+// noteConfusion records a typed answer that matched no tile as a confusion
+// for the most urgent single-kana tile, when the answer is another kana's
+// romaji. Must be called under lock.
+func (gs *GameState) noteConfusion(input string) {
+	if gs.mode.Production() {
+		return
+	}
+	if _, ok := gs.charSet.GetChar(input); !ok {
+		return
+	}
+	candidates := make([]kanacore.Kana, len(gs.tiles))
+	for i, tile := range gs.tiles {
+		candidates[i] = tile.kana
+	}
+	i := kanacore.MostUrgent(candidates, gs.canvasH, gs.targetPolicy)
+	if i < 0 || len(kanacore.SplitKana(candidates[i].Char)) != 1 {
+		return
+	}
+	gs.recordConfusion(candidates[i].Char, input)
+}
+
+// recordConfusion remembers that answer was given for char. Must be called under lock.
+func (gs *GameState) recordConfusion(char, answer string) {
+	if gs.confusions[char] == nil {
+		gs.confusions[char] = make(map[string]int)
+	}
+	gs.confusions[char][answer]++
+	if gs.sessionMixups[char] == nil {
+		gs.sessionMixups[char] = make(map[string]int)
+	}
+	gs.sessionMixups[char][answer]++
+	if gs.store != nil {
+		_ = gs.store.RecordConfusion(char, answer)
+	}
+}
+
 // checkMissedLimit ends the game if misses have reached the threshold.
 // Must be called with lock held.
 func (gs *GameState) checkMissedLimit() {
-	if gs.missed >= 10 && !gs.over {
+	if gs.mode.EndsOnMisses() && gs.missed >= 10 && !gs.over {
 		gs.endGame("misses")
 	}
 }
@@ -327,6 +703,14 @@ func (gs *GameState) endGame(reason string) {
 	if gs.overReason == "" {
 		gs.overReason = reason
 	}
+	gs.replay = gs.recorder.Finish(gs.now(), gs.overReason)
+	gs.recorder = nil
+	if gs.ghost != nil {
+		gs.ghostResult = kanacore.GhostResult(gs.score, gs.ghost.Final())
+	}
+	gs.saveSession()
+	gs.savePracticeDay(gs.practice.Stop(gs.now()))
+	gs.observeAchievements(kanacore.EventSessionEnd)
 	gs.mergeSessionStats()
 
 	select {
@@ -335,34 +719,130 @@ func (gs *GameState) endGame(reason string) {
 	}
 }
 
+// saveSession persists the finished session and its score breakdown. Must be called under lock.
+func (gs *GameState) saveSession() {
+	if gs.store == nil {
+		return
+	}
+	timeLimit := gs.sessionTimeLimit()
+	switch {
+	case gs.mode == kanacore.ModeDaily:
+		// Daily challenges are compared within the day's shared sequence.
+		y, m, d := gs.sessionStart.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, gs.sessionStart.Location())
+		gs.previousBest, _ = gs.store.BestHitsSince(string(gs.mode), timeLimit, day)
+	case gs.mode.Timed():
+		gs.previousBest, _ = gs.store.BestHits(string(gs.mode), timeLimit)
+	default:
+		gs.previousBest, _ = gs.store.BestScore(string(gs.mode), timeLimit)
+	}
+	b := gs.scorer.Breakdown
+	id, err := gs.store.SaveSession(store.SessionRecord{
+		StartedAt:     gs.sessionStart,
+		EndedAt:       gs.now(),
+		Mode:          string(gs.mode),
+		TimeLimit:     timeLimit,
+		Reason:        gs.overReason,
+		Score:         gs.score,
+		Missed:        gs.missed,
+		BasePoints:    b.Base,
+		ComboPoints:   b.Combo,
+		SpeedPoints:   b.Speed,
+		PenaltyPoints: b.Penalty,
+		Hits:          b.Hits,
+		Wrong:         b.Wrong,
+		MaxCombo:      b.MaxCombo,
+	})
+	if err == nil && gs.replay != nil {
+		_ = gs.replay.Save(gs.store.ReplayPath(id))
+	}
+}
+
+// sessionTimeLimit returns the countdown or zen time goal, in seconds, that
+// sessions are compared within; 0 when the session is not timed. Must be
+// called under lock.
+func (gs *GameState) sessionTimeLimit() int {
+	switch {
+	case gs.mode.Timed():
+		return int(gs.countdown() / time.Second)
+	case gs.mode == kanacore.ModeZen && gs.zenGoal.Kind == kanacore.GoalTime:
+		return int(gs.zenGoal.Duration / time.Second)
+	}
+	return 0
+}
+
 // recordCorrect updates session stats only. Must be called under lock.
 func (gs *GameState) recordCorrect(char string) {
 	streak := gs.currentStreak[char] + 1
 	gs.currentStreak[char] = streak
 
+	tally := gs.tallies[char]
+	tally.Correct++
+	gs.tallies[char] = tally
+
 	stat := gs.sessionStats[char]
 	stat.Char = char
 	stat.CorrectCount++
 	stat.Streak = streak
+	stat.LastSeen = gs.now()
 	gs.sessionStats[char] = stat
 	gs.sessionDirty = true
 
+	gs.rowHistory.Record(char, true)
+	gs.checkAutoRegression()
+	gs.recordPractice(1)
+
 	if unlocked := gs.checkAutoProgression(); len(unlocked) > 0 {
 		gs.newlyUnlocked = append(gs.newlyUnlocked, unlocked...)
 		gs.showUnlockMessage(unlocked)
+		gs.observeAchievements(kanacore.EventUnlock)
 	}
+	gs.observeAchievements(kanacore.EventCorrect)
 }
 
 // recordMiss updates session stats + resets streak. Must be called under lock.
 func (gs *GameState) recordMiss(char string) {
 	gs.currentStreak[char] = 0
 
+	tally := gs.tallies[char]
+	tally.Missed++
+	gs.tallies[char] = tally
+
 	stat := gs.sessionStats[char]
 	stat.Char = char
 	stat.MissCount++
 	stat.Streak = 0
+	stat.LastSeen = gs.now()
 	gs.sessionStats[char] = stat
 	gs.sessionDirty = true
+
+	gs.rowHistory.Record(char, false)
+	gs.checkAutoRegression()
+	gs.recordPractice(0)
+	gs.observeAchievements(kanacore.EventMiss)
+}
+
+// statsTrack returns the statistics track the current mode practises. Must be called under lock.
+func (gs *GameState) statsTrack() store.StatsTrack {
+	if gs.mode.Production() {
+		return store.TrackProduction
+	}
+	return store.TrackRecognition
+}
+
+// loadOverallStats fills overallStats and currentStreak from the store for the
+// current track. Must be called under lock.
+func (gs *GameState) loadOverallStats() {
+	if gs.store == nil {
+		return
+	}
+	if stats, err := gs.store.TrackStatistics(gs.statsTrack()); err == nil {
+		for _, stat := range stats {
+			copied := stat
+			gs.overallStats[stat.Char] = copied
+			gs.currentStreak[stat.Char] = stat.Streak
+		}
+	}
 }
 
 // mergeSessionStats writes (baseline + session) to store. Must be called under lock.
@@ -373,7 +853,7 @@ func (gs *GameState) mergeSessionStats() {
 
 	var baseline map[string]store.KanaStats
 	if gs.store != nil {
-		stats, err := gs.store.KanaStatistics()
+		stats, err := gs.store.TrackStatistics(gs.statsTrack())
 		if err != nil {
 			// Leave sessionDirty=true so next merge retries.
 			return
@@ -393,8 +873,11 @@ func (gs *GameState) mergeSessionStats() {
 		base.CorrectCount += session.CorrectCount
 		base.MissCount += session.MissCount
 		base.Streak = gs.currentStreak[char]
+		if session.LastSeen.After(base.LastSeen) {
+			base.LastSeen = session.LastSeen
+		}
 		if gs.store != nil {
-			if err := gs.store.SaveKanaStats(char, base.CorrectCount, base.MissCount, base.Streak); err != nil {
+			if err := gs.store.SaveTrackStats(gs.statsTrack(), char, base.CorrectCount, base.MissCount, base.Streak, base.LastSeen); err != nil {
 				continue
 			}
 		}
@@ -405,11 +888,30 @@ func (gs *GameState) mergeSessionStats() {
 	gs.sessionDirty = false
 }
 
-// availableCharacters returns characters filtered by selectedRows. Must be called under lock.
+// availableCharacters returns characters filtered by selectedChars, or by
+// selectedRows when no characters are picked. Must be called under lock.
 func (gs *GameState) availableCharacters() []string {
+	if gs.mode == kanacore.ModeDrill {
+		return gs.drillChars
+	}
+	if gs.mode == kanacore.ModeLookalike {
+		return gs.lookalike
+	}
 	chars := gs.charSet.GetCharacters()
-	if len(chars) == 0 {
-		return nil
+	if len(chars) == 0 || gs.mode == kanacore.ModeDaily {
+		// The daily challenge ignores the selection so everyone plays the same kana.
+		return chars
+	}
+	if len(gs.selectedChars) > 0 {
+		filtered := make([]string, 0, len(gs.selectedChars))
+		for _, char := range chars {
+			if gs.selectedChars[char] {
+				filtered = append(filtered, char)
+			}
+		}
+		if len(filtered) > 0 {
+			return filtered
+		}
 	}
 	if len(gs.selectedRows) == 0 {
 		return chars
@@ -432,6 +934,33 @@ func (gs *GameState) availableCharacters() []string {
 	return filtered
 }
 
+// tileSelected reports whether every kana on the tile is selected, either
+// individually or through its row. Drills ignore the selection and look-alike
+// drills keep only their group. Must be called under lock.
+func (gs *GameState) tileSelected(t *KanaTile) bool {
+	if gs.mode == kanacore.ModeDrill {
+		return true
+	}
+	for _, char := range kanacore.SplitKana(t.kana.Char) {
+		if gs.mode == kanacore.ModeLookalike {
+			if !gs.lookalike.Contains(char) {
+				return false
+			}
+			continue
+		}
+		if len(gs.selectedChars) > 0 {
+			if _, known := kanacore.CharToRow[char]; known && !gs.selectedChars[char] {
+				return false
+			}
+			continue
+		}
+		if rowID, ok := kanacore.CharToRow[char]; ok && !gs.selectedRows[rowID] {
+			return false
+		}
+	}
+	return true
+}
+
 // applySelectedRows replaces the selection map.
 func (gs *GameState) applySelectedRows(rows []string) {
 	if gs.selectedRows == nil {
@@ -445,10 +974,14 @@ func (gs *GameState) applySelectedRows(rows []string) {
 	}
 }
 
-// SetSelectedRows updates selection and persists to store.
+// SetSelectedRows updates selection and persists to store. Choosing anything
+// other than the focus of an active auto-regression ends it.
 func (gs *GameState) SetSelectedRows(rows []string) {
 	gs.mu.Lock()
 	gs.applySelectedRows(rows)
+	if gs.regression != nil && !kanacore.SameRows(rows, gs.regression.Focus) {
+		gs.setRegression(nil)
+	}
 	st := gs.store
 	gs.mu.Unlock()
 	if st != nil {
@@ -456,6 +989,40 @@ func (gs *GameState) SetSelectedRows(rows []string) {
 	}
 }
 
+// applySelectedChars replaces the character selection. Must be called under lock.
+func (gs *GameState) applySelectedChars(chars []string) {
+	gs.selectedChars = make(map[string]bool, len(chars))
+	for _, char := range kanacore.NormalizeCharSelection(chars) {
+		gs.selectedChars[char] = true
+	}
+}
+
+// selectedCharList returns the picked characters in row order. Must be called under lock.
+func (gs *GameState) selectedCharList() []string {
+	chars := make([]string, 0, len(gs.selectedChars))
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			if gs.selectedChars[char] {
+				chars = append(chars, char)
+			}
+		}
+	}
+	return chars
+}
+
+// SetSelectedChars practises exactly the given characters and persists them.
+// An empty selection returns to row selection.
+func (gs *GameState) SetSelectedChars(chars []string) {
+	gs.mu.Lock()
+	gs.applySelectedChars(chars)
+	chars = gs.selectedCharList()
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveSelectedChars(chars)
+	}
+}
+
 // SelectedRowIDs returns the currently selected row IDs.
 func (gs *GameState) SelectedRowIDs() []string {
 	gs.mu.Lock()
@@ -483,6 +1050,229 @@ func (gs *GameState) SetAutoProgress(enabled bool) {
 	}
 }
 
+// SetGhostRace sets and persists whether sessions race a ghost of the best
+// recorded run, starting with the next session.
+func (gs *GameState) SetGhostRace(enabled bool) {
+	gs.mu.Lock()
+	gs.ghostRace = enabled
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveGhostRace(enabled)
+	}
+}
+
+// SetTargetPolicy sets and persists the duplicate-tile targeting policy.
+func (gs *GameState) SetTargetPolicy(policy kanacore.TargetPolicy) {
+	gs.mu.Lock()
+	gs.targetPolicy = policy
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveTargetPolicy(string(policy))
+	}
+}
+
+// SetGameMode selects the session rules and countdown length, and persists both.
+func (gs *GameState) SetGameMode(mode kanacore.GameMode, limit time.Duration) {
+	limit = kanacore.NormalizeTimeLimit(limit)
+	gs.mu.Lock()
+	gs.switchMode(mode)
+	gs.timeLimit = limit
+	gs.reseed()
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveGameMode(string(mode))
+		_ = st.SaveTimeLimit(int(limit / time.Second))
+	}
+}
+
+// switchMode changes the game mode. Moving between recognition and production
+// practice flushes the session under the old track and reloads overall stats
+// for the new one. Must be called under lock.
+func (gs *GameState) switchMode(mode kanacore.GameMode) {
+	if mode.Production() != gs.mode.Production() {
+		gs.mergeSessionStats()
+		gs.mode = mode
+		gs.overallStats = make(map[string]store.KanaStats)
+		gs.currentStreak = make(map[string]int)
+		gs.loadOverallStats()
+	}
+	gs.mode = mode
+}
+
+// sessionGoal returns the goal that ends the session, if the mode has one.
+// Must be called under lock.
+func (gs *GameState) sessionGoal() (kanacore.Goal, bool) {
+	switch gs.mode {
+	case kanacore.ModeZen:
+		return gs.zenGoal, true
+	case kanacore.ModeDrill:
+		return kanacore.DrillGoal(len(gs.drillChars)), true
+	case kanacore.ModeLookalike:
+		return kanacore.LookalikeGoal, true
+	}
+	return kanacore.Goal{}, false
+}
+
+// goalReached reports whether the session has met its goal. Must be called under lock.
+func (gs *GameState) goalReached() bool {
+	goal, ok := gs.sessionGoal()
+	return ok && goal.Reached(gs.scorer.Breakdown.Hits, gs.now().Sub(gs.sessionStart))
+}
+
+// StartDrill switches to a drill of the characters missed in the session that
+// just ended, ignoring row selection, and resets for it. The caller must call
+// Start() as after Reset(). Reports false, changing nothing, when nothing was missed.
+func (gs *GameState) StartDrill() bool {
+	gs.mu.Lock()
+	chars := kanacore.DrillCharacters(gs.missedKanas)
+	if len(chars) == 0 {
+		gs.mu.Unlock()
+		return false
+	}
+	gs.drillBefore = make(map[string]kanacore.CharTally, len(chars))
+	for _, char := range chars {
+		gs.drillBefore[char] = gs.tallies[char]
+	}
+	gs.drillChars = chars
+	if gs.mode != kanacore.ModeDrill {
+		gs.drillReturn = gs.mode
+	}
+	// Drills are typed as romaji, so they count towards recognition stats.
+	gs.switchMode(kanacore.ModeDrill)
+	gs.mu.Unlock()
+
+	gs.Reset()
+	return true
+}
+
+// EndDrill restores the mode that was active before a drill. Call it before
+// Reset() when playing again after a drill.
+func (gs *GameState) EndDrill() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.mode == kanacore.ModeDrill {
+		gs.switchMode(gs.drillReturn)
+	}
+}
+
+// lookalikeGroups returns the curated look-alike groups plus pairs from the
+// player's confusion history. Must be called under lock.
+func (gs *GameState) lookalikeGroups() []kanacore.LookalikeGroup {
+	return kanacore.ExtendLookalikes(kanacore.LookalikeGroups, gs.confusions)
+}
+
+// resolveLookalike picks the group look-alike drills use. Must be called under lock.
+func (gs *GameState) resolveLookalike() {
+	groups := gs.lookalikeGroups()
+	if g, ok := kanacore.FindLookalikeGroup(groups, gs.lookalikeChoice); ok {
+		gs.lookalike = g
+		return
+	}
+	gs.lookalike = kanacore.MostConfusedLookalike(groups, gs.confusions)
+}
+
+// SetLookalikeGroup sets and persists the group drilled in look-alike mode.
+// An empty ID drills whichever group the player confuses most.
+func (gs *GameState) SetLookalikeGroup(id string) {
+	gs.mu.Lock()
+	gs.lookalikeChoice = id
+	gs.resolveLookalike()
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveLookalikeGroup(id)
+	}
+}
+
+// SetZenGoal sets and persists the goal that ends a zen session.
+func (gs *GameState) SetZenGoal(goal kanacore.Goal) {
+	gs.mu.Lock()
+	gs.zenGoal = goal
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveZenGoal(goal.String())
+	}
+}
+
+// reseed starts the session's tile sequence, the date's sequence in the
+// daily challenge, the ghost's when racing one and a fresh one otherwise,
+// and a new recording of it. Must be called under lock.
+func (gs *GameState) reseed() {
+	gs.seed = kanacore.SessionSeed(gs.mode, gs.sessionStart)
+	gs.ghost = gs.loadGhost()
+	if gs.ghost != nil {
+		gs.seed = gs.ghost.Replay.Seed
+	}
+	gs.rng = kanacore.NewRand(gs.seed)
+	gs.recorder = kanacore.NewRecorder(gs.mode, gs.seed, gs.sessionStart)
+}
+
+// loadGhost returns the recording of the best run in the current mode to
+// race, or nil when ghost races are off or no run was recorded. Drills and
+// daily challenges have their own tile sequences and are never raced. Must
+// be called under lock.
+func (gs *GameState) loadGhost() *kanacore.Ghost {
+	if !gs.ghostRace || gs.store == nil || gs.mode == kanacore.ModeDrill || gs.mode == kanacore.ModeDaily {
+		return nil
+	}
+	ids, err := gs.store.BestSessionIDs(string(gs.mode), gs.sessionTimeLimit())
+	if err != nil {
+		return nil
+	}
+	// Sessions played before recording began have no replay; race the best
+	// one that has.
+	for _, id := range ids {
+		if r, err := kanacore.LoadReplay(gs.store.ReplayPath(id)); err == nil {
+			return kanacore.NewGhost(r)
+		}
+	}
+	return nil
+}
+
+// now reads the game's clock.
+func (gs *GameState) now() time.Time {
+	return gs.clock.Now()
+}
+
+// recordScore adds the score and miss count to the session's recording.
+// Must be called under lock.
+func (gs *GameState) recordScore() {
+	gs.recorder.Score(gs.now(), gs.score, gs.missed)
+}
+
+// noteInput records the entry's text in the session's recording.
+func (gs *GameState) noteInput(text string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.recorder.Input(gs.now(), text)
+}
+
+// LastReplay returns the recording of the session that just ended, or nil.
+func (gs *GameState) LastReplay() *kanacore.Replay {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.replay
+}
+
+// countdown returns the countdown length of the current timed session. Must
+// be called under lock.
+func (gs *GameState) countdown() time.Duration {
+	return gs.mode.Countdown(gs.timeLimit)
+}
+
+// timeRemaining returns how long is left on the countdown. Must be called under lock.
+func (gs *GameState) timeRemaining() time.Duration {
+	remaining := gs.countdown() - gs.now().Sub(gs.sessionStart)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // SetScoreLimit sets and persists the score limit.
 func (gs *GameState) SetScoreLimit(limit int) {
 	if limit < 0 {
@@ -497,38 +1287,30 @@ func (gs *GameState) SetScoreLimit(limit int) {
 	}
 }
 
-// checkAutoProgression unlocks the next row when selected rows are all mastered.
-// Must be called under lock. Returns the IDs unlocked.
+// checkAutoProgression unlocks the next lesson of the active curriculum once
+// its unlock rule is met. Nothing is unlocked while hand-picked characters
+// that are not whole lessons or rows are active, or while an auto-regression
+// has narrowed the rows. Must be called under lock. Returns the IDs unlocked.
 func (gs *GameState) checkAutoProgression() []string {
-	if !gs.autoProgress {
+	if !gs.autoProgress || gs.regression != nil {
 		return nil
 	}
-
-	var nextRow *kanacore.KanaRow
-	for i := range kanacore.AllKanaRows {
-		row := kanacore.AllKanaRows[i]
-		if !gs.selectedRows[row.ID] {
-			nextRow = &row
-			break
-		}
-	}
-	if nextRow == nil {
+	if len(gs.selectedChars) > 0 && !gs.curriculum.Covers(gs.selectedChars) {
 		return nil
 	}
 
-	allMastered := true
-	for _, row := range kanacore.AllKanaRows {
-		if !gs.selectedRows[row.ID] {
-			continue
-		}
-		if !gs.isRowMastered(row) {
-			allMastered = false
-			break
-		}
+	active := gs.activeChars()
+	lesson, ok := gs.curriculum.Next(active, gs.masteryPolicy, gs.charRecord, gs.now())
+	if !ok {
+		return nil
 	}
 
-	if allMastered {
-		gs.selectedRows[nextRow.ID] = true
+	// Lessons made of whole rows extend the row selection; any other lesson
+	// switches to practising the unlocked characters.
+	if rows, whole := lesson.Rows(); whole && len(gs.selectedChars) == 0 {
+		for _, id := range rows {
+			gs.selectedRows[id] = true
+		}
 		if gs.store != nil {
 			ids := make([]string, 0, len(gs.selectedRows))
 			for id, ok := range gs.selectedRows {
@@ -538,68 +1320,417 @@ func (gs *GameState) checkAutoProgression() []string {
 			}
 			_ = gs.store.SaveSelectedRows(ids)
 		}
-		return []string{nextRow.ID}
+	} else {
+		chars := append([]string(nil), lesson.Chars...)
+		for char := range active {
+			chars = append(chars, char)
+		}
+		gs.applySelectedChars(chars)
+		if gs.store != nil {
+			_ = gs.store.SaveSelectedChars(gs.selectedCharList())
+		}
 	}
+	return []string{lesson.ID}
+}
 
-	return nil
+// activeChars returns the characters practised under the current selection.
+// Must be called under lock.
+func (gs *GameState) activeChars() map[string]bool {
+	active := make(map[string]bool)
+	if len(gs.selectedChars) > 0 {
+		for char := range gs.selectedChars {
+			active[char] = true
+		}
+		return active
+	}
+	for _, row := range kanacore.AllKanaRows {
+		if len(gs.selectedRows) == 0 || gs.selectedRows[row.ID] {
+			for _, char := range row.Characters {
+				active[char] = true
+			}
+		}
+	}
+	return active
 }
 
-// isRowMastered returns true when at least 80% of the row's characters have a
-// combined (overall+session) correct count of 3 or more.
-func (gs *GameState) isRowMastered(row kanacore.KanaRow) bool {
-	if len(row.Characters) == 0 {
-		return true
+// NeedsPlacement reports whether this is a first launch: no placement test
+// was taken or skipped and there are no statistics yet.
+func (gs *GameState) NeedsPlacement() bool {
+	if gs.store == nil {
+		return false
+	}
+	if placement, err := gs.store.Placement(); err != nil || !placement.At.IsZero() {
+		return false
+	}
+	stats, err := gs.store.TrackStatistics(store.TrackRecognition)
+	return err == nil && len(stats) == 0
+}
+
+// ApplyPlacement selects the rows the placement result suggests, seeds
+// statistics for kana that have none and records the placement.
+func (gs *GameState) ApplyPlacement(result kanacore.PlacementResult) {
+	rows := result.Rows()
+	gs.mu.Lock()
+	gs.applySelectedRows(rows)
+	gs.applySelectedChars(nil)
+	baseline := result.Baseline(gs.masteryPolicy, gs.now())
+	st := gs.store
+	gs.mu.Unlock()
+
+	if st != nil {
+		existing, err := st.TrackStatistics(store.TrackRecognition)
+		if err == nil {
+			for char, r := range baseline {
+				if _, ok := existing[char]; ok {
+					continue
+				}
+				_ = st.SaveTrackStats(store.TrackRecognition, char, r.Correct, r.Missed, r.Streak, r.LastSeen)
+			}
+		}
+		_ = st.SaveSelectedRows(rows)
+		_ = st.SaveSelectedChars(nil)
+		_ = st.SavePlacement(store.Placement{At: gs.now(), Known: result.Known})
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.statsTrack() != store.TrackRecognition {
+		return
+	}
+	for char, r := range baseline {
+		if _, ok := gs.overallStats[char]; ok {
+			continue
+		}
+		gs.overallStats[char] = store.KanaStats{
+			Char:         char,
+			CorrectCount: r.Correct,
+			MissCount:    r.Missed,
+			Streak:       r.Streak,
+			LastSeen:     r.LastSeen,
+		}
+		gs.currentStreak[char] = r.Streak
+	}
+}
+
+// SkipPlacement records that the placement test was declined.
+func (gs *GameState) SkipPlacement() {
+	if gs.store != nil {
+		_ = gs.store.SavePlacement(store.Placement{At: gs.now(), Skipped: true})
+	}
+}
+
+// SetCurriculum makes the curriculum with the given ID drive auto-progression
+// and persists the choice. Unknown IDs select the default curriculum.
+func (gs *GameState) SetCurriculum(id string) {
+	gs.mu.Lock()
+	c, ok := kanacore.FindCurriculum(gs.curricula, id)
+	if !ok {
+		c = kanacore.DefaultCurriculum()
+	}
+	gs.curriculum = c
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveCurriculum(c.ID)
+	}
+}
+
+// checkAutoRegression narrows the rows to a struggling row plus the last
+// mastered one when the struggling row's recent accuracy collapses, and
+// restores the previous rows once it recovers. Must be called under lock.
+func (gs *GameState) checkAutoRegression() {
+	if !gs.autoProgress || len(gs.selectedChars) > 0 {
+		return
+	}
+	if gs.mode == kanacore.ModeDrill || gs.mode == kanacore.ModeLookalike {
+		return
+	}
+	if gs.regression != nil {
+		if gs.regression.Recovered(gs.rowHistory) {
+			label := gs.regression.RowLabel()
+			gs.applySelectedRows(gs.regression.Restore)
+			if gs.store != nil {
+				_ = gs.store.SaveSelectedRows(gs.regression.Restore)
+			}
+			gs.setRegression(nil)
+			gs.showRegressionMessage(label + " recovered, all rows are back")
+		}
+		return
 	}
 
-	masteredCount := 0
+	selected := make([]string, 0, len(gs.selectedRows))
+	for id, ok := range gs.selectedRows {
+		if ok {
+			selected = append(selected, id)
+		}
+	}
+	r, ok := kanacore.DetectRegression(gs.rowHistory, selected, gs.isRowMastered, gs.now())
+	if !ok {
+		return
+	}
+	gs.applySelectedRows(r.Focus)
+	if gs.store != nil {
+		_ = gs.store.SaveSelectedRows(r.Focus)
+	}
+	gs.setRegression(&r)
+	gs.showRegressionMessage("Focusing on " + r.RowLabel() + ": " + r.Reason)
+}
+
+// setRegression sets and persists the active auto-regression. Must be called under lock.
+func (gs *GameState) setRegression(r *kanacore.Regression) {
+	gs.regression = r
+	if gs.store == nil {
+		return
+	}
+	if r == nil {
+		_ = gs.store.SaveRegression("")
+		return
+	}
+	_ = gs.store.SaveRegression(r.String())
+}
+
+// isRowMastered reports whether enough of the row's characters are green
+// under the mastery policy. Must be called under lock.
+func (gs *GameState) isRowMastered(row kanacore.KanaRow) bool {
+	records := make([]kanacore.CharRecord, 0, len(row.Characters))
 	for _, char := range row.Characters {
-		total := gs.overallStats[char].CorrectCount + gs.sessionStats[char].CorrectCount
-		if total >= 3 {
-			masteredCount++
+		records = append(records, gs.charRecord(char))
+	}
+	return gs.masteryPolicy.RowMastered(records, gs.now())
+}
+
+// charRecord combines a character's overall and session statistics. Must be
+// called under lock.
+func (gs *GameState) charRecord(char string) kanacore.CharRecord {
+	overall, session := gs.overallStats[char], gs.sessionStats[char]
+	record := kanacore.CharRecord{
+		Correct:  overall.CorrectCount + session.CorrectCount,
+		Missed:   overall.MissCount + session.MissCount,
+		LastSeen: overall.LastSeen,
+	}
+	// Characters not played since the stats were loaded keep their saved streak.
+	if streak, ok := gs.currentStreak[char]; ok {
+		record.Streak = streak
+	} else {
+		record.Streak = overall.Streak
+	}
+	if session.LastSeen.After(record.LastSeen) {
+		record.LastSeen = session.LastSeen
+	}
+	return record
+}
+
+// masteryLevels grades every kana under the mastery policy. Must be called under lock.
+func (gs *GameState) masteryLevels() map[string]kanacore.MasteryLevel {
+	now := gs.now()
+	levels := make(map[string]kanacore.MasteryLevel, len(kanacore.CharToRow))
+	for char := range kanacore.CharToRow {
+		levels[char] = gs.masteryPolicy.Level(gs.charRecord(char), now)
+	}
+	return levels
+}
+
+// SetMasteryPolicy sets and persists the rules for mastering a character.
+func (gs *GameState) SetMasteryPolicy(policy kanacore.MasteryPolicy) {
+	gs.mu.Lock()
+	gs.masteryPolicy = policy
+	st := gs.store
+	gs.mu.Unlock()
+	if st != nil {
+		_ = st.SaveMasteryPolicy(policy.String())
+	}
+}
+
+// showUnlockMessage composes an "unlocked" notification, with the lesson's
+// intro when there is one. Must be called under lock.
+func (gs *GameState) showUnlockMessage(lessonIDs []string) {
+	if len(lessonIDs) == 0 {
+		return
+	}
+
+	titles := make([]string, 0, len(lessonIDs))
+	intro := ""
+	for _, id := range lessonIDs {
+		if lesson, ok := gs.curriculum.Lesson(id); ok {
+			titles = append(titles, lesson.Title)
+			intro = lesson.Intro
+		}
+	}
+
+	if len(titles) == 1 {
+		gs.unlockMessage = "New lesson unlocked: " + titles[0]
+		if intro != "" {
+			gs.unlockMessage += "\n" + intro
+		}
+	} else if len(titles) > 1 {
+		gs.unlockMessage = "New lessons unlocked: " + strings.Join(titles, ", ")
+	}
+	gs.unlockAt = gs.now()
+}
+
+// showRegressionMessage announces an auto-regression change the way unlocks
+// are announced. Must be called under lock.
+func (gs *GameState) showRegressionMessage(message string) {
+	gs.unlockMessage = message
+	gs.unlockAt = gs.now()
+}
+
+// loadPracticeLog builds the practice log from the stored daily goal and totals.
+func loadPracticeLog(st *store.Store) *kanacore.PracticeLog {
+	goal := kanacore.DefaultDailyGoal
+	if stored, err := st.DailyGoal(); err == nil {
+		goal = kanacore.ParseDailyGoal(stored)
+	}
+	totals, _ := st.DailyTotals()
+	days := make([]kanacore.DayTotal, len(totals))
+	for i, d := range totals {
+		days[i] = kanacore.DayTotal{Day: d.Day, Correct: d.Correct, Played: d.Played, GoalMet: d.GoalMet}
+	}
+	return kanacore.NewPracticeLog(goal, days)
+}
+
+// recordPractice credits an answer or miss to today's practice, announcing
+// the daily goal when it is reached. Caller must hold mu.
+func (gs *GameState) recordPractice(correct int) {
+	now := gs.now()
+	wasMet := gs.practice.Today(now).GoalMet
+	day := gs.practice.Record(now, correct)
+	gs.savePracticeDay(day)
+	if day.GoalMet && !wasMet {
+		current, _ := gs.practice.Streaks(now)
+		gs.announce(fmt.Sprintf("🎯 Daily goal reached! Streak: %d %s", current, kanacore.DayWord(current)))
+	}
+}
+
+// observeAchievements passes a game event to the achievement tracker, saving
+// and announcing the badges it earns. Caller must hold mu.
+func (gs *GameState) observeAchievements(kind kanacore.EventKind) {
+	now := gs.now()
+	streak, _ := gs.practice.Streaks(now)
+	earned := gs.achievements.Observe(kanacore.AchievementEvent{
+		Kind:      kind,
+		At:        now,
+		Levels:    gs.masteryLevels(),
+		DayStreak: streak,
+	})
+	for _, a := range earned {
+		if gs.store != nil {
+			_ = gs.store.SaveAchievement(a.ID, now)
+		}
+		gs.sessionBadges = append(gs.sessionBadges, a)
+		gs.announce("🏅 Badge earned: " + a.Title)
+	}
+}
+
+// Achievements returns when each earned achievement was earned, keyed by ID.
+func (gs *GameState) Achievements() map[string]time.Time {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	earned := make(map[string]time.Time)
+	for _, a := range kanacore.Achievements {
+		if at, ok := gs.achievements.Earned(a.ID); ok {
+			earned[a.ID] = at
 		}
 	}
+	return earned
+}
 
-	threshold := int(float64(len(row.Characters)) * 0.8)
-	if threshold == 0 {
-		threshold = 1
+// announce shows a message in the stats panel below any message that is
+// still showing. Caller must hold mu.
+func (gs *GameState) announce(message string) {
+	now := gs.now()
+	if gs.unlockMessage != "" && now.Sub(gs.unlockAt) < unlockMessageDuration {
+		message = gs.unlockMessage + "\n" + message
 	}
-	return masteredCount >= threshold
+	gs.unlockMessage = message
+	gs.unlockAt = now
 }
 
-// showUnlockMessage composes an "unlocked" notification. Must be called under lock.
-func (gs *GameState) showUnlockMessage(rowIDs []string) {
-	if len(rowIDs) == 0 {
+// savePracticeDay persists a day's totals. Caller must hold mu.
+func (gs *GameState) savePracticeDay(d kanacore.DayTotal) {
+	if gs.store == nil {
 		return
 	}
+	_ = gs.store.SaveDailyTotal(store.DailyTotal{Day: d.Day, Correct: d.Correct, Played: d.Played, GoalMet: d.GoalMet})
+}
 
-	labels := make([]string, 0, len(rowIDs))
-	for _, id := range rowIDs {
-		for _, row := range kanacore.AllKanaRows {
-			if row.ID == id {
-				labels = append(labels, row.Label)
-				break
+// SetDailyGoal updates and persists the daily practice goal.
+func (gs *GameState) SetDailyGoal(goal kanacore.Goal) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.savePracticeDay(gs.practice.SetGoal(goal, gs.now()))
+	if gs.store != nil {
+		_ = gs.store.SaveDailyGoal(goal.String())
+	}
+}
+
+// practiceCalendar returns the heat level of each day in the practice
+// calendar's weeks.
+func (gs *GameState) practiceCalendar(now time.Time) ([][7]string, map[string]int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	weeks := kanacore.CalendarWeeks(now, kanacore.CalendarWeekCount)
+	levels := make(map[string]int)
+	for _, week := range weeks {
+		for _, key := range week {
+			if key != "" {
+				levels[key] = gs.practice.HeatLevel(key)
 			}
 		}
 	}
+	return weeks, levels
+}
 
-	if len(labels) == 1 {
-		gs.unlockMessage = "New row unlocked: " + labels[0]
-	} else if len(labels) > 1 {
-		gs.unlockMessage = "New rows unlocked: " + labels[0]
-		for i := 1; i < len(labels); i++ {
-			gs.unlockMessage += ", " + labels[i]
+// showHint sets the memory hint for a missed kana or word, reporting whether
+// there is one. Caller must hold mu.
+func (gs *GameState) showHint(text string, now time.Time) bool {
+	char, hint := kanacore.KanaMnemonic(text, gs.mnemonics)
+	if hint == "" {
+		return false
+	}
+	romaji, _ := gs.charSet.GetRomaji(char)
+	gs.hint = fmt.Sprintf("%s (%s): %s", char, romaji, hint)
+	gs.hintAt = now
+	return true
+}
+
+// Mnemonic returns the memory hint shown for a kana: the player's own if set,
+// the bundled one otherwise.
+func (gs *GameState) Mnemonic(char string) string {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return kanacore.Mnemonic(char, gs.mnemonics)
+}
+
+// SetMnemonic saves the player's own memory hint for a kana. An empty hint
+// restores the bundled one.
+func (gs *GameState) SetMnemonic(char, hint string) error {
+	hint = strings.TrimSpace(hint)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.store != nil {
+		if err := gs.store.SaveMnemonic(char, hint); err != nil {
+			return err
 		}
 	}
-	gs.unlockAt = time.Now()
+	if hint == "" {
+		delete(gs.mnemonics, char)
+	} else {
+		gs.mnemonics[char] = hint
+	}
+	return nil
 }
 
 // buildSnapshot rebuilds the atomic snapshot of canvas objects.
 // Must be called under lock.
 func (gs *GameState) buildSnapshot() {
-	objs := make([]fyne.CanvasObject, 0, len(gs.tiles)*3)
+	objs := make([]fyne.CanvasObject, 0, len(gs.tiles)*3+len(gs.reveals))
 	for _, tile := range gs.tiles {
 		objs = append(objs, tile.Objects()...)
 	}
+	for _, r := range gs.reveals {
+		objs = append(objs, r.text)
+	}
 	gs.objectSnapshot.Store(objs)
 }
 
@@ -609,18 +1740,70 @@ func (gs *GameState) snapshot() StatsSnapshot {
 	for k, v := range gs.sessionStats {
 		sessionCopy[k] = v
 	}
+	goal, _ := gs.sessionGoal()
+	var drillResults []kanacore.DrillComparison
+	if gs.mode == kanacore.ModeDrill {
+		drillResults = kanacore.CompareDrill(gs.drillChars, gs.drillBefore, gs.tallies)
+	}
 	rowsCopy := make(map[string]bool, len(gs.selectedRows))
 	for k, v := range gs.selectedRows {
 		rowsCopy[k] = v
 	}
+	var regression string
+	if gs.regression != nil {
+		regression = gs.regression.Reason
+	}
+	var nextLesson string
+	if gs.autoProgress && gs.regression == nil {
+		if lesson, ok := gs.curriculum.Upcoming(gs.activeChars()); ok {
+			nextLesson = gs.curriculum.Name + " | next: " + lesson.Title
+		}
+	}
+	now := gs.now()
+	dayStreak, longestStreak := gs.practice.Streaks(now)
+	var ghostScore, ghostFinal int
+	if gs.ghost != nil {
+		ghostScore = gs.ghost.Score(now.Sub(gs.sessionStart))
+		ghostFinal = gs.ghost.Final()
+	}
 	return StatsSnapshot{
 		SessionStats:  sessionCopy,
 		SelectedRows:  rowsCopy,
+		SelectedChars: gs.selectedCharList(),
+		Levels:        gs.masteryLevels(),
 		MissedKanas:   append([]kanacore.Kana{}, gs.missedKanas...),
 		Score:         gs.score,
 		ScoreLimit:    gs.scoreLimit,
+		Mode:          gs.mode,
+		TimeLimit:     gs.countdown(),
+		TimeLeft:      gs.timeRemaining(),
+		ZenGoal:       gs.zenGoal,
+		Goal:          goal,
+		DrillResults:  drillResults,
+		Lookalike:     gs.lookalike,
+		Mixups:        kanacore.LookalikeMixups(gs.lookalikeGroups(), gs.sessionMixups),
+		Elapsed:       gs.now().Sub(gs.sessionStart),
+		SessionDay:    kanacore.DayKey(gs.sessionStart),
+		PreviousBest:  gs.previousBest,
+		HasStore:      gs.store != nil,
+		Breakdown:     gs.scorer.Breakdown,
+		Combo:         gs.scorer.Combo,
+		Multiplier:    gs.scorer.Multiplier(),
 		Missed:        gs.missed,
 		UnlockMessage: gs.unlockMessage,
 		UnlockAt:      gs.unlockAt,
+		Hint:          gs.hint,
+		DailyGoal:     gs.practice.Goal,
+		DailyProgress: gs.practice.Progress(now),
+		DayStreak:     dayStreak,
+		LongestStreak: longestStreak,
+		Badges:        append([]kanacore.Achievement(nil), gs.sessionBadges...),
+		Regression:    regression,
+		NextLesson:    nextLesson,
+		Choices:       append([]string(nil), gs.choices...),
+		Racing:        gs.ghost != nil,
+		GhostScore:    ghostScore,
+		GhostFinal:    ghostFinal,
+		GhostResult:   gs.ghostResult,
 	}
 }
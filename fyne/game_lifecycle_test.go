@@ -2,10 +2,14 @@ package main
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/test"
+	"kana/kanacore"
+	"kana/netplay"
 	"kana/store"
 )
 
@@ -22,7 +26,7 @@ func TestMergeSessionStatsRoundTrip(t *testing.T) {
 	}
 	t.Cleanup(func() { _ = st.Close() })
 
-	gs := NewGameState(st)
+	gs := NewGameState(st, nil)
 
 	gs.mu.Lock()
 	gs.recordCorrect("か")
@@ -86,6 +90,9 @@ func TestMergeSessionStatsRoundTrip(t *testing.T) {
 	if got := persisted2["か"].CorrectCount; got != 4 {
 		t.Errorf("after second merge: persisted か.CorrectCount = %d, want 4 (double-count regression?)", got)
 	}
+	if seen := persisted2["か"].LastSeen; time.Since(seen) > time.Minute {
+		t.Errorf("persisted か.LastSeen = %v, want the time of the last answer", seen)
+	}
 }
 
 // TestGameStateLifecycle verifies Start/Reset/Stop can be called in sequence
@@ -108,6 +115,45 @@ func TestGameStateLifecycle(t *testing.T) {
 	gs.Stop()
 }
 
+// TestResetKeepsMasteryGrades verifies that playing again grades kana on
+// their saved streaks rather than treating every streak as broken.
+func TestResetKeepsMasteryGrades(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	now := time.Now()
+	if err := st.SaveTrackStats(store.TrackRecognition, "あ", 5, 1, 5, now); err != nil {
+		t.Fatal(err)
+	}
+
+	gs := NewGameState(st, nil)
+	gs.mu.Lock()
+	gs.recordMiss("い")
+	gs.mu.Unlock()
+	gs.Reset()
+
+	gs.mu.Lock()
+	levels := gs.masteryLevels()
+	gs.mu.Unlock()
+	if levels["あ"] != kanacore.LevelGreen {
+		t.Errorf("expected あ to stay green after Reset, got %s", levels["あ"])
+	}
+	if levels["い"] != kanacore.LevelRed {
+		t.Errorf("expected the missed い to be red, got %s", levels["い"])
+	}
+
+	// Without a current streak the saved one counts.
+	fresh := newTestState()
+	fresh.overallStats["う"] = store.KanaStats{Char: "う", CorrectCount: 5, Streak: 5, LastSeen: now}
+	if level := fresh.masteryLevels()["う"]; level != kanacore.LevelGreen {
+		t.Errorf("expected う to be graded on its saved streak, got %s", level)
+	}
+}
+
 // TestResetClosesEventCh verifies that Reset closes the event channel so any
 // watcher goroutine ranging over it exits cleanly.
 func TestResetClosesEventCh(t *testing.T) {
@@ -152,7 +198,8 @@ func TestCheckAutoProgressionUnlocksNextRow(t *testing.T) {
 	gs.selectedRows = map[string]bool{"vowels": true}
 
 	for _, char := range []string{"あ", "い", "う", "え", "お"} {
-		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3}
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+		gs.currentStreak[char] = 3
 	}
 
 	unlocked := gs.checkAutoProgression()
@@ -176,7 +223,8 @@ func TestCheckAutoProgressionNoUnlockWhenNotAllMastered(t *testing.T) {
 	gs.selectedRows = map[string]bool{"vowels": true}
 
 	for _, char := range []string{"あ", "い", "う"} {
-		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3}
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+		gs.currentStreak[char] = 3
 	}
 
 	unlocked := gs.checkAutoProgression()
@@ -184,3 +232,486 @@ func TestCheckAutoProgressionNoUnlockWhenNotAllMastered(t *testing.T) {
 		t.Errorf("expected no unlock at 60%%, got %d rows: %v", len(unlocked), unlocked)
 	}
 }
+
+// TestCheckAutoProgressionIgnoresFrequentlyMissedRow verifies that correct
+// answers alone do not master a row whose characters are often missed.
+func TestCheckAutoProgressionIgnoresFrequentlyMissedRow(t *testing.T) {
+	test.NewApp()
+	gs := newTestState()
+	gs.autoProgress = true
+	gs.selectedRows = map[string]bool{"vowels": true}
+
+	for _, char := range []string{"あ", "い", "う", "え", "お"} {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, MissCount: 20, Streak: 3}
+		gs.currentStreak[char] = 3
+	}
+
+	if unlocked := gs.checkAutoProgression(); len(unlocked) != 0 {
+		t.Errorf("expected no unlock for a row missed 20 times per kana, got %v", unlocked)
+	}
+	if level := gs.masteryLevels()["あ"]; level != kanacore.LevelRed {
+		t.Errorf("expected あ to be red, got %s", level)
+	}
+}
+
+func TestAutoRegressionPersistsAcrossRestarts(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	gs := NewGameState(st, nil)
+	gs.SetAutoProgress(true)
+	gs.SetSelectedRows([]string{"vowels", "k"})
+	gs.mu.Lock()
+	for i := 0; i < 6; i++ {
+		gs.recordMiss("き")
+	}
+	gs.mu.Unlock()
+
+	reloaded := NewGameState(st, nil)
+	if reloaded.regression == nil || reloaded.regression.Reason == "" {
+		t.Fatalf("expected the regression and its reason to be restored, got %+v", reloaded.regression)
+	}
+	if rows := reloaded.SelectedRowIDs(); len(rows) != 1 || rows[0] != "k" {
+		t.Errorf("expected the focused rows to be restored, got %v", rows)
+	}
+
+	reloaded.SetSelectedRows(kanacore.DefaultRowIDs())
+	if value, _ := st.Regression(); value != "" {
+		t.Errorf("expected choosing rows to end the regression, got %q", value)
+	}
+}
+
+func TestCheckAutoProgressionFollowsMixedCurriculum(t *testing.T) {
+	test.NewApp()
+	gs := newTestState()
+	gs.autoProgress = true
+	c, err := kanacore.ParseCurriculum("mixed", `
+lesson: first
+chars: の に
+lesson: second Second lesson
+chars: あ か
+intro: Hello
+lesson: third
+chars: さ`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs.curriculum = c
+	gs.applySelectedChars([]string{"の", "に"})
+	for _, char := range []string{"の", "に"} {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+		gs.currentStreak[char] = 3
+	}
+
+	unlocked := gs.checkAutoProgression()
+	if len(unlocked) != 1 || unlocked[0] != "second" {
+		t.Fatalf("expected the second lesson to unlock, got %v", unlocked)
+	}
+	if got := gs.selectedCharList(); len(got) != 4 {
+		t.Errorf("expected the lesson's characters to join the selection, got %v", got)
+	}
+	gs.showUnlockMessage(unlocked)
+	if gs.unlockMessage != "New lesson unlocked: Second lesson\nHello" {
+		t.Errorf("unexpected unlock message %q", gs.unlockMessage)
+	}
+	if unlocked := gs.checkAutoProgression(); len(unlocked) != 0 {
+		t.Errorf("expected the new lesson to need practice first, got %v", unlocked)
+	}
+
+	gs.applySelectedChars([]string{"の", "あ"})
+	for _, char := range []string{"の", "あ"} {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+		gs.currentStreak[char] = 3
+	}
+	if unlocked := gs.checkAutoProgression(); len(unlocked) != 0 {
+		t.Errorf("expected hand-picked characters to pause the curriculum, got %v", unlocked)
+	}
+}
+
+func TestCheckAutoProgressionKeepsUnlockingLessonsAfterRows(t *testing.T) {
+	test.NewApp()
+	gs := newTestState()
+	gs.autoProgress = true
+	c, ok := kanacore.FindCurriculum(kanacore.DefaultCurricula(), "common")
+	if !ok {
+		t.Fatal("expected the common curriculum to be bundled")
+	}
+	gs.curriculum = c
+	gs.selectedRows = map[string]bool{"vowels": true}
+
+	master := func() {
+		for char := range gs.activeChars() {
+			gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+			gs.currentStreak[char] = 3
+		}
+	}
+	for _, want := range []string{"particles", "endings", "verbs"} {
+		master()
+		unlocked := gs.checkAutoProgression()
+		if len(unlocked) != 1 || unlocked[0] != want {
+			t.Fatalf("expected lesson %q to unlock, got %v (selection %v)", want, unlocked, gs.selectedCharList())
+		}
+	}
+	if !gs.selectedChars["お"] || !gs.selectedChars["る"] {
+		t.Errorf("expected the vowels and the unlocked lessons to be practised, got %v", gs.selectedCharList())
+	}
+}
+
+func TestApplyPlacementSeedsRowsAndStats(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	gs := NewGameState(st, nil)
+	if !gs.NeedsPlacement() {
+		t.Fatal("expected a fresh store to need placement")
+	}
+	gs.ApplyPlacement(kanacore.PlacementResult{
+		Known:   []string{"vowels"},
+		Answers: map[string]bool{"あ": true, "か": false},
+	})
+
+	if rows := gs.SelectedRowIDs(); len(rows) != 2 {
+		t.Errorf("expected the vowels plus one new row, got %v", rows)
+	}
+	if level := gs.masteryLevels()["う"]; level != kanacore.LevelGreen {
+		t.Errorf("expected a known row's kana to start green, got %s", level)
+	}
+	stats, _ := st.TrackStatistics(store.TrackRecognition)
+	if stats["か"].MissCount != 1 || stats["お"].CorrectCount == 0 {
+		t.Errorf("expected baseline stats to be saved, got %+v / %+v", stats["か"], stats["お"])
+	}
+	if gs.NeedsPlacement() {
+		t.Error("expected the placement to be recorded")
+	}
+	if placement, _ := st.Placement(); len(placement.Known) != 1 || placement.Skipped {
+		t.Errorf("unexpected placement record %+v", placement)
+	}
+}
+
+func TestMissedKanaShowsEditableHint(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	gs := NewGameState(st, nil)
+	tile := newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})
+	tile.Move(fyne.NewPos(0, gs.canvasH+1))
+	gs.tiles = []*KanaTile{tile}
+	gs.tick()
+
+	want := "ぬ (nu): " + kanacore.DefaultMnemonics()["ぬ"]
+	if snap := gs.snapshot(); snap.Hint != want {
+		t.Errorf("expected hint %q after the miss, got %q", want, snap.Hint)
+	}
+
+	if err := gs.SetMnemonic("ぬ", "noodles on chopsticks"); err != nil {
+		t.Fatalf("set mnemonic: %v", err)
+	}
+	if hint := NewGameState(st, nil).Mnemonic("ぬ"); hint != "noodles on chopsticks" {
+		t.Errorf("expected the edited hint to persist, got %q", hint)
+	}
+	if err := gs.SetMnemonic("ぬ", ""); err != nil {
+		t.Fatalf("reset mnemonic: %v", err)
+	}
+	if hint := gs.Mnemonic("ぬ"); hint != kanacore.DefaultMnemonics()["ぬ"] {
+		t.Errorf("expected an empty hint to restore the bundled one, got %q", hint)
+	}
+}
+
+func TestDailyGoalTracksTotalsAndStreaks(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	yesterday := kanacore.DayKey(time.Now().AddDate(0, 0, -1))
+	if err := st.SaveDailyTotal(store.DailyTotal{Day: yesterday, Correct: 5, GoalMet: true}); err != nil {
+		t.Fatalf("save daily total: %v", err)
+	}
+
+	gs := NewGameState(st, nil)
+	gs.SetDailyGoal(kanacore.Goal{Kind: kanacore.GoalCount, Count: 2})
+	gs.mu.Lock()
+	gs.recordCorrect("あ")
+	gs.recordCorrect("い")
+	snap := gs.snapshot()
+	gs.mu.Unlock()
+
+	if snap.DailyProgress != "2/2 correct today" {
+		t.Errorf("unexpected daily progress %q", snap.DailyProgress)
+	}
+	if snap.DayStreak != 2 || snap.LongestStreak != 2 {
+		t.Errorf("expected a 2 day streak, got %d (best %d)", snap.DayStreak, snap.LongestStreak)
+	}
+	if snap.UnlockMessage == "" {
+		t.Error("expected reaching the daily goal to be announced")
+	}
+
+	reloaded := NewGameState(st, nil)
+	if goal := reloaded.practice.Goal; goal.Count != 2 {
+		t.Errorf("expected the daily goal to persist, got %+v", goal)
+	}
+	if today := reloaded.practice.Today(time.Now()); today.Correct != 2 || !today.GoalMet {
+		t.Errorf("expected today's total to persist, got %+v", today)
+	}
+}
+
+func TestAchievementsAreEarnedAndPersisted(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	gs := NewGameState(st, nil)
+	gs.mu.Lock()
+	for _, char := range []string{"あ", "い", "う", "え", "お"} {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 10, Streak: 5, LastSeen: time.Now()}
+		gs.currentStreak[char] = 5
+	}
+	gs.recordCorrect("あ")
+	gs.endGame("quit")
+	snap := gs.snapshot()
+	gs.mu.Unlock()
+
+	if len(snap.Badges) != 2 || snap.Badges[0].ID != kanacore.AchievementFirstRow || snap.Badges[1].ID != kanacore.AchievementFirstSession {
+		t.Fatalf("expected the row master then first session badges, got %+v", snap.Badges)
+	}
+	if !strings.Contains(snap.UnlockMessage, "Row master") {
+		t.Errorf("expected the badge to be announced, got %q", snap.UnlockMessage)
+	}
+
+	earned := NewGameState(st, nil).Achievements()
+	if _, ok := earned[kanacore.AchievementFirstRow]; !ok || len(earned) != 2 {
+		t.Errorf("expected both badges to persist, got %v", earned)
+	}
+}
+
+func TestGhostRaceReplaysTheBestRun(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := kanacore.NewManualClock(start)
+	gs := NewGameState(st, nil)
+	gs.SetGhostRace(true)
+	gs.mu.Lock()
+	gs.clock = clock
+	gs.sessionStart = start
+	gs.reseed()
+	if gs.ghost != nil {
+		t.Error("expected no ghost before any run was recorded")
+	}
+	gs.mu.Unlock()
+
+	gs.spawnKana()
+	clock.Advance(tickInterval)
+	gs.tick()
+	gs.spawnKana()
+	clock.Advance(tickInterval)
+	gs.tick()
+	gs.checkAnswer(gs.tiles[0].kana.Romaji)
+	clock.Advance(tickInterval)
+	gs.tick()
+	gs.mu.Lock()
+	best := gs.score
+	seed := gs.seed
+	var chars []string
+	for _, tile := range gs.tiles {
+		chars = append(chars, tile.kana.Char)
+	}
+	gs.endGame("quit")
+	gs.mu.Unlock()
+
+	gs.Reset()
+	gs.mu.Lock()
+	if gs.ghost == nil || gs.seed != seed {
+		t.Fatalf("expected the next session to race the recorded run on seed %d, got seed %d", seed, gs.seed)
+	}
+	gs.mu.Unlock()
+
+	for i := 0; i < 3; i++ {
+		gs.spawnKana() // the ghost's run drops the tiles instead
+		clock.Advance(tickInterval)
+		gs.tick()
+		if i == 0 {
+			gs.mu.Lock()
+			snap := gs.snapshot()
+			gs.mu.Unlock()
+			if !snap.Racing || snap.GhostScore != 0 || snap.GhostFinal != best {
+				t.Errorf("expected the ghost at 0 of %d before its answer, got %d of %d", best, snap.GhostScore, snap.GhostFinal)
+			}
+		}
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if len(gs.tiles) != 2 || gs.tiles[1].kana.Char != chars[0] {
+		t.Errorf("expected the recorded tiles to fall again, got %d tiles", len(gs.tiles))
+	}
+	if score := gs.snapshot().GhostScore; score != best {
+		t.Errorf("expected the ghost to have scored %d, got %d", best, score)
+	}
+	gs.endGame("quit")
+	if want := kanacore.GhostResult(0, best); gs.ghostResult != want {
+		t.Errorf("expected %q, got %q", want, gs.ghostResult)
+	}
+}
+
+func TestSplitScreenPlayersShareTilesAndKeepProfiles(t *testing.T) {
+	test.NewApp()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	base := NewGameState(st, nil)
+	base.mode = kanacore.ModeChoice
+	start := time.Now()
+	players := [2]*versusPlayer{
+		{name: "Aki", gs: newVersusGame(base, start)},
+		{name: "Ren", gs: newVersusGame(base, start)},
+	}
+	if mode := players[0].gs.mode; mode != kanacore.DefaultGameMode {
+		t.Errorf("expected multiple choice to play as classic, got %s", mode)
+	}
+
+	for i := 0; i < 5; i++ {
+		for _, p := range players {
+			p.gs.spawnKana()
+		}
+	}
+	left, right := players[0].gs.tiles, players[1].gs.tiles
+	for i := range left {
+		if left[i].kana.Char != right[i].kana.Char || left[i].pos != right[i].pos {
+			t.Fatalf("tile %d differs: %s at %v and %s at %v", i, left[i].kana.Char, left[i].pos, right[i].kana.Char, right[i].pos)
+		}
+	}
+
+	char := right[0].kana.Char
+	players[1].gs.checkAnswer(right[0].kana.Romaji)
+	for _, p := range players {
+		p.gs.mu.Lock()
+		p.gs.endGame("quit")
+		p.gs.mu.Unlock()
+	}
+	results := []kanacore.PlayerResult{players[0].result(), players[1].result()}
+	if kanacore.MatchWinner(results) != 1 {
+		t.Errorf("expected Ren to win, got %+v", results)
+	}
+	if record := recordMatch(st, players, results); record != "Head to head: Aki 0 – 1 Ren, 0 drawn" {
+		t.Errorf("unexpected record %q", record)
+	}
+
+	stats, err := st.ProfileStatistics("Ren", store.TrackRecognition)
+	if err != nil {
+		t.Fatalf("profile stats: %v", err)
+	}
+	if stats[char].CorrectCount != 1 {
+		t.Errorf("expected Ren's answer to count under their profile, got %+v", stats)
+	}
+	if overall, _ := st.KanaStatistics(); len(overall) != 0 {
+		t.Errorf("expected the main statistics to stay untouched, got %+v", overall)
+	}
+}
+
+// nextRaceEvent waits for the next race event of kind, skipping the others.
+func nextRaceEvent(t *testing.T, peer netplay.Peer, kind netplay.EventKind) netplay.Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-peer.Events():
+			if e.Kind == kind {
+				return e
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", kind)
+		}
+	}
+}
+
+func TestLANRacePlaysTheHostsMatch(t *testing.T) {
+	test.NewApp()
+
+	base := NewGameState(nil, nil)
+	base.mode = kanacore.ModeLookalike
+	base.applySelectedChars([]string{"あ", "い", "う"})
+	match := lanMatch(base)
+	if match.Mode != kanacore.DefaultGameMode || len(match.Chars) != 3 {
+		t.Fatalf("expected look-alike drills to race as classic on the picked kana, got %+v", match)
+	}
+
+	host, err := netplay.Listen("127.0.0.1:0", "Aki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+	client, err := netplay.Join(host.Addr().String(), "Ren")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	nextRaceEvent(t, host, netplay.EventLobby)
+	if err := host.Start(match); err != nil {
+		t.Fatal(err)
+	}
+
+	var games [2]*GameState
+	for i, peer := range []netplay.Peer{host, client} {
+		e := nextRaceEvent(t, peer, netplay.EventStart)
+		games[i] = NewGameState(nil, nil)
+		games[i].mu.Lock()
+		games[i].playMatch(e.Match, time.Now())
+		games[i].mu.Unlock()
+	}
+	for i := 0; i < 5; i++ {
+		for _, gs := range games {
+			gs.spawnKana()
+		}
+	}
+	for i := range games[0].tiles {
+		if a, b := games[0].tiles[i].kana.Char, games[1].tiles[i].kana.Char; a != b {
+			t.Fatalf("tile %d differs: %s and %s", i, a, b)
+		}
+	}
+
+	games[1].checkAnswer(games[1].tiles[0].kana.Romaji)
+	for _, gs := range games {
+		gs.mu.Lock()
+		gs.endGame("quit")
+		gs.mu.Unlock()
+	}
+	host.Finish(games[0].raceResult())
+	client.Finish(games[1].raceResult())
+	results := netplay.Results(nextRaceEvent(t, client, netplay.EventResults).Players)
+	if kanacore.MatchWinner(results) != 1 || results[1].Hits != 1 {
+		t.Errorf("expected Ren to win the race, got %+v", results)
+	}
+}
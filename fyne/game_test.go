@@ -2,7 +2,9 @@ package main
 
 import (
 	"testing"
+	"time"
 
+	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/test"
 	"kana/kanacore"
 	"kana/store"
@@ -14,13 +16,28 @@ func newTestState() *GameState {
 		sessionStats:  make(map[string]store.KanaStats),
 		overallStats:  make(map[string]store.KanaStats),
 		currentStreak: make(map[string]int),
+		confusions:    make(map[string]map[string]int),
+		sessionMixups: make(map[string]map[string]int),
+		tallies:       make(map[string]kanacore.CharTally),
+		rowHistory:    make(kanacore.RowHistory),
 		selectedRows:  make(map[string]bool),
+		selectedChars: make(map[string]bool),
+		mnemonics:     make(map[string]string),
 		eventCh:       make(chan gameEvent, 4),
 		stopCh:        make(chan struct{}),
+		clock:         kanacore.SystemClock,
 		canvasW:       400,
 		canvasH:       600,
 	}
 	gs.charSet = kanacore.Hiragana()
+	gs.targetPolicy = kanacore.DefaultTargetPolicy
+	gs.masteryPolicy = kanacore.DefaultMasteryPolicy()
+	gs.curriculum = kanacore.DefaultCurriculum()
+	gs.practice = kanacore.NewPracticeLog(kanacore.DefaultDailyGoal, nil)
+	gs.achievements = kanacore.NewAchievementTracker(nil)
+	gs.scorer = kanacore.Scorer{Rules: kanacore.DefaultScoreRules()}
+	gs.mode = kanacore.DefaultGameMode
+	gs.rng = kanacore.NewRand(1)
 	for _, id := range kanacore.DefaultRowIDs() {
 		gs.selectedRows[id] = true
 	}
@@ -35,8 +52,29 @@ func TestCheckAnswerRemovesTile(t *testing.T) {
 	if len(gs.tiles) != 0 {
 		t.Errorf("expected tile removed, got %d tiles", len(gs.tiles))
 	}
-	if gs.score != 10 {
-		t.Errorf("expected score 10, got %d", gs.score)
+	// 10 base points plus the full speed bonus for a tile still at the top.
+	if gs.score != 15 {
+		t.Errorf("expected score 15, got %d", gs.score)
+	}
+}
+
+func TestCheckAnswerWrongSubmissionPenalises(t *testing.T) {
+	gs := newTestState()
+	gs.score = 20
+	gs.scorer.Breakdown.Base = 20
+	gs.scorer.Combo = 3
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})}
+
+	gs.checkAnswer("ki")
+
+	if gs.score != 18 {
+		t.Errorf("expected score 18 after penalty, got %d", gs.score)
+	}
+	if gs.scorer.Combo != 0 {
+		t.Errorf("expected combo reset, got %d", gs.scorer.Combo)
+	}
+	if gs.scorer.Breakdown.Wrong != 1 {
+		t.Errorf("expected 1 wrong submission, got %d", gs.scorer.Breakdown.Wrong)
 	}
 }
 
@@ -50,6 +88,57 @@ func TestCheckAnswerNoMatchLeavestTile(t *testing.T) {
 	}
 }
 
+func TestCheckAnswerClearsLowestDuplicate(t *testing.T) {
+	gs := newTestState()
+	high := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	high.Move(fyne.NewPos(10, 50))
+	low := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	low.Move(fyne.NewPos(200, 400))
+	gs.tiles = []*KanaTile{high, low}
+
+	gs.checkAnswer("ka")
+
+	if len(gs.tiles) != 1 {
+		t.Fatalf("expected 1 tile left, got %d", len(gs.tiles))
+	}
+	if gs.tiles[0] != high {
+		t.Error("expected the tile nearest the floor to be cleared")
+	}
+}
+
+func TestCheckAnswerSoonestPolicyUsesSpeed(t *testing.T) {
+	gs := newTestState()
+	gs.targetPolicy = kanacore.TargetSoonest
+	// slow is lower but needs 100 ticks; fast is higher but lands in 50.
+	slow := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka", Speed: 1})
+	slow.Move(fyne.NewPos(10, 500))
+	fast := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka", Speed: 10})
+	fast.Move(fyne.NewPos(200, 100))
+	gs.tiles = []*KanaTile{slow, fast}
+
+	gs.checkAnswer("ka")
+
+	if len(gs.tiles) != 1 || gs.tiles[0] != slow {
+		t.Error("expected the faster tile to be cleared under the soonest policy")
+	}
+}
+
+func TestCheckAnswerOldestPolicyKeepsSliceOrder(t *testing.T) {
+	gs := newTestState()
+	gs.targetPolicy = kanacore.TargetOldest
+	first := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	first.Move(fyne.NewPos(10, 50))
+	second := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	second.Move(fyne.NewPos(200, 400))
+	gs.tiles = []*KanaTile{first, second}
+
+	gs.checkAnswer("ka")
+
+	if len(gs.tiles) != 1 || gs.tiles[0] != second {
+		t.Error("expected the first spawned tile to be cleared under the oldest policy")
+	}
+}
+
 func TestRecordCorrectUpdatesSessionOnly(t *testing.T) {
 	gs := newTestState()
 	gs.recordCorrect("か")
@@ -106,11 +195,516 @@ func TestMissLimitEndsGame(t *testing.T) {
 func TestIsRowMastered(t *testing.T) {
 	gs := newTestState()
 	row := kanacore.AllKanaRows[0] // vowels
-	// Give 3 correct answers to 4 out of 5 characters (80%)
+	// Give 3 correct answers in a row to 4 out of 5 characters (80%)
 	for _, char := range row.Characters[:4] {
-		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3}
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 3, Streak: 3}
+		gs.currentStreak[char] = 3
 	}
 	if !gs.isRowMastered(row) {
 		t.Error("expected row to be mastered at 80% threshold")
 	}
 }
+
+func TestTimeAttackEndsWhenClockRunsOut(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeTimeAttack
+	gs.timeLimit = 60 * time.Second
+	gs.sessionStart = time.Now().Add(-61 * time.Second)
+
+	gs.tick()
+
+	if !gs.over {
+		t.Fatal("expected game over once the countdown expires")
+	}
+	if gs.overReason != "time" {
+		t.Errorf("expected reason 'time', got %q", gs.overReason)
+	}
+}
+
+func TestTimeAttackIgnoresScoreLimit(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeTimeAttack
+	gs.timeLimit = 60 * time.Second
+	gs.sessionStart = time.Now()
+	gs.scoreLimit = 10
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})}
+
+	gs.checkAnswer("ka")
+
+	if gs.over {
+		t.Error("expected time attack to keep running past the score limit")
+	}
+}
+
+func TestZenModeSurvivesMisses(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeZen
+	gs.zenGoal = kanacore.Goal{Kind: kanacore.GoalNone}
+	gs.sessionStart = time.Now()
+	for i := 0; i < 12; i++ {
+		tile := newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu", Speed: 10})
+		tile.Move(fyne.NewPos(20, gs.canvasH))
+		gs.tiles = []*KanaTile{tile}
+		gs.tick()
+	}
+
+	if gs.over {
+		t.Fatal("expected zen session to continue past 10 misses")
+	}
+	if gs.missed != 12 {
+		t.Errorf("expected 12 misses recorded, got %d", gs.missed)
+	}
+	if len(gs.reveals) == 0 {
+		t.Error("expected missed kana to be revealed")
+	}
+}
+
+func TestZenModeEndsOnCountGoal(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeZen
+	gs.zenGoal = kanacore.Goal{Kind: kanacore.GoalCount, Count: 2}
+	gs.sessionStart = time.Now()
+	for i := 0; i < 2; i++ {
+		gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})}
+		gs.checkAnswer("ka")
+	}
+	if !gs.over || gs.overReason != "goal" {
+		t.Errorf("expected session to end with reason 'goal', got over=%v reason=%q", gs.over, gs.overReason)
+	}
+}
+
+func TestWordAnswerCreditsEachCharacter(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeWords
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "ねこ", Romaji: "neko"})}
+
+	gs.checkAnswer("neko")
+
+	if len(gs.tiles) != 0 {
+		t.Fatalf("expected word tile cleared, got %d tiles", len(gs.tiles))
+	}
+	for _, char := range []string{"ね", "こ"} {
+		if got := gs.sessionStats[char].CorrectCount; got != 1 {
+			t.Errorf("sessionStats[%s].CorrectCount = %d, want 1", char, got)
+		}
+	}
+}
+
+func TestDailyChallengeSpawnsTheSameTiles(t *testing.T) {
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	spawn := func(start time.Time, rows map[string]bool) []*KanaTile {
+		gs := newTestState()
+		gs.mode = kanacore.ModeDaily
+		gs.selectedRows = rows
+		gs.sessionStart = start
+		gs.reseed()
+		for i := 0; i < 10; i++ {
+			gs.spawnKana()
+		}
+		return gs.tiles
+	}
+
+	first := spawn(day, map[string]bool{"vowels": true})
+	second := spawn(day.Add(8*time.Hour), map[string]bool{"k": true})
+	for i := range first {
+		a, b := first[i], second[i]
+		if a.kana.Char != b.kana.Char || a.kana.Speed != b.kana.Speed || a.pos != b.pos {
+			t.Fatalf("tile %d differs: %s at %v and %s at %v", i, a.kana.Char, a.pos, b.kana.Char, b.pos)
+		}
+	}
+
+	gs := newTestState()
+	gs.mode = kanacore.ModeDaily
+	if got, want := len(gs.availableCharacters()), len(kanacore.Hiragana().Data); got != want {
+		t.Errorf("expected the daily challenge to use all %d hiragana, got %d", want, got)
+	}
+}
+
+func TestSessionIsRecordedForReplay(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := kanacore.NewManualClock(start)
+	gs := newTestState()
+	gs.clock = clock
+	gs.sessionStart = start
+	gs.reseed()
+
+	gs.spawnKana()
+	clock.Advance(tickInterval)
+	gs.tick()
+	gs.spawnKana()
+	clock.Advance(tickInterval)
+	gs.tick()
+	answer := gs.tiles[0].kana.Romaji
+	gs.noteInput(answer)
+	gs.checkAnswer(answer)
+	gs.noteInput("")
+	clock.Advance(tickInterval)
+	gs.tick()
+	gs.endGame("quit")
+
+	replay := gs.LastReplay()
+	if replay == nil {
+		t.Fatal("expected the ended session to be recorded")
+	}
+	if replay.Length != 3*tickInterval || replay.Seed != gs.seed {
+		t.Errorf("unexpected length %v or seed %d", replay.Length, replay.Seed)
+	}
+	f := replay.Frame(replay.Length)
+	if f.Score != gs.score || f.Answer != answer || f.Input != "" {
+		t.Errorf("unexpected final frame %+v", f)
+	}
+	if len(f.Tiles) != len(gs.tiles) {
+		t.Fatalf("expected %d tiles in the frame, got %d", len(gs.tiles), len(f.Tiles))
+	}
+	for i, tile := range gs.tiles {
+		k := f.Tiles[i]
+		if k.Char != tile.kana.Char || abs32(k.X*gs.canvasW-tile.pos.X) > 0.1 || abs32(k.Y*gs.canvasH-tile.pos.Y) > 0.1 {
+			t.Errorf("tile %d replayed as %s at (%v, %v), want %s at %v", i, k.Char,
+				k.X*gs.canvasW, k.Y*gs.canvasH, tile.kana.Char, tile.pos)
+		}
+	}
+	if mid := replay.Frame(tickInterval + tickInterval/2); len(mid.Tiles) != 2 || mid.Score != 0 {
+		t.Errorf("expected both tiles before the answer, got %+v", mid)
+	}
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestWordModeSpawnsOnlySelectedCharacters(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeWords
+	gs.selectedRows = map[string]bool{"vowels": true}
+
+	for i := 0; i < 20; i++ {
+		gs.spawnKana()
+	}
+	for _, tile := range gs.tiles {
+		if !gs.tileSelected(tile) {
+			t.Errorf("spawned %s outside the selected rows", tile.kana.Char)
+		}
+	}
+}
+
+func TestSelectedCharsOverrideRows(t *testing.T) {
+	gs := newTestState()
+	gs.applySelectedChars([]string{"め", "ぬ"})
+
+	chars := gs.availableCharacters()
+	if len(chars) != 2 || chars[0] == chars[1] {
+		t.Fatalf("expected only ぬ and め, got %v", chars)
+	}
+	for _, char := range chars {
+		if char != "ぬ" && char != "め" {
+			t.Fatalf("expected only ぬ and め, got %v", chars)
+		}
+	}
+	if gs.tileSelected(newKanaTile(kanacore.Kana{Char: "あ", Romaji: "a"})) {
+		t.Error("expected あ to be deselected by the character selection")
+	}
+	if !gs.tileSelected(newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})) {
+		t.Error("expected ぬ to be selected")
+	}
+	for i := 0; i < 20; i++ {
+		gs.spawnKana()
+	}
+	for _, tile := range gs.tiles {
+		if tile.kana.Char != "ぬ" && tile.kana.Char != "め" {
+			t.Errorf("spawned %s outside the character selection", tile.kana.Char)
+		}
+	}
+
+	gs.applySelectedChars(nil)
+	if got := len(gs.availableCharacters()); got != len(kanacore.CharToRow) {
+		t.Errorf("expected rows to apply after clearing characters, got %d characters", got)
+	}
+}
+
+func TestSelectedCharsPauseAutoProgression(t *testing.T) {
+	gs := newTestState()
+	gs.autoProgress = true
+	gs.selectedRows = map[string]bool{"vowels": true}
+	gs.applySelectedChars([]string{"あ"})
+	for _, char := range kanacore.AllKanaRows[0].Characters {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 10, Streak: 10}
+		gs.currentStreak[char] = 10
+	}
+	if unlocked := gs.checkAutoProgression(); len(unlocked) != 0 {
+		t.Errorf("expected no unlocks while characters are picked, got %v", unlocked)
+	}
+}
+
+func TestAutoRegressionNarrowsAndRestoresRows(t *testing.T) {
+	gs := newTestState()
+	gs.autoProgress = true
+	gs.applySelectedRows([]string{"vowels", "k", "s"})
+	for _, char := range kanacore.AllKanaRows[1].Characters {
+		gs.overallStats[char] = store.KanaStats{Char: char, CorrectCount: 10, Streak: 10}
+		gs.currentStreak[char] = 10
+	}
+
+	for i := 0; i < 6; i++ {
+		gs.recordMiss("さ")
+	}
+	if gs.regression == nil || gs.regression.Row != "s" {
+		t.Fatalf("expected the S-row to collapse, got %+v", gs.regression)
+	}
+	if len(gs.selectedRows) != 2 || !gs.selectedRows["k"] || !gs.selectedRows["s"] {
+		t.Errorf("expected focus on the K-row and S-row, got %v", gs.selectedRows)
+	}
+	if snap := gs.snapshot(); snap.UnlockMessage == "" || snap.Regression == "" {
+		t.Errorf("expected the regression to be announced, got %q / %q", snap.UnlockMessage, snap.Regression)
+	}
+	if unlocked := gs.checkAutoProgression(); len(unlocked) != 0 {
+		t.Errorf("expected no unlocks during a regression, got %v", unlocked)
+	}
+
+	for i := 0; i < 8; i++ {
+		gs.recordCorrect("し")
+	}
+	if gs.regression != nil {
+		t.Fatal("expected the S-row to recover")
+	}
+	if len(gs.selectedRows) != 3 || !gs.selectedRows["vowels"] {
+		t.Errorf("expected the previous rows back, got %v", gs.selectedRows)
+	}
+}
+
+func TestReverseModeAcceptsKanaAndRomaji(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeReverse
+	gs.tiles = []*KanaTile{
+		newKanaTile(kanacore.Kana{Char: "し", Romaji: "shi", Reverse: true}),
+		newKanaTile(kanacore.Kana{Char: "ん", Romaji: "n", Reverse: true}),
+	}
+
+	gs.checkAnswer("shi")
+	if len(gs.tiles) != 1 {
+		t.Fatalf("expected romaji input to clear し, got %d tiles", len(gs.tiles))
+	}
+	gs.checkAnswer("ん")
+	if len(gs.tiles) != 0 {
+		t.Errorf("expected kana input to clear ん, got %d tiles", len(gs.tiles))
+	}
+	if gs.sessionStats["し"].CorrectCount != 1 || gs.sessionStats["ん"].CorrectCount != 1 {
+		t.Errorf("expected both characters credited, got %+v", gs.sessionStats)
+	}
+}
+
+func TestReverseModeSpawnsRomajiTiles(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeReverse
+	gs.spawnKana()
+	if len(gs.tiles) != 1 {
+		t.Fatalf("expected one tile, got %d", len(gs.tiles))
+	}
+	tile := gs.tiles[0]
+	if !tile.kana.Reverse || tile.text.Text != tile.kana.Romaji {
+		t.Errorf("expected tile to show romaji %q, got %q", tile.kana.Romaji, tile.text.Text)
+	}
+}
+
+func TestChoiceModeOffersAnswerForLowestTile(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeChoice
+	high := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	low := newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})
+	low.Move(fyne.NewPos(0, 300))
+	gs.tiles = []*KanaTile{high, low}
+	gs.confusions["ぬ"] = map[string]int{"me": 3}
+
+	if !gs.refreshChoices() {
+		t.Fatal("expected choices to change")
+	}
+	if gs.choiceTile != low {
+		t.Fatalf("expected choices for the lowest tile")
+	}
+	if len(gs.choices) != kanacore.ChoiceCount {
+		t.Fatalf("expected %d choices, got %v", kanacore.ChoiceCount, gs.choices)
+	}
+	has := map[string]bool{}
+	for _, c := range gs.choices {
+		has[c] = true
+	}
+	if !has["nu"] || !has["me"] {
+		t.Errorf("expected answer and confused romaji among %v", gs.choices)
+	}
+	if gs.refreshChoices() {
+		t.Error("expected choices to stay put while the tile is unchanged")
+	}
+}
+
+func TestChoiceModeCorrectPickClearsLowestMatch(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeChoice
+	high := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	low := newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})
+	low.Move(fyne.NewPos(0, 300))
+	gs.tiles = []*KanaTile{high, low}
+	gs.refreshChoices()
+
+	gs.answerChoice("ka")
+
+	if len(gs.tiles) != 1 || gs.tiles[0] != high {
+		t.Fatalf("expected the lowest か to be cleared")
+	}
+	if gs.choiceTile != high {
+		t.Error("expected choices to move on to the remaining tile")
+	}
+}
+
+func TestChoiceModeWrongPickRecordsConfusion(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeChoice
+	gs.score = 10
+	gs.scorer.Breakdown.Base = 10
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})}
+	gs.refreshChoices()
+
+	gs.answerChoice("me")
+
+	if len(gs.tiles) != 1 {
+		t.Fatal("expected a wrong pick to leave the tile")
+	}
+	if gs.score != 8 {
+		t.Errorf("expected penalty to leave score 8, got %d", gs.score)
+	}
+	if gs.confusions["ぬ"]["me"] != 1 {
+		t.Errorf("expected confusion ぬ→me recorded, got %v", gs.confusions)
+	}
+}
+
+func TestHandwritingClearsTileForRecognisedKana(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeHandwriting
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "く", Romaji: "ku", Reverse: true})}
+
+	gs.checkDrawing(kanacore.Drawing{{{X: 130, Y: 20}, {X: 60, Y: 100}, {X: 130, Y: 180}}})
+
+	if len(gs.tiles) != 0 {
+		t.Fatalf("expected drawn く to clear its tile")
+	}
+	if gs.sessionStats["く"].CorrectCount != 1 {
+		t.Errorf("expected く credited, got %+v", gs.sessionStats["く"])
+	}
+}
+
+func TestHandwritingUnrecognisedDrawingIsWrong(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeHandwriting
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "く", Romaji: "ku", Reverse: true})}
+
+	// く drawn bottom-up does not match its template.
+	gs.checkDrawing(kanacore.Drawing{{{X: 130, Y: 180}, {X: 60, Y: 100}, {X: 130, Y: 20}}})
+
+	if len(gs.tiles) != 1 {
+		t.Fatal("expected tile to remain")
+	}
+	if gs.scorer.Breakdown.Wrong != 1 {
+		t.Errorf("expected a wrong answer, got %+v", gs.scorer.Breakdown)
+	}
+}
+
+func TestDrillMissedRestrictsToMissedCharacters(t *testing.T) {
+	gs := newTestState()
+	gs.tallies["ぬ"] = kanacore.CharTally{Correct: 1, Missed: 2}
+	gs.missedKanas = []kanacore.Kana{{Char: "ぬ", Romaji: "nu"}, {Char: "め", Romaji: "me"}}
+	gs.selectedRows = map[string]bool{"vowels": true}
+
+	if !gs.StartDrill() {
+		t.Fatal("expected drill to start")
+	}
+	if gs.mode != kanacore.ModeDrill || gs.drillReturn != kanacore.ModeClassic {
+		t.Fatalf("expected drill mode returning to classic, got %q/%q", gs.mode, gs.drillReturn)
+	}
+	for i := 0; i < 10; i++ {
+		gs.spawnKana()
+	}
+	for _, tile := range gs.tiles {
+		if tile.kana.Char != "ぬ" && tile.kana.Char != "め" {
+			t.Errorf("drill spawned %s", tile.kana.Char)
+		}
+	}
+
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})}
+	gs.checkAnswer("nu")
+	snap := gs.snapshot()
+	if len(snap.DrillResults) != 2 {
+		t.Fatalf("expected results for 2 characters, got %+v", snap.DrillResults)
+	}
+	nu := snap.DrillResults[0]
+	if nu.Char != "ぬ" || nu.Before.Missed != 2 || nu.After.Correct != 1 || !nu.Improved() {
+		t.Errorf("unexpected before/after for ぬ: %+v", nu)
+	}
+
+	gs.EndDrill()
+	if gs.mode != kanacore.ModeClassic {
+		t.Errorf("expected EndDrill to restore classic, got %q", gs.mode)
+	}
+}
+
+func TestDrillEndsOnCountGoalNotMisses(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeDrill
+	gs.drillChars = []string{"か"}
+	gs.missed = 15
+	gs.checkMissedLimit()
+	if gs.over {
+		t.Fatal("drill must not end on misses")
+	}
+	gs.scorer.Breakdown.Hits = kanacore.DrillGoal(1).Count - 1
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "か", Romaji: "ka"})}
+	gs.checkAnswer("ka")
+	if !gs.over || gs.overReason != "goal" {
+		t.Errorf("expected drill to end on its goal, got over=%v reason=%q", gs.over, gs.overReason)
+	}
+}
+
+func TestStartDrillWithoutMissesDoesNothing(t *testing.T) {
+	gs := newTestState()
+	if gs.StartDrill() {
+		t.Error("expected no drill without missed characters")
+	}
+	if gs.mode != kanacore.ModeClassic {
+		t.Errorf("mode changed to %q", gs.mode)
+	}
+}
+
+func TestLookalikeModeAlternatesGroup(t *testing.T) {
+	gs := newTestState()
+	gs.mode = kanacore.ModeLookalike
+	gs.lookalike = kanacore.LookalikeGroup{"ぬ", "め"}
+	gs.selectedRows = map[string]bool{"vowels": true}
+
+	prev := ""
+	for i := 0; i < 6; i++ {
+		gs.spawnKana()
+		char := gs.tiles[len(gs.tiles)-1].kana.Char
+		if !gs.lookalike.Contains(char) || char == prev {
+			t.Fatalf("spawn %d: got %s after %s, want the other kana of ぬ/め", i, char, prev)
+		}
+		prev = char
+	}
+	if goal, ok := gs.sessionGoal(); !ok || goal != kanacore.LookalikeGoal {
+		t.Errorf("expected the look-alike goal, got %v", goal)
+	}
+}
+
+func TestLookalikeMixupsInSnapshot(t *testing.T) {
+	gs := newTestState()
+	gs.tiles = []*KanaTile{newKanaTile(kanacore.Kana{Char: "ぬ", Romaji: "nu"})}
+	gs.checkAnswer("me")
+	gs.checkAnswer("ka")
+
+	snap := gs.snapshot()
+	if len(snap.Mixups) != 1 {
+		t.Fatalf("expected one look-alike mixup, got %v", snap.Mixups)
+	}
+	if mix := snap.Mixups[0]; mix.Shown != "ぬ" || mix.Given != "め" || mix.Count != 1 {
+		t.Errorf("unexpected mixup %+v", mix)
+	}
+}
@@ -5,58 +5,209 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
 )
 
-// InputBar holds the score label, romaji entry, missed count, and settings gear.
+// InputBar holds the score label, romaji entry, missed count, and settings gear,
+// with the memory hint for a missed kana and the ghost's progress above them.
+// In multiple-choice mode the entry is replaced by answer buttons.
 type InputBar struct {
-	scoreLabel  *widget.Label
-	missedLabel *widget.Label
-	entry       *widget.Entry
-	Container   *fyne.Container
+	hintLabel     *widget.Label
+	ghostBar      *widget.ProgressBar
+	scoreLabel    *widget.Label
+	timeLabel     *widget.Label
+	missedLabel   *widget.Label
+	entry         *widget.Entry
+	choiceButtons []*widget.Button
+	choiceBox     *fyne.Container
+	drawPad       *DrawPad
+	drawBox       *fyne.Container
+	Container     *fyne.Container
 }
 
 func newInputBar(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameCanvas, win fyne.Window) *InputBar {
 	ib := &InputBar{
+		hintLabel:   widget.NewLabel(""),
+		ghostBar:    widget.NewProgressBar(),
 		scoreLabel:  widget.NewLabel("Score: 0"),
+		timeLabel:   widget.NewLabel(""),
 		missedLabel: widget.NewLabel("Missed: 0/10"),
 		entry:       widget.NewEntry(),
 	}
 	ib.entry.SetPlaceHolder("type romaji…")
+	ib.hintLabel.Wrapping = fyne.TextWrapWord
+	ib.hintLabel.TextStyle = fyne.TextStyle{Italic: true}
+	ib.hintLabel.Hide()
+	ib.ghostBar.Hide()
+	ib.entry.OnChanged = func(text string) {
+		gs.noteInput(text)
+		gs.mu.Lock()
+		production := gs.mode.Production()
+		gs.mu.Unlock()
+		if !production {
+			return
+		}
+		// Convert romaji to kana as it is typed; kana from the system IME
+		// pass through unchanged.
+		if converted := kanacore.RomajiToHiragana(text); converted != text {
+			ib.entry.SetText(converted)
+			ib.entry.CursorColumn = len([]rune(converted))
+		}
+	}
 
-	ib.entry.OnSubmitted = func(text string) {
-		// checkAnswer acquires its own lock and calls canvas.Refresh internally
-		gs.checkAnswer(text)
-
-		// Build snapshot for stats/score updates
+	// refresh rebuilds the score and stats labels after an answer.
+	refresh := func() {
 		gs.mu.Lock()
 		snap := gs.snapshot()
 		gs.mu.Unlock()
 
-		ib.entry.SetText("")
-		ib.scoreLabel.SetText(ib.formatScore(snap.Score, snap.ScoreLimit))
-		ib.missedLabel.SetText(fmt.Sprintf("Missed: %d/10", snap.Missed))
+		ib.Update(snap)
 		statsPanel.Update(snap)
 	}
 
+	ib.entry.OnSubmitted = func(text string) {
+		// checkAnswer acquires its own lock and calls canvas.Refresh internally
+		gs.checkAnswer(text)
+		ib.entry.SetText("")
+		refresh()
+	}
+
+	choiceObjects := make([]fyne.CanvasObject, kanacore.ChoiceCount)
+	for i := range choiceObjects {
+		btn := widget.NewButton("", nil)
+		btn.OnTapped = func() {
+			gs.answerChoice(btn.Text)
+			refresh()
+		}
+		ib.choiceButtons = append(ib.choiceButtons, btn)
+		choiceObjects[i] = btn
+	}
+	ib.choiceBox = container.NewGridWithColumns(kanacore.ChoiceCount, choiceObjects...)
+	ib.choiceBox.Hide()
+
 	gearBtn := widget.NewButton("⚙", func() {
 		showSettingsDialog(gs, statsPanel, gameCanvas, win)
 	})
 
-	rightCluster := container.NewHBox(ib.missedLabel, gearBtn)
-	ib.Container = container.NewBorder(nil, nil, ib.scoreLabel, rightCluster, ib.entry)
+	calendarBtn := widget.NewButton("📅", func() {
+		showCalendarDialog(gs, win)
+	})
+
+	badgesBtn := widget.NewButton("🏅", func() {
+		showAchievementsDialog(gs, win)
+	})
+
+	rightCluster := container.NewHBox(ib.timeLabel, ib.missedLabel, calendarBtn, badgesBtn, gearBtn)
+	ib.drawPad = newDrawPad()
+	submitBtn := widget.NewButton("Submit", func() {
+		gs.checkDrawing(ib.drawPad.Drawing())
+		ib.drawPad.Clear()
+		refresh()
+	})
+	undoBtn := widget.NewButton("Undo", ib.drawPad.Undo)
+	clearBtn := widget.NewButton("Clear", ib.drawPad.Clear)
+	padButtons := container.NewVBox(submitBtn, undoBtn, clearBtn)
+	ib.drawBox = container.NewHBox(layout.NewSpacer(), ib.drawPad, padButtons, layout.NewSpacer())
+	ib.drawBox.Hide()
+
+	answerArea := container.NewStack(ib.entry, ib.choiceBox, ib.drawBox)
+	ib.Container = container.NewBorder(container.NewVBox(ib.hintLabel, ib.ghostBar), nil, ib.scoreLabel, rightCluster, answerArea)
 	return ib
 }
 
-func (ib *InputBar) formatScore(score, limit int) string {
-	if limit > 0 {
-		return fmt.Sprintf("Score: %d/%d", score, limit)
+func (ib *InputBar) formatScore(snap StatsSnapshot) string {
+	text := fmt.Sprintf("Score: %d", snap.Score)
+	if snap.Mode.Timed() {
+		text = fmt.Sprintf("Score: %d  Correct: %d", snap.Score, snap.Breakdown.Hits)
+	} else if snap.ScoreLimit > 0 {
+		text = fmt.Sprintf("Score: %d/%d", snap.Score, snap.ScoreLimit)
 	}
-	return fmt.Sprintf("Score: %d", score)
+	if snap.Combo > 1 {
+		text += fmt.Sprintf("  Combo %d (x%d)", snap.Combo, snap.Multiplier)
+	}
+	return text
 }
 
-// Update refreshes score and missed labels (used after Reset/PlayAgain).
+// Update refreshes score, countdown, missed and hint labels and the ghost's
+// progress.
 func (ib *InputBar) Update(snap StatsSnapshot) {
-	ib.scoreLabel.SetText(ib.formatScore(snap.Score, snap.ScoreLimit))
-	ib.missedLabel.SetText(fmt.Sprintf("Missed: %d/10", snap.Missed))
+	ib.scoreLabel.SetText(ib.formatScore(snap))
+	ib.updateGhost(snap)
+	if snap.Hint != "" {
+		ib.hintLabel.SetText("💡 " + snap.Hint)
+		ib.hintLabel.Show()
+	} else {
+		ib.hintLabel.Hide()
+	}
+	ib.missedLabel.SetText("Missed: " + formatMissed(snap))
+	if snap.Mode.Production() {
+		ib.entry.SetPlaceHolder("type kana (romaji converts)…")
+	} else {
+		ib.entry.SetPlaceHolder("type romaji…")
+	}
+	ib.updateChoices(snap)
+	if snap.Mode.Timed() {
+		ib.timeLabel.SetText("Time: " + kanacore.FormatClock(snap.TimeLeft))
+		ib.timeLabel.Show()
+	} else if snap.Mode.HasGoal() {
+		ib.timeLabel.SetText("Goal: " + snap.Goal.Progress(snap.Breakdown.Hits, snap.Elapsed))
+		ib.timeLabel.Show()
+	} else {
+		ib.timeLabel.SetText("")
+		ib.timeLabel.Hide()
+	}
+}
+
+// updateGhost fills the ghost's progress bar towards its final score, naming
+// the ghost's score and the player's lead over it.
+func (ib *InputBar) updateGhost(snap StatsSnapshot) {
+	if !snap.Racing {
+		ib.ghostBar.Hide()
+		return
+	}
+	ib.ghostBar.Max = float64(max(snap.GhostFinal, 1))
+	ib.ghostBar.TextFormatter = func() string {
+		return fmt.Sprintf("👻 Ghost: %d/%d, %s", snap.GhostScore, snap.GhostFinal,
+			kanacore.GhostGap(snap.Score, snap.GhostScore))
+	}
+	ib.ghostBar.SetValue(float64(snap.GhostScore))
+	ib.ghostBar.Show()
+}
+
+// formatMissed formats the miss counter, omitting the limit in modes without one.
+func formatMissed(snap StatsSnapshot) string {
+	if !snap.Mode.EndsOnMisses() {
+		return fmt.Sprintf("%d", snap.Missed)
+	}
+	return fmt.Sprintf("%d/10", snap.Missed)
+}
+
+// updateChoices swaps the entry for the drawing pad in handwriting mode or the
+// answer buttons in multiple-choice mode, labelling them with the current choices.
+func (ib *InputBar) updateChoices(snap StatsSnapshot) {
+	if snap.Mode.Drawn() {
+		ib.entry.Hide()
+		ib.choiceBox.Hide()
+		ib.drawBox.Show()
+		return
+	}
+	ib.drawBox.Hide()
+	if !snap.Mode.MultipleChoice() {
+		ib.choiceBox.Hide()
+		ib.entry.Show()
+		return
+	}
+	ib.entry.Hide()
+	ib.choiceBox.Show()
+	for i, btn := range ib.choiceButtons {
+		if i < len(snap.Choices) {
+			btn.SetText(snap.Choices[i])
+			btn.Enable()
+		} else {
+			btn.SetText("")
+			btn.Disable()
+		}
+	}
 }
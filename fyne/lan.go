@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+	"kana/netplay"
+	"kana/store"
+)
+
+// Options of the LAN race dialog's role picker.
+const (
+	lanHostRole = "Host a race"
+	lanJoinRole = "Join a race"
+)
+
+// lanRace is the local player's side of a running LAN race.
+type lanRace struct {
+	gs        *GameState
+	canvas    *GameCanvas
+	entry     *widget.Entry
+	standings *widget.Label
+}
+
+// showLANSetup asks for the player's name and whether to host a race or join
+// one by address, then opens the race's lobby.
+func showLANSetup(base *GameState, st *store.Store, win fyne.Window) {
+	name := widget.NewEntry()
+	name.SetPlaceHolder("Your name")
+	name.Validator = func(v string) error { return profileName(v, "") }
+	addr := widget.NewEntry()
+	role := widget.NewRadioGroup([]string{lanHostRole, lanJoinRole}, func(r string) {
+		if r == lanHostRole {
+			addr.SetText(":" + netplay.DefaultPort)
+			return
+		}
+		addr.SetText("")
+		addr.SetPlaceHolder("192.168.1.10:" + netplay.DefaultPort)
+	})
+	role.Horizontal = true
+	role.Required = true
+	role.SetSelected(lanHostRole)
+	addr.Validator = func(v string) error {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("enter an address")
+		}
+		return nil
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+		widget.NewFormItem("Race", role),
+		widget.NewFormItem("Address", addr),
+	}
+	dialog.ShowForm("LAN race", "Continue", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		player := strings.TrimSpace(name.Text)
+		hosting := role.Selected == lanHostRole
+		address := netplay.WithDefaultPort(addr.Text)
+		// Joining waits on the network, so it runs off the UI thread.
+		go func() {
+			peer, host, err := netplay.Open(hosting, address, player)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				showLANWindow(base, st, player, peer, host)
+			})
+		}()
+	}, win)
+}
+
+// lanMatch returns the match a hosted race plays: the rules and kana of base
+// with a fresh seed. Modes others cannot share play as classic.
+func lanMatch(base *GameState) netplay.Match {
+	base.mu.Lock()
+	defer base.mu.Unlock()
+	mode := base.mode
+	if mode == kanacore.ModeDrill {
+		mode = base.drillReturn
+	}
+	if !netplay.Raceable(mode) {
+		mode = kanacore.DefaultGameMode
+	}
+	// The kana come from the race's mode rather than a drill's or look-alike
+	// group's.
+	current := base.mode
+	base.mode = mode
+	chars := base.availableCharacters()
+	base.mode = current
+	return netplay.Match{
+		Mode:       mode,
+		Seed:       kanacore.SessionSeed(mode, time.Now()),
+		TimeLimit:  int(base.timeLimit / time.Second),
+		ScoreLimit: base.scoreLimit,
+		ZenGoal:    base.zenGoal.String(),
+		Chars:      chars,
+	}
+}
+
+// playMatch sets the game up to play a race's match from start: the host's
+// rules, kana and seed over the player's own statistics. None of it is saved
+// as the player's settings, and auto-progression and ghosts sit the race out
+// so every player keeps getting the same tiles. Must be called under lock.
+func (gs *GameState) playMatch(match netplay.Match, start time.Time) {
+	gs.autoProgress = false
+	gs.ghostRace = false
+	gs.ghost = nil
+	gs.applySelectedChars(match.Chars)
+	gs.switchMode(match.Mode)
+	gs.timeLimit = kanacore.NormalizeTimeLimit(time.Duration(match.TimeLimit) * time.Second)
+	gs.scoreLimit = match.ScoreLimit
+	gs.zenGoal = kanacore.ParseGoal(match.ZenGoal)
+	gs.sessionStart = start
+	gs.seed = match.Seed
+	gs.rng = kanacore.NewRand(gs.seed)
+	gs.recorder = kanacore.NewRecorder(gs.mode, gs.seed, gs.sessionStart)
+}
+
+// raceResult returns the player's progress to share with the others.
+func (gs *GameState) raceResult() kanacore.PlayerResult {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	b := gs.scorer.Breakdown
+	return kanacore.PlayerResult{
+		Score:    gs.score,
+		Hits:     b.Hits,
+		Wrong:    b.Wrong,
+		Missed:   gs.missed,
+		MaxCombo: b.MaxCombo,
+	}
+}
+
+// lobbyText lists the players waiting in the lobby.
+func lobbyText(players []netplay.PlayerState, self string) string {
+	return strings.Join(netplay.LobbyLines(players, self), "\n")
+}
+
+// standingsText lists every player's progress, marking the local player.
+func standingsText(players []netplay.PlayerState, self string) string {
+	return strings.Join(netplay.StandingLines(players, self), "\n")
+}
+
+// showLANWindow opens a race's lobby in a window of its own. Once the host
+// starts the race the window plays the match beside everyone's standings,
+// and shows the results when every player is done.
+func showLANWindow(base *GameState, st *store.Store, name string, peer netplay.Peer, host *netplay.Host) {
+	w := fyne.CurrentApp().NewWindow("Kana — LAN race")
+	w.Resize(fyne.NewSize(800, 620))
+
+	players := widget.NewLabel("")
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	lobby := container.NewVBox(widget.NewLabelWithStyle("Lobby", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	if host != nil {
+		_, port, _ := net.SplitHostPort(host.Addr().String())
+		if joins := netplay.JoinAddresses(port); len(joins) > 0 {
+			lobby.Add(widget.NewLabel("Others join at " + strings.Join(joins, " or ")))
+		}
+		players.SetText(lobbyText(host.Players(), name))
+		startBtn := widget.NewButton("Start race", func() {
+			if err := host.Start(lanMatch(base)); err != nil {
+				status.SetText(err.Error())
+			}
+		})
+		startBtn.Importance = widget.HighImportance
+		lobby.Add(players)
+		lobby.Add(status)
+		lobby.Add(startBtn)
+	} else {
+		lobby.Add(players)
+		lobby.Add(widget.NewLabel("Waiting for the host to start the race…"))
+		lobby.Add(status)
+	}
+	w.SetContent(lobby)
+
+	stop := make(chan struct{})
+	var race *lanRace
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case e, ok := <-peer.Events():
+				if !ok {
+					return
+				}
+				fyne.Do(func() {
+					switch e.Kind {
+					case netplay.EventLobby:
+						players.SetText(lobbyText(e.Players, name))
+					case netplay.EventStart:
+						race = startLANRace(st, name, peer, e.Match, w, stop)
+						race.standings.SetText(standingsText(e.Players, name))
+					case netplay.EventStandings:
+						if race != nil {
+							race.standings.SetText(standingsText(e.Players, name))
+						}
+					case netplay.EventResults:
+						if race != nil {
+							race.standings.SetText(standingsText(e.Players, name))
+						}
+						dialog.ShowCustom("Race results", "Close", resultsTable(netplay.Results(e.Players)), w)
+					case netplay.EventClosed:
+						status.SetText(e.Err.Error())
+						if race != nil {
+							race.standings.SetText(race.standings.Text + "\n\n" + e.Err.Error())
+						}
+					}
+				})
+			}
+		}
+	}()
+
+	w.SetOnClosed(func() {
+		close(stop)
+		if race != nil {
+			race.gs.mu.Lock()
+			race.gs.mergeSessionStats()
+			race.gs.mu.Unlock()
+			race.gs.Stop()
+		}
+		_ = peer.Close()
+	})
+	w.Show()
+}
+
+// startLANRace plays match in w beside everyone's standings, sharing the
+// player's progress until they are done.
+func startLANRace(st *store.Store, name string, peer netplay.Peer, match netplay.Match, w fyne.Window, stop chan struct{}) *lanRace {
+	gs := NewGameState(st, nil)
+	gs.mu.Lock()
+	gs.playMatch(match, time.Now())
+	gs.mu.Unlock()
+	race := &lanRace{
+		gs:        gs,
+		canvas:    newGameCanvas(gs),
+		entry:     widget.NewEntry(),
+		standings: widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}),
+	}
+
+	production := match.Mode.Production()
+	race.entry.SetPlaceHolder("Type the romaji and press Enter")
+	if production {
+		race.entry.SetPlaceHolder("Type the kana and press Enter")
+		race.entry.OnChanged = func(text string) {
+			if converted := kanacore.RomajiToHiragana(text); converted != text {
+				race.entry.SetText(converted)
+				race.entry.CursorColumn = len([]rune(converted))
+			}
+		}
+	}
+	race.entry.OnSubmitted = func(text string) {
+		gs.checkAnswer(text)
+		race.entry.SetText("")
+	}
+	endBtn := widget.NewButton("End my race", func() {
+		gs.mu.Lock()
+		gs.endGame("quit")
+		gs.mu.Unlock()
+	})
+	side := container.NewVBox(
+		widget.NewLabelWithStyle("Standings", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		race.standings,
+	)
+	w.SetContent(container.NewBorder(nil, container.NewBorder(nil, nil, nil, endBtn, race.entry), nil, side, race.canvas))
+
+	gs.Start(race.canvas)
+	go func(events chan gameEvent) {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		var reported kanacore.PlayerResult
+		for {
+			select {
+			case <-stop:
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.kind != gameOverEvent {
+					continue
+				}
+				peer.Finish(gs.raceResult())
+				fyne.Do(func() {
+					race.entry.Disable()
+					race.entry.SetPlaceHolder(fmt.Sprintf("Done, %s! Waiting for the others to finish…", name))
+				})
+				return
+			case <-ticker.C:
+				if r := gs.raceResult(); r != reported {
+					reported = r
+					peer.Report(r)
+				}
+			}
+		}
+	}(gs.eventCh)
+	w.Canvas().Focus(race.entry)
+	return race
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"fyne.io/fyne/v2/app"
+	"kana/kanacore"
 	"kana/store"
 )
 
@@ -16,7 +17,12 @@ func main() {
 	}
 	defer st.Close()
 
+	curricula, err := kanacore.LoadCurricula(st.CurriculumDir())
+	if err != nil {
+		fmt.Printf("Warning: skipped curricula that could not be loaded:\n%v\n", err)
+	}
+
 	a := app.New()
-	w := buildWindow(a, st)
+	w := buildWindow(a, st, curricula)
 	w.ShowAndRun()
 }
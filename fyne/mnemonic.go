@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// newMissedHints lists the memory hint for each kana missed this session,
+// one per kana, each with a button to edit it.
+func newMissedHints(gs *GameState, missed []kanacore.Kana, win fyne.Window) fyne.CanvasObject {
+	seen := make(map[string]bool)
+	var chars []string
+	for _, k := range missed {
+		char, _ := kanacore.KanaMnemonic(k.Char, nil)
+		if char == "" || seen[char] {
+			continue
+		}
+		seen[char] = true
+		chars = append(chars, char)
+	}
+	sort.Strings(chars)
+
+	box := container.NewVBox()
+	for _, char := range chars {
+		label := widget.NewLabel(formatHint(gs, char))
+		label.Wrapping = fyne.TextWrapWord
+		edit := widget.NewButton("Edit", func() {
+			showMnemonicEditor(gs, char, win, func() { label.SetText(formatHint(gs, char)) })
+		})
+		box.Add(container.NewBorder(nil, nil, nil, edit, label))
+	}
+	return box
+}
+
+func formatHint(gs *GameState, char string) string {
+	romaji, _ := kanacore.Hiragana().GetRomaji(char)
+	return fmt.Sprintf("%s (%s): %s", char, romaji, gs.Mnemonic(char))
+}
+
+// showMnemonicEditor lets the player replace the memory hint for a kana.
+// Saving an empty hint restores the bundled one.
+func showMnemonicEditor(gs *GameState, char string, win fyne.Window, saved func()) {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.SetText(gs.Mnemonic(char))
+	bundled := widget.NewLabel("Bundled: " + kanacore.DefaultMnemonics()[char])
+	bundled.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Hint", entry),
+		widget.NewFormItem("", bundled),
+	}
+	d := dialog.NewForm("Memory hint for "+char, "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		hint := entry.Text
+		if hint == kanacore.DefaultMnemonics()[char] {
+			hint = ""
+		}
+		if err := gs.SetMnemonic(char, hint); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		saved()
+	}, win)
+	d.Resize(fyne.NewSize(420, 260))
+	d.Show()
+}
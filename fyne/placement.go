@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// showPlacementDialog runs the first-launch placement test one kana at a time
+// and calls done once it is finished or skipped.
+func showPlacementDialog(gs *GameState, win fyne.Window, done func()) {
+	test := kanacore.NewPlacementTest(kanacore.Hiragana(), kanacore.NewRand(time.Now().UnixNano()))
+
+	kanaText := canvas.NewText("", WarmPaperTheme().kanaColor(colorKanaText))
+	kanaText.TextSize = 72
+	kanaText.Alignment = fyne.TextAlignCenter
+	progress := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("Type the romaji and press Enter")
+
+	showCurrent := func() {
+		k, _ := test.Current()
+		kanaText.Text = k.Char
+		kanaText.Refresh()
+		row, rows := test.Progress()
+		progress.SetText(fmt.Sprintf("Row %d of %d", row, rows))
+	}
+
+	var d *dialog.CustomDialog
+	submit := func(text string) {
+		test.Answer(text)
+		entry.SetText("")
+		if !test.Done() {
+			showCurrent()
+			return
+		}
+		result := test.Result()
+		gs.ApplyPlacement(result)
+		d.Hide()
+		dialog.ShowInformation("Placement done", result.Summary()+"\nYou can change the rows in the settings.", win)
+		done()
+	}
+	entry.OnSubmitted = submit
+
+	skipBtn := widget.NewButton("Skip test", func() {
+		gs.SkipPlacement()
+		d.Hide()
+		done()
+	})
+	unknownBtn := widget.NewButton("I don't know", func() { submit("") })
+	answerBtn := widget.NewButton("Answer", func() { submit(entry.Text) })
+	answerBtn.Importance = widget.HighImportance
+
+	intro := widget.NewLabel(fmt.Sprintf(
+		"Type the romaji for up to %d kana per row so practice starts with the rows you don't know yet.",
+		kanacore.PlacementSamples))
+	intro.Wrapping = fyne.TextWrapWord
+	content := container.NewVBox(intro, kanaText, progress, entry)
+
+	showCurrent()
+	d = dialog.NewCustomWithoutButtons("Placement test", content, win)
+	d.SetButtons([]fyne.CanvasObject{skipBtn, unknownBtn, answerBtn})
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+	win.Canvas().Focus(entry)
+}
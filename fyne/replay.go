@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+)
+
+// replayFrameInterval is how often the replay viewer advances and redraws.
+const replayFrameInterval = 50 * time.Millisecond
+
+// ReplayField draws one frame of a replay, scaling the recorded field
+// fractions to its own size.
+type ReplayField struct {
+	widget.BaseWidget
+	frame kanacore.ReplayFrame
+}
+
+func newReplayField() *ReplayField {
+	f := &ReplayField{}
+	f.ExtendBaseWidget(f)
+	return f
+}
+
+// SetFrame shows frame.
+func (f *ReplayField) SetFrame(frame kanacore.ReplayFrame) {
+	f.frame = frame
+	f.Refresh()
+}
+
+func (f *ReplayField) CreateRenderer() fyne.WidgetRenderer {
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameBackground))
+	return &replayFieldRenderer{field: f, bg: bg}
+}
+
+type replayFieldRenderer struct {
+	field   *ReplayField
+	bg      *canvas.Rectangle
+	objects []fyne.CanvasObject
+}
+
+func (r *replayFieldRenderer) Layout(size fyne.Size) {
+	r.bg.Resize(size)
+	r.bg.Move(fyne.NewPos(0, 0))
+	r.build(size)
+}
+
+func (r *replayFieldRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, 300)
+}
+
+func (r *replayFieldRenderer) Refresh() {
+	r.bg.FillColor = theme.Color(theme.ColorNameBackground)
+	r.build(r.field.Size())
+	canvas.Refresh(r.field)
+}
+
+// build lays the frame's tiles out for the field size.
+func (r *replayFieldRenderer) build(size fyne.Size) {
+	r.objects = []fyne.CanvasObject{r.bg}
+	for _, k := range r.field.frame.Tiles {
+		tile := newKanaTile(k)
+		tile.Move(fyne.NewPos(k.X*size.Width, k.Y*size.Height))
+		r.objects = append(r.objects, tile.Objects()...)
+	}
+}
+
+func (r *replayFieldRenderer) Destroy() {}
+
+func (r *replayFieldRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// replayViewer plays a recording back with speed, pause and seek controls.
+// It is only touched on the UI goroutine.
+type replayViewer struct {
+	replay *kanacore.Replay
+	at     time.Duration
+	speed  int
+	paused bool
+
+	field     *ReplayField
+	seekBar   *widget.Slider
+	seeking   bool // seekBar is being moved by playback, not the player
+	clock     *widget.Label
+	score     *widget.Label
+	input     *widget.Label
+	answer    *widget.Label
+	playPause *widget.Button
+}
+
+func newReplayViewer(r *kanacore.Replay) *replayViewer {
+	v := &replayViewer{
+		replay: r,
+		speed:  kanacore.ReplaySpeeds[0],
+		field:  newReplayField(),
+		clock:  widget.NewLabel(""),
+		score:  widget.NewLabel(""),
+		input:  widget.NewLabel(""),
+		answer: widget.NewLabel(""),
+	}
+	v.seekBar = widget.NewSlider(0, r.Length.Seconds())
+	v.seekBar.Step = replayFrameInterval.Seconds()
+	v.seekBar.OnChanged = func(value float64) {
+		if !v.seeking {
+			v.seek(time.Duration(value * float64(time.Second)))
+		}
+	}
+	v.playPause = widget.NewButton("Pause", v.togglePause)
+	v.render()
+	return v
+}
+
+// seek moves playback to at, kept within the replay.
+func (v *replayViewer) seek(at time.Duration) {
+	v.at = max(0, min(at, v.replay.Length))
+	v.render()
+}
+
+// advance plays one frame, pausing at the end.
+func (v *replayViewer) advance() {
+	if v.paused {
+		return
+	}
+	v.seek(v.at + replayFrameInterval*time.Duration(v.speed))
+	if v.at == v.replay.Length {
+		v.paused = true
+		v.playPause.SetText("Play")
+	}
+}
+
+func (v *replayViewer) togglePause() {
+	if v.paused && v.at == v.replay.Length {
+		v.at = 0
+	}
+	v.paused = !v.paused
+	if v.paused {
+		v.playPause.SetText("Play")
+	} else {
+		v.playPause.SetText("Pause")
+	}
+}
+
+// render shows the frame at the playback position.
+func (v *replayViewer) render() {
+	f := v.replay.Frame(v.at)
+	v.field.SetFrame(f)
+	v.seeking = true
+	v.seekBar.SetValue(v.at.Seconds())
+	v.seeking = false
+	v.clock.SetText(kanacore.FormatClock(v.at) + " / " + kanacore.FormatClock(v.replay.Length))
+	v.score.SetText(fmt.Sprintf("Score: %d  Missed: %d", f.Score, f.Missed))
+	v.input.SetText("Typing: " + f.Input)
+	v.answer.SetText("Last answer: " + f.Answer)
+}
+
+// showReplayDialog plays a recorded session back in a dialog.
+func showReplayDialog(r *kanacore.Replay, win fyne.Window) {
+	v := newReplayViewer(r)
+
+	speeds := make([]string, len(kanacore.ReplaySpeeds))
+	for i, s := range kanacore.ReplaySpeeds {
+		speeds[i] = fmt.Sprintf("%dx", s)
+	}
+	speedRadio := widget.NewRadioGroup(speeds, func(label string) {
+		for i, s := range speeds {
+			if s == label {
+				v.speed = kanacore.ReplaySpeeds[i]
+			}
+		}
+	})
+	speedRadio.Horizontal = true
+	speedRadio.Required = true
+	speedRadio.SetSelected(speeds[0])
+
+	title := fmt.Sprintf("%s, %s", r.Mode.Label(), r.Start.Format("2 Jan 2006 15:04"))
+	info := container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		v.score, v.input, v.answer,
+	)
+	controls := container.NewBorder(nil, nil, v.playPause, container.NewHBox(v.clock, speedRadio), v.seekBar)
+	content := container.NewBorder(info, controls, nil, nil, v.field)
+
+	stop := make(chan struct{})
+	d := dialog.NewCustom("Replay", "Close", content, win)
+	d.SetOnClosed(func() { close(stop) })
+	d.Resize(fyne.NewSize(480, 640))
+	d.Show()
+
+	go func() {
+		ticker := time.NewTicker(replayFrameInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(v.advance)
+			}
+		}
+	}()
+}
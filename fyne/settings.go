@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -20,8 +21,25 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 			currentRowIDs = append(currentRowIDs, row.ID)
 		}
 	}
+	currentChars := gs.selectedCharList()
 	currentAuto := gs.autoProgress
+	currentGhost := gs.ghostRace
+	currentCurriculum := gs.curriculum.ID
+	curricula := append([]kanacore.Curriculum(nil), gs.curricula...)
+	currentMastery := gs.masteryPolicy
 	currentLimit := gs.scoreLimit
+	currentPolicy := gs.targetPolicy
+	currentMode := gs.mode
+	drilling := currentMode == kanacore.ModeDrill
+	if drilling {
+		// Drills are not selectable; show the mode the drill returns to.
+		currentMode = gs.drillReturn
+	}
+	currentTimeLimit := gs.timeLimit
+	currentGoal := gs.zenGoal
+	currentDaily := gs.practice.Goal
+	currentLookalike := gs.lookalikeChoice
+	lookalikeGroups := gs.lookalikeGroups()
 	gs.mu.Unlock()
 
 	// Build options list (labels)
@@ -43,9 +61,45 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 	rowCheck := widget.NewCheckGroup(options, nil)
 	rowCheck.SetSelected(selectedLabels)
 
+	charPicker := newCharPicker(gs, currentChars, win)
+
 	autoCheck := widget.NewCheck("Enable auto-progression", nil)
 	autoCheck.SetChecked(currentAuto)
 
+	ghostCheck := widget.NewCheck("Race a ghost of your best run (from the next game)", nil)
+	ghostCheck.SetChecked(currentGhost)
+
+	curriculumLabels := make([]string, len(curricula))
+	labelToCurriculum := make(map[string]string, len(curricula))
+	selectedCurriculum := ""
+	for i, c := range curricula {
+		curriculumLabels[i] = c.Name
+		labelToCurriculum[c.Name] = c.ID
+		if c.ID == currentCurriculum {
+			selectedCurriculum = c.Name
+		}
+	}
+	curriculumSelect := widget.NewSelect(curriculumLabels, nil)
+	curriculumSelect.PlaceHolder = "Curriculum"
+	if selectedCurriculum != "" {
+		curriculumSelect.SetSelected(selectedCurriculum)
+	}
+
+	masteryCorrect := newWholeNumberEntry(currentMastery.MinCorrect, 0)
+	masteryAccuracy := newWholeNumberEntry(int(currentMastery.MinAccuracy*100+0.5), 100)
+	masteryStreak := newWholeNumberEntry(currentMastery.MinStreak, 0)
+	masteryDays := newWholeNumberEntry(int(currentMastery.MaxAge/(24*time.Hour)), 0)
+	masteryForm := widget.NewForm(
+		widget.NewFormItem("Correct answers", masteryCorrect),
+		widget.NewFormItem("Accuracy (%)", masteryAccuracy),
+		widget.NewFormItem("In a row", masteryStreak),
+		widget.NewFormItem("Fades after days", masteryDays),
+	)
+	masteryItem := widget.NewAccordionItem("Mastery (green kana)", container.NewVBox(
+		widget.NewLabel("The curriculum sets how much of each lesson must be green to unlock the next.\nUse 0 days to never fade."),
+		masteryForm,
+	))
+
 	limitEntry := widget.NewEntry()
 	limitEntry.SetText(strconv.Itoa(currentLimit))
 	limitEntry.Validator = func(s string) error {
@@ -57,14 +111,103 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 		return nil
 	}
 
+	policyLabels := make([]string, len(kanacore.AllTargetPolicies))
+	labelToPolicy := make(map[string]kanacore.TargetPolicy, len(kanacore.AllTargetPolicies))
+	for i, policy := range kanacore.AllTargetPolicies {
+		policyLabels[i] = policy.Label()
+		labelToPolicy[policy.Label()] = policy
+	}
+	policySelect := widget.NewSelect(policyLabels, nil)
+	policySelect.SetSelected(currentPolicy.Label())
+
+	modeLabels := make([]string, len(kanacore.AllGameModes))
+	labelToMode := make(map[string]kanacore.GameMode, len(kanacore.AllGameModes))
+	for i, mode := range kanacore.AllGameModes {
+		modeLabels[i] = mode.Label()
+		labelToMode[mode.Label()] = mode
+	}
+	durationLabels := make([]string, len(kanacore.TimeAttackDurations))
+	labelToDuration := make(map[string]time.Duration, len(kanacore.TimeAttackDurations))
+	for i, d := range kanacore.TimeAttackDurations {
+		durationLabels[i] = fmt.Sprintf("%d seconds", int(d/time.Second))
+		labelToDuration[durationLabels[i]] = d
+	}
+	durationSelect := widget.NewSelect(durationLabels, nil)
+	durationSelect.SetSelected(fmt.Sprintf("%d seconds", int(currentTimeLimit/time.Second)))
+	goalLabels := make([]string, len(kanacore.ZenGoals))
+	labelToGoal := make(map[string]kanacore.Goal, len(kanacore.ZenGoals))
+	for i, g := range kanacore.ZenGoals {
+		goalLabels[i] = g.Label()
+		labelToGoal[g.Label()] = g
+	}
+	goalSelect := widget.NewSelect(goalLabels, nil)
+	goalSelect.SetSelected(currentGoal.Label())
+	dailyLabels := make([]string, len(kanacore.DailyGoals))
+	labelToDaily := make(map[string]kanacore.Goal, len(kanacore.DailyGoals))
+	for i, g := range kanacore.DailyGoals {
+		dailyLabels[i] = g.DailyLabel()
+		labelToDaily[g.DailyLabel()] = g
+	}
+	dailySelect := widget.NewSelect(dailyLabels, nil)
+	dailySelect.SetSelected(currentDaily.DailyLabel())
+	const mostConfusedLabel = "The pair I confuse most"
+	lookalikeLabels := []string{mostConfusedLabel}
+	labelToLookalike := map[string]string{mostConfusedLabel: ""}
+	selectedLookalike := mostConfusedLabel
+	for _, g := range lookalikeGroups {
+		lookalikeLabels = append(lookalikeLabels, g.Label())
+		labelToLookalike[g.Label()] = g.ID()
+		if g.ID() == currentLookalike {
+			selectedLookalike = g.Label()
+		}
+	}
+	lookalikeSelect := widget.NewSelect(lookalikeLabels, nil)
+	lookalikeSelect.SetSelected(selectedLookalike)
+	modeSelect := widget.NewSelect(modeLabels, func(label string) {
+		mode := labelToMode[label]
+		if mode == kanacore.ModeTimeAttack {
+			durationSelect.Enable()
+		} else {
+			durationSelect.Disable()
+		}
+		if mode == kanacore.ModeZen {
+			goalSelect.Enable()
+		} else {
+			goalSelect.Disable()
+		}
+		if mode == kanacore.ModeLookalike {
+			lookalikeSelect.Enable()
+		} else {
+			lookalikeSelect.Disable()
+		}
+	})
+	modeSelect.SetSelected(currentMode.Label())
+
 	form := container.NewVBox(
+		widget.NewLabel("Game mode"),
+		modeSelect,
+		durationSelect,
+		goalSelect,
+		lookalikeSelect,
+		widget.NewSeparator(),
 		widget.NewLabel("Kana Rows"),
 		rowCheck,
+		charPicker.Container,
 		widget.NewSeparator(),
 		autoCheck,
+		widget.NewForm(widget.NewFormItem("Curriculum", curriculumSelect)),
+		widget.NewAccordion(masteryItem),
 		widget.NewSeparator(),
 		widget.NewLabel("Score limit (0 = endless)"),
 		limitEntry,
+		widget.NewSeparator(),
+		widget.NewLabel("When several tiles match, clear the"),
+		policySelect,
+		widget.NewSeparator(),
+		widget.NewLabel("Daily goal (meet it every day to build a streak)"),
+		dailySelect,
+		widget.NewSeparator(),
+		ghostCheck,
 	)
 
 	dialog.ShowCustomConfirm("Settings", "Save", "Cancel", form, func(save bool) {
@@ -72,9 +215,11 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 			return
 		}
 
-		if err := limitEntry.Validate(); err != nil {
-			dialog.ShowError(err, win)
-			return
+		for _, entry := range []*widget.Entry{limitEntry, masteryCorrect, masteryAccuracy, masteryStreak, masteryDays} {
+			if err := entry.Validate(); err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
 		}
 
 		// Map selected labels back to IDs
@@ -91,28 +236,94 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 		if len(newRows) == 0 {
 			newRows = kanacore.DefaultRowIDs()
 		}
+		newChars := charPicker.Selected()
 
 		newAuto := autoCheck.Checked
+		newGhost := ghostCheck.Checked
+		newCurriculum := currentCurriculum
+		if id, ok := labelToCurriculum[curriculumSelect.Selected]; ok {
+			newCurriculum = id
+		}
 		newLimit := currentLimit
 		if n, err := strconv.Atoi(strings.TrimSpace(limitEntry.Text)); err == nil && n >= 0 {
 			newLimit = n
 		}
+		newMastery := kanacore.MasteryPolicy{
+			MinCorrect:  wholeNumber(masteryCorrect),
+			MinAccuracy: float64(wholeNumber(masteryAccuracy)) / 100,
+			MinStreak:   wholeNumber(masteryStreak),
+			MaxAge:      time.Duration(wholeNumber(masteryDays)) * 24 * time.Hour,
+		}
+		newPolicy := currentPolicy
+		if policy, ok := labelToPolicy[policySelect.Selected]; ok {
+			newPolicy = policy
+		}
+		newMode := currentMode
+		if mode, ok := labelToMode[modeSelect.Selected]; ok {
+			newMode = mode
+		}
+		newTimeLimit := currentTimeLimit
+		if d, ok := labelToDuration[durationSelect.Selected]; ok {
+			newTimeLimit = d
+		}
+		newGoal := currentGoal
+		if g, ok := labelToGoal[goalSelect.Selected]; ok {
+			newGoal = g
+		}
+		newDaily := currentDaily
+		if g, ok := labelToDaily[dailySelect.Selected]; ok {
+			newDaily = g
+		}
+		newLookalike := currentLookalike
+		if id, ok := labelToLookalike[lookalikeSelect.Selected]; ok {
+			newLookalike = id
+		}
 
 		// Apply under lock
 		gs.mu.Lock()
 		gs.applySelectedRows(newRows)
+		if gs.regression != nil && !kanacore.SameRows(newRows, gs.regression.Focus) {
+			gs.setRegression(nil)
+		}
+		gs.applySelectedChars(newChars)
 		gs.autoProgress = newAuto
+		if c, ok := kanacore.FindCurriculum(gs.curricula, newCurriculum); ok {
+			gs.curriculum = c
+		}
+		gs.masteryPolicy = newMastery
 		gs.scoreLimit = newLimit
+		gs.targetPolicy = newPolicy
+		// Changing the mode or clock mid-session restarts the countdown.
+		restart := newMode != currentMode || newTimeLimit != gs.timeLimit || newGoal != gs.zenGoal || newLookalike != currentLookalike
+		if restart {
+			gs.sessionStart = gs.now()
+		}
+		// A running drill continues unless another mode was picked.
+		if !drilling || newMode != currentMode {
+			gs.switchMode(newMode)
+		}
+		gs.timeLimit = newTimeLimit
+		gs.zenGoal = newGoal
+		// A ghost race starts with the next session, from a score of zero.
+		gs.ghostRace = newGhost
+		if restart {
+			gs.reseed()
+		}
+		gs.lookalikeChoice = newLookalike
+		gs.resolveLookalike()
+		gs.savePracticeDay(gs.practice.SetGoal(newDaily, gs.now()))
 
-		// Remove in-flight tiles whose row is now deselected
+		// Remove in-flight tiles whose kana are now deselected or that ask
+		// for the other answer direction
 		filtered := gs.tiles[:0]
 		for _, t := range gs.tiles {
-			if rowID, ok := kanacore.CharToRow[t.kana.Char]; ok && !gs.selectedRows[rowID] {
+			if !gs.tileSelected(t) || t.kana.Reverse != gs.mode.Production() {
 				continue
 			}
 			filtered = append(filtered, t)
 		}
 		gs.tiles = filtered
+		gs.refreshChoices()
 
 		// Rebuild the canvas-object snapshot so the renderer reflects removals.
 		gs.buildSnapshot()
@@ -122,11 +333,48 @@ func showSettingsDialog(gs *GameState, statsPanel *StatsPanel, gameCanvas *GameC
 		// Persist to store
 		if gs.store != nil {
 			_ = gs.store.SaveSelectedRows(newRows)
+			_ = gs.store.SaveSelectedChars(newChars)
 			_ = gs.store.SaveAutoProgress(newAuto)
+			_ = gs.store.SaveCurriculum(newCurriculum)
+			_ = gs.store.SaveMasteryPolicy(newMastery.String())
 			_ = gs.store.SaveScoreLimit(newLimit)
+			_ = gs.store.SaveTargetPolicy(string(newPolicy))
+			_ = gs.store.SaveGameMode(string(newMode))
+			_ = gs.store.SaveTimeLimit(int(newTimeLimit / time.Second))
+			_ = gs.store.SaveZenGoal(newGoal.String())
+			_ = gs.store.SaveLookalikeGroup(newLookalike)
+			_ = gs.store.SaveDailyGoal(newDaily.String())
+			_ = gs.store.SaveGhostRace(newGhost)
 		}
 
 		statsPanel.Update(snap)
+		if gs.inputBar != nil {
+			gs.inputBar.Update(snap)
+		}
 		gameCanvas.Refresh()
 	}, win)
 }
+
+// newWholeNumberEntry returns an entry holding value that accepts whole
+// numbers of zero or more, up to max when max is positive.
+func newWholeNumberEntry(value, max int) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetText(strconv.Itoa(value))
+	entry.Validator = func(s string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			return fmt.Errorf("enter a whole number ≥ 0")
+		}
+		if max > 0 && n > max {
+			return fmt.Errorf("enter a number up to %d", max)
+		}
+		return nil
+	}
+	return entry
+}
+
+// wholeNumber reads an entry validated by newWholeNumberEntry.
+func wholeNumber(entry *widget.Entry) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(entry.Text))
+	return n
+}
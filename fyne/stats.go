@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -15,12 +16,42 @@ import (
 type StatsSnapshot struct {
 	SessionStats  map[string]store.KanaStats
 	SelectedRows  map[string]bool
+	SelectedChars []string // picked characters in row order; empty when rows apply
+	Levels        map[string]kanacore.MasteryLevel
 	MissedKanas   []kanacore.Kana
 	Score         int
 	ScoreLimit    int
+	Mode          kanacore.GameMode
+	TimeLimit     time.Duration
+	TimeLeft      time.Duration
+	ZenGoal       kanacore.Goal
+	Goal          kanacore.Goal // goal ending the session in modes that have one
+	Elapsed       time.Duration
+	SessionDay    string // DayKey of the session start, naming the daily challenge
+	PreviousBest  int
+	HasStore      bool
+	Breakdown     kanacore.ScoreBreakdown
+	Combo         int
+	Multiplier    int
 	Missed        int
 	UnlockMessage string
 	UnlockAt      time.Time
+	Regression    string // why an auto-regression narrowed the rows, if one is active
+	NextLesson    string // curriculum and lesson auto-progression unlocks next, if any
+	Hint          string // memory hint for the kana missed last, while it is shown
+	DailyGoal     kanacore.Goal
+	DailyProgress string // today's progress towards the daily goal
+	DayStreak     int    // consecutive days the daily goal was met
+	LongestStreak int
+	Badges        []kanacore.Achievement // achievements earned this session
+	Choices       []string               // multiple-choice answers for the most urgent tile
+	DrillResults  []kanacore.DrillComparison
+	Lookalike     kanacore.LookalikeGroup   // group dropped in look-alike drills
+	Mixups        []kanacore.LookalikeMixup // this session's look-alike confusions
+	Racing        bool                      // the session is racing a ghost of the best recorded run
+	GhostScore    int                       // ghost's score at the same point of its run
+	GhostFinal    int                       // score the ghost's run ended with
+	GhostResult   string                    // how the session finished against its ghost
 }
 
 // StatsPanel shows hiragana progress, active rows, and missed characters.
@@ -32,8 +63,13 @@ type StatsPanel struct {
 	missLabels  map[string]*widget.Label
 	missEmpty   *widget.Label
 	rowBox      *fyne.Container
+	charsLabel  *widget.Label
+	focusLabel  *widget.Label
+	lessonLabel *widget.Label
 	missBox     *fyne.Container
 	unlockLabel *widget.Label
+	dailyLabel  *widget.Label
+	streakLabel *widget.Label
 	container   *container.Scroll
 }
 
@@ -43,7 +79,10 @@ func newStatsPanel() *StatsPanel {
 		rowLabels:   make(map[string]*widget.Label),
 		missLabels:  make(map[string]*widget.Label),
 		unlockLabel: widget.NewLabel(""),
+		dailyLabel:  widget.NewLabel(""),
+		streakLabel: widget.NewLabel(""),
 	}
+	p.unlockLabel.Wrapping = fyne.TextWrapWord
 
 	// Build progress grid (5 columns: a, i, u, e, o)
 	gridItems := make([]fyne.CanvasObject, 0)
@@ -56,14 +95,41 @@ func newStatsPanel() *StatsPanel {
 	}
 	grid := container.NewGridWithColumns(5, gridItems...)
 
+	legend := container.NewHBox()
+	for _, entry := range []struct {
+		level kanacore.MasteryLevel
+		text  string
+	}{
+		{kanacore.LevelRed, "■ missed"},
+		{kanacore.LevelYellow, "■ learning"},
+		{kanacore.LevelGreen, "■ mastered"},
+	} {
+		lbl := widget.NewLabel(entry.text)
+		lbl.Importance = levelImportance(entry.level)
+		legend.Add(lbl)
+	}
+
 	// Pre-create row labels (one per known row), hidden by default.
 	p.rowBox = container.NewVBox()
+	p.charsLabel = widget.NewLabel("")
+	p.charsLabel.Wrapping = fyne.TextWrapWord
+	p.charsLabel.Hide()
+	p.rowBox.Add(p.charsLabel)
 	for _, row := range kanacore.AllKanaRows {
 		lbl := widget.NewLabel("")
 		lbl.Hide()
 		p.rowLabels[row.ID] = lbl
 		p.rowBox.Add(lbl)
 	}
+	p.focusLabel = widget.NewLabel("")
+	p.focusLabel.Wrapping = fyne.TextWrapWord
+	p.focusLabel.Importance = widget.WarningImportance
+	p.focusLabel.Hide()
+	p.rowBox.Add(p.focusLabel)
+	p.lessonLabel = widget.NewLabel("")
+	p.lessonLabel.Wrapping = fyne.TextWrapWord
+	p.lessonLabel.Hide()
+	p.rowBox.Add(p.lessonLabel)
 
 	// Pre-create missed-kana labels (one per character), hidden by default.
 	p.missBox = container.NewVBox()
@@ -79,8 +145,13 @@ func newStatsPanel() *StatsPanel {
 	p.missBox.Add(p.missEmpty)
 
 	p.container = container.NewVScroll(container.NewVBox(
+		widget.NewLabel("TODAY"),
+		p.dailyLabel,
+		p.streakLabel,
+		widget.NewSeparator(),
 		widget.NewLabel("PROGRESS"),
 		grid,
+		legend,
 		widget.NewSeparator(),
 		widget.NewLabel("ACTIVE ROWS"),
 		p.rowBox,
@@ -94,13 +165,36 @@ func newStatsPanel() *StatsPanel {
 	return p
 }
 
+// formatStreak describes the current and longest day streaks.
+func formatStreak(snap StatsSnapshot) string {
+	return fmt.Sprintf("Streak: %d %s (best %d)", snap.DayStreak, kanacore.DayWord(snap.DayStreak), snap.LongestStreak)
+}
+
+// levelImportance colours a progress cell by mastery level: red, yellow or green.
+func levelImportance(level kanacore.MasteryLevel) widget.Importance {
+	switch level {
+	case kanacore.LevelRed:
+		return widget.DangerImportance
+	case kanacore.LevelYellow:
+		return widget.WarningImportance
+	case kanacore.LevelGreen:
+		return widget.SuccessImportance
+	default:
+		return widget.MediumImportance
+	}
+}
+
 func (p *StatsPanel) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(p.container)
 }
 
 // Update refreshes all labels from the snapshot.
 func (p *StatsPanel) Update(snap StatsSnapshot) {
+	p.dailyLabel.SetText(snap.DailyProgress)
+	p.streakLabel.SetText(formatStreak(snap))
+
 	for char, lbl := range p.charLabels {
+		lbl.Importance = levelImportance(snap.Levels[char])
 		count := snap.SessionStats[char].CorrectCount
 		if count > 0 {
 			lbl.SetText(fmt.Sprintf("%d", count))
@@ -114,7 +208,7 @@ func (p *StatsPanel) Update(snap StatsSnapshot) {
 		if !ok {
 			continue
 		}
-		if snap.SelectedRows[row.ID] {
+		if len(snap.SelectedChars) == 0 && snap.SelectedRows[row.ID] {
 			lbl.SetText("• " + row.Label)
 			lbl.Show()
 		} else {
@@ -123,6 +217,27 @@ func (p *StatsPanel) Update(snap StatsSnapshot) {
 		}
 	}
 
+	if len(snap.SelectedChars) > 0 {
+		p.charsLabel.SetText("Characters: " + strings.Join(snap.SelectedChars, " "))
+		p.charsLabel.Show()
+	} else {
+		p.charsLabel.Hide()
+	}
+
+	if snap.Regression != "" && len(snap.SelectedChars) == 0 {
+		p.focusLabel.SetText("Focused: " + snap.Regression)
+		p.focusLabel.Show()
+	} else {
+		p.focusLabel.Hide()
+	}
+
+	if snap.NextLesson != "" {
+		p.lessonLabel.SetText(snap.NextLesson)
+		p.lessonLabel.Show()
+	} else {
+		p.lessonLabel.Hide()
+	}
+
 	seen := make(map[string]bool)
 	for _, k := range snap.MissedKanas {
 		if seen[k.Char] {
@@ -145,7 +260,7 @@ func (p *StatsPanel) Update(snap StatsSnapshot) {
 		p.missEmpty.Hide()
 	}
 
-	if snap.UnlockMessage != "" && time.Since(snap.UnlockAt) < 5*time.Second {
+	if snap.UnlockMessage != "" && time.Since(snap.UnlockAt) < unlockMessageDuration {
 		p.unlockLabel.SetText(snap.UnlockMessage)
 	} else {
 		p.unlockLabel.SetText("")
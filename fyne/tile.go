@@ -2,6 +2,7 @@ package main
 
 import (
 	"image/color"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -11,40 +12,76 @@ import (
 const (
 	tileW float32 = 52
 	tileH float32 = 60
+	// glyphW approximates the advance of one kana glyph at TextSize 32.
+	glyphW float32 = 32
+	// latinW approximates the advance of one romaji letter at TextSize 32.
+	latinW float32 = 18
 )
 
+// textWidthFor approximates the rendered width of tile text at TextSize 32.
+func textWidthFor(text string) float32 {
+	var w float32
+	for _, r := range text {
+		if r < 0x80 {
+			w += latinW
+		} else {
+			w += glyphW
+		}
+	}
+	return w
+}
+
+// tileWidthFor returns the face width for a tile, widening it for words.
+func tileWidthFor(text string) float32 {
+	if w := textWidthFor(text) + 20; w > tileW {
+		return w
+	}
+	return tileW
+}
+
 // KanaTile is a falling kana card rendered as three canvas objects.
 type KanaTile struct {
 	kana   kanacore.Kana
 	pos    fyne.Position
+	width  float32
 	shadow *canvas.Rectangle
 	face   *canvas.Rectangle
 	text   *canvas.Text
 }
 
 func newKanaTile(k kanacore.Kana) *KanaTile {
+	width := tileWidthFor(k.Display())
+
 	shadow := canvas.NewRectangle(color.RGBA{R: 0xb8, G: 0x95, B: 0x6a, A: 0xff})
-	shadow.Resize(fyne.NewSize(tileW, tileH))
+	shadow.Resize(fyne.NewSize(width, tileH))
 
 	face := canvas.NewRectangle(color.RGBA{R: 0xee, G: 0xdf, B: 0xc0, A: 0xff})
-	face.Resize(fyne.NewSize(tileW, tileH))
+	face.Resize(fyne.NewSize(width, tileH))
 
-	text := canvas.NewText(k.Char, color.RGBA{R: 0x2c, G: 0x1a, B: 0x0e, A: 0xff})
+	text := canvas.NewText(k.Display(), color.RGBA{R: 0x2c, G: 0x1a, B: 0x0e, A: 0xff})
 	text.TextSize = 32
 	text.Alignment = fyne.TextAlignCenter
 
-	t := &KanaTile{kana: k, shadow: shadow, face: face, text: text}
+	t := &KanaTile{kana: k, width: width, shadow: shadow, face: face, text: text}
 	t.Move(fyne.NewPos(0, 0))
 	return t
 }
 
 // Move updates the positions of all three canvas objects atomically.
+// The kana's X/Y mirror pos so targeting can compare tiles directly.
 func (t *KanaTile) Move(pos fyne.Position) {
 	t.pos = pos
+	t.kana.X = pos.X
+	t.kana.Y = pos.Y
 	t.shadow.Move(fyne.NewPos(pos.X+3, pos.Y+3))
 	t.face.Move(pos)
-	// Centre text within face (approximate — kana glyphs ~18px wide at TextSize 32)
-	textX := pos.X + (tileW-18)/2
+	// Centre text within face (approximate — single kana glyphs render ~18px
+	// wide at TextSize 32; longer text is laid out per textWidthFor).
+	textW := float32(18)
+	if runes := []rune(t.kana.Display()); len(runes) > 1 {
+		textW = textWidthFor(t.kana.Display())
+	}
+	textX := pos.X + (t.width-textW)/2
 	t.text.Move(fyne.NewPos(textX, pos.Y+10))
 }
 
@@ -52,3 +89,26 @@ func (t *KanaTile) Move(pos fyne.Position) {
 func (t *KanaTile) Objects() []fyne.CanvasObject {
 	return []fyne.CanvasObject{t.shadow, t.face, t.text}
 }
+
+// missRevealDuration is how long a missed kana's romaji stays visible in zen mode.
+const missRevealDuration = 2 * time.Second
+
+// MissReveal briefly shows the romaji of a kana that fell off the bottom.
+type MissReveal struct {
+	kana kanacore.Kana
+	at   time.Time
+	text *canvas.Text
+}
+
+func newMissReveal(k kanacore.Kana, x, canvasH float32, at time.Time) *MissReveal {
+	text := canvas.NewText(k.Char+" = "+k.Romaji, WarmPaperTheme().kanaColor(colorMiss))
+	text.TextSize = 18
+	text.TextStyle = fyne.TextStyle{Bold: true}
+	text.Move(fyne.NewPos(x, canvasH-28))
+	return &MissReveal{kana: k, at: at, text: text}
+}
+
+// Expired reports whether the reveal has been shown long enough.
+func (r *MissReveal) Expired(now time.Time) bool {
+	return now.Sub(r.at) >= missRevealDuration
+}
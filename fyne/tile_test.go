@@ -32,3 +32,19 @@ func TestKanaTileObjectsReturnsThree(t *testing.T) {
 		t.Errorf("expected 3 canvas objects, got %d", len(tile.Objects()))
 	}
 }
+
+func TestKanaTileWidensForWords(t *testing.T) {
+	test.NewApp()
+	single := newKanaTile(kanacore.Kana{Char: "ね", Romaji: "ne"})
+	word := newKanaTile(kanacore.Kana{Char: "さかな", Romaji: "sakana"})
+
+	if single.face.Size().Width != tileW {
+		t.Errorf("single kana width: got %v, want %v", single.face.Size().Width, tileW)
+	}
+	if word.face.Size().Width <= tileW {
+		t.Errorf("expected word tile wider than %v, got %v", tileW, word.face.Size().Width)
+	}
+	if word.shadow.Size() != word.face.Size() {
+		t.Errorf("shadow size %v does not match face %v", word.shadow.Size(), word.face.Size())
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"kana/kanacore"
+	"kana/store"
+)
+
+// versusPrefixKeys move the keyboard focus when typed into either player's
+// box: to the left player's box, then the right's.
+var versusPrefixKeys = [2]string{"[", "]"}
+
+// versusPlayer is one side of a split-screen race.
+type versusPlayer struct {
+	name   string
+	gs     *GameState
+	canvas *GameCanvas
+	entry  *widget.Entry
+	status *widget.Label
+}
+
+// newVersusGame builds one player's game with the rules of base. Players have
+// no store of their own; starting together gives both the same seed.
+func newVersusGame(base *GameState, start time.Time) *GameState {
+	gs := NewGameState(nil, nil)
+	base.mu.Lock()
+	mode := base.mode
+	if mode == kanacore.ModeDrill {
+		mode = base.drillReturn
+	}
+	if !mode.SplitScreen() {
+		mode = kanacore.DefaultGameMode
+	}
+	gs.mode = mode
+	gs.timeLimit = base.timeLimit
+	gs.zenGoal = base.zenGoal
+	gs.scoreLimit = base.scoreLimit
+	gs.targetPolicy = base.targetPolicy
+	gs.lookalike = base.lookalike
+	gs.selectedRows = make(map[string]bool, len(base.selectedRows))
+	for id, on := range base.selectedRows {
+		gs.selectedRows[id] = on
+	}
+	gs.selectedChars = make(map[string]bool, len(base.selectedChars))
+	for char, on := range base.selectedChars {
+		gs.selectedChars[char] = on
+	}
+	base.mu.Unlock()
+
+	gs.sessionStart = start
+	gs.reseed()
+	return gs
+}
+
+// statusText describes the player's score, misses and whether they are done.
+func (p *versusPlayer) statusText() string {
+	p.gs.mu.Lock()
+	snap := p.gs.snapshot()
+	over := p.gs.over
+	p.gs.mu.Unlock()
+	text := fmt.Sprintf("%s — Score: %d  Missed: %s", p.name, snap.Score, formatMissed(snap))
+	if snap.Mode.Timed() {
+		text += "  Time: " + kanacore.FormatClock(snap.TimeLeft)
+	}
+	if over {
+		text += "  (done)"
+	}
+	return text
+}
+
+// result returns the player's outcome for the head-to-head comparison.
+func (p *versusPlayer) result() kanacore.PlayerResult {
+	p.gs.mu.Lock()
+	defer p.gs.mu.Unlock()
+	b := p.gs.scorer.Breakdown
+	return kanacore.PlayerResult{
+		Name:     p.name,
+		Score:    p.gs.score,
+		Hits:     b.Hits,
+		Wrong:    b.Wrong,
+		Missed:   p.gs.missed,
+		MaxCombo: b.MaxCombo,
+	}
+}
+
+// showVersusSetup asks for the two players' names, then opens the race.
+func showVersusSetup(base *GameState, st *store.Store, win fyne.Window) {
+	var profiles []string
+	if st != nil {
+		profiles, _ = st.Profiles()
+	}
+	left := widget.NewSelectEntry(profiles)
+	left.SetText("Player 1")
+	right := widget.NewSelectEntry(profiles)
+	right.SetText("Player 2")
+	left.Validator = func(v string) error { return profileName(v, "") }
+	right.Validator = func(v string) error { return profileName(v, left.Text) }
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Left player", left),
+		widget.NewFormItem("Right player", right),
+	}
+	dialog.ShowForm("Split screen", "Start", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		names := [2]string{strings.TrimSpace(left.Text), strings.TrimSpace(right.Text)}
+		showVersusWindow(base, st, names)
+	}, win)
+}
+
+// profileName validates a split-screen player's name, which must differ
+// from other's.
+func profileName(v, other string) error {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return fmt.Errorf("enter a name")
+	}
+	if v == strings.TrimSpace(other) {
+		return fmt.Errorf("pick a different name from the left player")
+	}
+	return nil
+}
+
+// showVersusWindow races two players side by side in a window of its own,
+// each typing into their own box on the same keyboard. Typing [ or ] in
+// either box moves the keyboard focus to the left or right player's box.
+func showVersusWindow(base *GameState, st *store.Store, names [2]string) {
+	w := fyne.CurrentApp().NewWindow("Kana — Split screen")
+	w.Resize(fyne.NewSize(900, 620))
+
+	start := time.Now()
+	var players [2]*versusPlayer
+	for i := range players {
+		gs := newVersusGame(base, start)
+		players[i] = &versusPlayer{
+			name:   names[i],
+			gs:     gs,
+			canvas: newGameCanvas(gs),
+			entry:  widget.NewEntry(),
+			status: widget.NewLabel(""),
+		}
+	}
+
+	columns := make([]fyne.CanvasObject, len(players))
+	production := players[0].gs.mode.Production()
+	for i, p := range players {
+		p.entry.SetPlaceHolder(fmt.Sprintf("%s types here (%s)", p.name, versusPrefixKeys[i]))
+		if production {
+			p.entry.SetPlaceHolder(fmt.Sprintf("%s types kana here (%s)", p.name, versusPrefixKeys[i]))
+		}
+		p.entry.OnChanged = func(text string) {
+			for j, key := range versusPrefixKeys {
+				if strings.HasSuffix(text, key) {
+					p.entry.SetText(strings.TrimSuffix(text, key))
+					w.Canvas().Focus(players[j].entry)
+					return
+				}
+			}
+			if production {
+				if converted := kanacore.RomajiToHiragana(text); converted != text {
+					p.entry.SetText(converted)
+					p.entry.CursorColumn = len([]rune(converted))
+				}
+			}
+		}
+		p.entry.OnSubmitted = func(text string) {
+			p.gs.checkAnswer(text)
+			p.entry.SetText("")
+			p.status.SetText(p.statusText())
+		}
+		p.status.SetText(p.statusText())
+		columns[i] = container.NewBorder(p.status, p.entry, nil, nil, p.canvas)
+	}
+
+	endBtn := widget.NewButton("End race", func() {
+		for _, p := range players {
+			p.gs.mu.Lock()
+			p.gs.endGame("quit")
+			p.gs.mu.Unlock()
+		}
+	})
+	help := widget.NewLabel(fmt.Sprintf("Type %s or %s in either box to switch to the left or right player.",
+		versusPrefixKeys[0], versusPrefixKeys[1]))
+	w.SetContent(container.NewBorder(nil, container.NewBorder(nil, nil, nil, endBtn, help), nil, nil,
+		container.NewGridWithColumns(len(columns), columns...)))
+
+	stop := make(chan struct{})
+	done := 0
+	for _, p := range players {
+		p.gs.Start(p.canvas)
+		go func(p *versusPlayer, events chan gameEvent) {
+			for event := range events {
+				if event.kind != gameOverEvent {
+					continue
+				}
+				fyne.Do(func() {
+					p.entry.Disable()
+					p.status.SetText(p.statusText())
+					done++
+					if done == len(players) {
+						showVersusResults(players, st, w)
+					}
+				})
+			}
+		}(p, p.gs.eventCh)
+	}
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					for _, p := range players {
+						p.status.SetText(p.statusText())
+					}
+				})
+			}
+		}
+	}()
+
+	w.SetOnClosed(func() {
+		close(stop)
+		for _, p := range players {
+			p.gs.Stop()
+		}
+	})
+	w.Show()
+	w.Canvas().Focus(players[0].entry)
+}
+
+// recordMatch keeps the match and each player's results under their
+// profiles, and returns the two profiles' head-to-head record.
+func recordMatch(st *store.Store, players [2]*versusPlayer, results []kanacore.PlayerResult) string {
+	if st == nil {
+		return ""
+	}
+	now := time.Now()
+	var mode kanacore.GameMode
+	for _, p := range players {
+		p.gs.mu.Lock()
+		stats := profileStats(p.gs.tallies, p.gs.currentStreak, now)
+		track := p.gs.statsTrack()
+		mode = p.gs.mode
+		p.gs.mu.Unlock()
+		_ = st.AddProfileStats(p.name, track, stats)
+	}
+	_ = st.SaveMatch(store.MatchRecord{
+		PlayedAt: now,
+		Mode:     string(mode),
+		Players:  [2]string{players[0].name, players[1].name},
+		Scores:   [2]int{results[0].Score, results[1].Score},
+		Winner:   kanacore.MatchWinner(results),
+	})
+	a, b, draws, err := st.HeadToHead(players[0].name, players[1].name)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("Head to head: %s %d – %d %s, %d drawn", players[0].name, a, b, players[1].name, draws)
+}
+
+// profileStats turns a session's results per character into the increments
+// kept under a player profile.
+func profileStats(tallies map[string]kanacore.CharTally, streaks map[string]int, at time.Time) []store.KanaStats {
+	stats := make([]store.KanaStats, 0, len(tallies))
+	for char, t := range tallies {
+		stats = append(stats, store.KanaStats{
+			Char:         char,
+			CorrectCount: t.Correct,
+			MissCount:    t.Missed,
+			Streak:       streaks[char],
+			LastSeen:     at,
+		})
+	}
+	return stats
+}
+
+// showVersusResults shows the head-to-head results once both players are done.
+func showVersusResults(players [2]*versusPlayer, st *store.Store, win fyne.Window) {
+	results := []kanacore.PlayerResult{players[0].result(), players[1].result()}
+	record := recordMatch(st, players, results)
+	content := resultsTable(results)
+	if record != "" {
+		content.Add(widget.NewLabel(record))
+	}
+	dialog.ShowCustom("Head to head", "Close", content, win)
+}
+
+// resultsTable announces the winner above each player's results.
+func resultsTable(results []kanacore.PlayerResult) *fyne.Container {
+	winner := kanacore.MatchWinner(results)
+	table := container.NewGridWithColumns(7)
+	for _, h := range []string{"Player", "Score", "Hits", "Wrong", "Missed", "Combo", "Accuracy"} {
+		table.Add(widget.NewLabelWithStyle(h, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	}
+	for i, r := range results {
+		name := r.Name
+		if i == winner {
+			name = "🏆 " + name
+		}
+		for _, cell := range []string{
+			name,
+			fmt.Sprint(r.Score),
+			fmt.Sprint(r.Hits),
+			fmt.Sprint(r.Wrong),
+			fmt.Sprint(r.Missed),
+			fmt.Sprint(r.MaxCombo),
+			fmt.Sprintf("%.0f%%", r.Accuracy()*100),
+		} {
+			table.Add(widget.NewLabel(cell))
+		}
+	}
+	return container.NewVBox(
+		widget.NewLabelWithStyle(kanacore.MatchVerdict(results), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		table,
+	)
+}
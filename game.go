@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,17 +15,49 @@ import (
 type Model struct {
 	Kanas           []*kanacore.Kana
 	CharacterSet    kanacore.CharacterSet
+	Words           []kanacore.Word // vocabulary for word mode
 	Width           int
-	Height          int
+	Height          int // Height of the playing field
+	WindowHeight    int // Height of the terminal; 0 until it is known
 	GameWidth       int // Width of the playing field (1/3 of total)
 	Score           int
+	Scoring         kanacore.Scorer
 	ScoreLimit      int
+	Mode            kanacore.GameMode
+	TimeLimit       time.Duration // countdown length in timed modes
+	ZenGoal         kanacore.Goal
+	MissReveals     []MissReveal      // recently missed kana whose romaji is shown at the bottom
+	Mnemonics       map[string]string // the player's own memory hints, overriding the bundled ones
+	Hint            string            // memory hint for the kana missed last
+	HintAt          time.Time
+	PreviousBest    int // best result for this mode before the session was saved
 	Missed          int
 	Input           string
+	ChoiceFor       *kanacore.Kana // tile the multiple-choice answers are for
+	Choices         []string
+	Confusions      map[string]map[string]int     // wrong romaji given per kana
+	SessionMixups   map[string]map[string]int     // wrong romaji given per kana this session
+	Tallies         map[string]kanacore.CharTally // this session's results per kana
+	DrillChars      []string                      // characters practised in drill mode
+	DrillBefore     map[string]kanacore.CharTally // drilled characters' results in the session that was drilled
+	LookalikeChoice string                        // configured look-alike group ID; "" drills the most confused group
+	Lookalike       kanacore.LookalikeGroup       // group dropped in look-alike drills
+	PrevLookalike   string                        // last kana dropped in a look-alike drill
+	Seed            int64                         // seeds this session's tile spawning
+	Session         int                           // numbers the sessions played; tick and spawn messages of earlier ones are dropped
+	Rand            *rand.Rand                    // draws tile characters, positions and speeds
+	Clock           kanacore.Clock                // time source of the game logic
+	Recorder        *kanacore.Recorder            // records the running session for replay
+	Replay          *kanacore.Replay              // recording of the session that just ended
+	Viewer          *ReplayViewer                 // replay being watched from the game-over screen
+	GhostRace       bool                          // race a ghost of the best recorded run
+	Ghost           *kanacore.Ghost               // recorded run the session is racing
+	GhostResult     string                        // how the session finished against its ghost
 	GameOver        bool
 	GameOverReason  string
 	LastSpawn       time.Time
 	LastUpdate      time.Time
+	SessionStart    time.Time
 	MissedKanas     []kanacore.Kana
 	OverallStats    map[string]store.KanaStats
 	SessionStats    map[string]store.KanaStats
@@ -31,33 +65,84 @@ type Model struct {
 	SessionDirty    bool
 	Store           *store.Store
 	SelectedRows    map[string]bool
+	SelectedChars   map[string]bool // individually picked characters; overrides SelectedRows when non-empty
 	AutoProgress    bool
-	NewlyUnlocked   []string // Row IDs unlocked during current session
-	UnlockMessage   string   // Message to display when rows are unlocked
+	MasteryPolicy   kanacore.MasteryPolicy
+	TargetPolicy    kanacore.TargetPolicy
+	Curricula       []kanacore.Curriculum
+	Curriculum      kanacore.Curriculum // drives auto-progression
+	NewlyUnlocked   []string            // Lesson IDs unlocked during current session
+	RowHistory      kanacore.RowHistory
+	Regression      *kanacore.Regression // active auto-regression narrowing SelectedRows
+	UnlockMessage   string               // Message to display when rows are unlocked
 	UnlockMessageAt time.Time
+	Practice        *kanacore.PracticeLog // per-day totals towards the daily goal
+	Achievements    *kanacore.AchievementTracker
+	SessionBadges   []kanacore.Achievement // achievements earned this session
+	ShowCalendar    bool                   // game-over screen shows the practice calendar
 }
 
-// Message types for the Bubble Tea update loop
-type tickMsg time.Time
-type spawnMsg time.Time
+// MissReveal briefly shows the romaji of a kana that reached the bottom.
+type MissReveal struct {
+	Kana kanacore.Kana
+	At   time.Time
+}
+
+// missRevealDuration is how long a missed kana's romaji stays visible in zen mode.
+const missRevealDuration = 2 * time.Second
+
+// unlockMessageDuration is how long unlock, regression and badge messages stay
+// in the status line.
+const unlockMessageDuration = 5 * time.Second
 
-// InitialModel creates a new game model with default values
-func InitialModel(st *store.Store) Model {
+// hintDuration is how long a missed kana's memory hint stays in the status line.
+const hintDuration = 4 * time.Second
+
+// Message types for the Bubble Tea update loop. Tick and spawn messages
+// carry the session they were scheduled for, so a loop left over from an
+// earlier session stops instead of running beside the new one.
+type tickMsg struct{ session int }
+type spawnMsg struct{ session int }
+
+// InitialModel creates a new game model with default values. curricula are
+// the curricula to choose from; nil offers the bundled ones.
+func InitialModel(st *store.Store, curricula []kanacore.Curriculum) Model {
 	model := Model{
 		Kanas:         make([]*kanacore.Kana, 0),
 		CharacterSet:  kanacore.Hiragana(),
+		Words:         kanacore.N5Words(),
 		Width:         80,
 		Height:        24,
 		GameWidth:     26, // 1/3 of 80
 		LastSpawn:     time.Now(),
 		LastUpdate:    time.Now(),
+		SessionStart:  time.Now(),
+		Scoring:       kanacore.Scorer{Rules: kanacore.DefaultScoreRules()},
 		ScoreLimit:    store.DefaultScoreLimit,
+		Mode:          kanacore.DefaultGameMode,
+		TimeLimit:     kanacore.DefaultTimeAttackDuration,
+		ZenGoal:       kanacore.DefaultZenGoal,
 		MissedKanas:   make([]kanacore.Kana, 0),
 		OverallStats:  make(map[string]store.KanaStats),
 		SessionStats:  make(map[string]store.KanaStats),
 		CurrentStreak: make(map[string]int),
+		Confusions:    make(map[string]map[string]int),
+		SessionMixups: make(map[string]map[string]int),
+		Tallies:       make(map[string]kanacore.CharTally),
+		RowHistory:    make(kanacore.RowHistory),
 		Store:         st,
+		Clock:         kanacore.SystemClock,
 		SelectedRows:  make(map[string]bool),
+		SelectedChars: make(map[string]bool),
+		TargetPolicy:  kanacore.DefaultTargetPolicy,
+		MasteryPolicy: kanacore.DefaultMasteryPolicy(),
+		Curriculum:    kanacore.DefaultCurriculum(),
+		Practice:      kanacore.NewPracticeLog(kanacore.DefaultDailyGoal, nil),
+		Achievements:  kanacore.NewAchievementTracker(nil),
+	}
+	model.Curricula = curricula
+	if model.Curricula == nil {
+		model.Curricula = kanacore.DefaultCurricula()
 	}
 
 	model.applySelectedRows(kanacore.DefaultRowIDs())
@@ -67,10 +152,24 @@ func InitialModel(st *store.Store) Model {
 			model.applySelectedRows(rows)
 		}
 
+		if chars, err := st.SelectedChars(); err == nil {
+			model.applySelectedChars(chars)
+		}
+
+		if value, err := st.Regression(); err == nil {
+			if r, ok := kanacore.ParseRegression(value); ok {
+				model.Regression = &r
+			}
+		}
+
 		if auto, err := st.AutoProgress(); err == nil {
 			model.AutoProgress = auto
 		}
 
+		if race, err := st.GhostRace(); err == nil {
+			model.GhostRace = race
+		}
+
 		if limit, err := st.ScoreLimit(); err == nil {
 			if limit < 0 {
 				limit = 0
@@ -78,34 +177,80 @@ func InitialModel(st *store.Store) Model {
 			model.ScoreLimit = limit
 		}
 
-		if stats, err := st.KanaStatistics(); err == nil {
-			for _, stat := range stats {
-				copied := stat
-				model.OverallStats[stat.Char] = copied
-				model.CurrentStreak[stat.Char] = stat.Streak
+		if policy, err := st.TargetPolicy(); err == nil {
+			model.TargetPolicy = kanacore.ParseTargetPolicy(policy)
+		}
+
+		if policy, err := st.MasteryPolicy(); err == nil && policy != "" {
+			model.MasteryPolicy = kanacore.ParseMasteryPolicy(policy)
+		}
+
+		if hints, err := st.Mnemonics(); err == nil {
+			model.Mnemonics = hints
+		}
+
+		if id, err := st.Curriculum(); err == nil {
+			if c, ok := kanacore.FindCurriculum(model.Curricula, id); ok {
+				model.Curriculum = c
+			}
+		}
+
+		if mode, err := st.GameMode(); err == nil {
+			if parsed := kanacore.ParseGameMode(mode); !parsed.Drawn() {
+				model.Mode = parsed
 			}
 		}
+
+		if seconds, err := st.TimeLimit(); err == nil {
+			model.TimeLimit = kanacore.NormalizeTimeLimit(time.Duration(seconds) * time.Second)
+		}
+
+		if goal, err := st.ZenGoal(); err == nil {
+			model.ZenGoal = kanacore.ParseGoal(goal)
+		}
+
+		if confusions, err := st.Confusions(); err == nil {
+			model.Confusions = confusions
+		}
+
+		if group, err := st.LookalikeGroup(); err == nil {
+			model.LookalikeChoice = group
+		}
+
+		model.Practice = loadPracticeLog(st)
+
+		if earned, err := st.Achievements(); err == nil {
+			model.Achievements = kanacore.NewAchievementTracker(earned)
+		}
+		model.loadOverallStats()
 	}
+	model.resolveLookalike()
+	model.reseed()
 
 	return model
 }
 
 // Init initializes the game and returns the initial commands
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(tickCmd(), spawnCmd())
+	m.Practice.Start(m.now())
+	return tea.Batch(tickCmd(m.Session), spawnCmd(m.Mode.SpawnInterval(), m.Session))
 }
 
-// tickCmd returns a command that sends tick messages at regular intervals
-func tickCmd() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// tickInterval is how often falling tiles move one step.
+const tickInterval = 100 * time.Millisecond
+
+// tickCmd returns a command that sends session's next tick message
+func tickCmd(session int) tea.Cmd {
+	return tea.Tick(tickInterval, func(time.Time) tea.Msg {
+		return tickMsg{session: session}
 	})
 }
 
-// spawnCmd returns a command that sends spawn messages at regular intervals
-func spawnCmd() tea.Cmd {
-	return tea.Tick(4*time.Second, func(t time.Time) tea.Msg {
-		return spawnMsg(t)
+// spawnCmd returns a command that sends a spawn message for session after
+// interval
+func spawnCmd(interval time.Duration, session int) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return spawnMsg{session: session}
 	})
 }
 
@@ -114,10 +259,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
-		m.Height = msg.Height - 3 // Reserve space for status bar and instructions
+		m.WindowHeight = msg.Height
+		m.fitField()
 		m.GameWidth = m.Width / 3 // 1/3 for game area
 
 	case tea.KeyMsg:
+		if m.Viewer != nil {
+			return m, m.updateViewer(msg)
+		}
+		if m.GameOver && msg.String() == "v" && m.Replay != nil {
+			m.Viewer = newReplayViewer(m.Replay)
+			return m, replayTickCmd(m.Viewer)
+		}
+		if m.GameOver && msg.String() == "c" {
+			m.ShowCalendar = !m.ShowCalendar
+			return m, nil
+		}
+		if m.GameOver && msg.String() == "d" {
+			if m.startDrill() {
+				return m, tea.Batch(tickCmd(m.Session), spawnCmd(m.Mode.SpawnInterval(), m.Session))
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			m.mergeSessionStats()
@@ -132,83 +295,301 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			m.checkAnswer()
 			m.Input = ""
-		case "backspace":
-			if len(m.Input) > 0 {
-				m.Input = m.Input[:len(m.Input)-1]
-			}
 		default:
-			if len(msg.String()) == 1 {
-				m.Input += msg.String()
-			}
+			m.typeKey(msg)
 		}
+		m.Recorder.Input(m.now(), m.Input)
 
 	case tickMsg:
-		if !m.GameOver {
+		if msg.session == m.Session && !m.GameOver {
 			m.update()
-			return m, tickCmd()
+			return m, tickCmd(m.Session)
 		}
 
 	case spawnMsg:
-		if !m.GameOver {
+		if msg.session == m.Session && !m.GameOver {
 			m.spawnKana()
-			return m, spawnCmd()
+			return m, spawnCmd(m.Mode.SpawnInterval(), m.Session)
+		}
+
+	case replayTickMsg:
+		if msg.viewer == m.Viewer {
+			m.Viewer.advance()
+			return m, replayTickCmd(m.Viewer)
 		}
 	}
 
 	return m, nil
 }
 
-// checkAnswer checks if the player's input matches any falling kana
+// typeKey edits the input buffer with a typed key, or answers with it in
+// multiple-choice modes. Production modes keep the input converted to kana,
+// so backspace removes the kana the player sees.
+func (m *Model) typeKey(msg tea.KeyMsg) {
+	switch {
+	case msg.String() == "backspace":
+		if runes := []rune(m.Input); len(runes) > 0 {
+			m.Input = string(runes[:len(runes)-1])
+		}
+		return
+	case m.Mode.MultipleChoice():
+		if n := msg.String(); len(n) == 1 && n[0] >= '1' && n[0] <= '9' {
+			m.answerChoice(int(n[0] - '1'))
+		}
+		return
+	case len(msg.String()) == 1:
+		m.Input += msg.String()
+	case m.Mode.Production() && msg.Type == tea.KeyRunes:
+		// Kana typed through a terminal IME arrive as multi-byte runes.
+		m.Input += string(msg.Runes)
+	}
+	if m.Mode.Production() {
+		m.Input = kanacore.RomajiToHiragana(m.Input)
+	}
+}
+
+// checkAnswer checks if the player's input matches any falling kana.
+// When several tiles match, TargetPolicy picks the one to clear.
 func (m *Model) checkAnswer() {
+	candidates := make([]kanacore.Kana, len(m.Kanas))
 	for i, k := range m.Kanas {
-		if k.Romaji == m.Input {
-			m.Kanas = append(m.Kanas[:i], m.Kanas[i+1:]...)
-			m.Score += 10
-			m.recordCorrect(k.Char)
-			if m.ScoreLimit > 0 && m.Score >= m.ScoreLimit {
-				m.endGame("score")
+		candidates[i] = *k
+	}
+	m.Recorder.Submit(m.now(), m.Input)
+	i := kanacore.SelectTarget(candidates, m.answer(), float32(m.Height), m.TargetPolicy)
+	if i < 0 {
+		if input := strings.TrimSpace(m.Input); input != "" {
+			m.Scoring.Wrong()
+			m.Score = m.Scoring.Score()
+			m.recordScore()
+			m.noteConfusion(input)
+			m.observeAchievements(kanacore.EventWrong)
+		}
+		return
+	}
+	k := m.Kanas[i]
+	m.Kanas = append(m.Kanas[:i], m.Kanas[i+1:]...)
+	m.Scoring.Hit(*k, float32(m.Height))
+	m.Score = m.Scoring.Score()
+	m.Recorder.Clear(m.now(), k.Seq)
+	m.recordScore()
+	for _, char := range kanacore.SplitKana(k.Char) {
+		m.recordCorrect(char)
+	}
+	if m.Mode.UsesScoreLimit() && m.ScoreLimit > 0 && m.Score >= m.ScoreLimit {
+		m.endGame("score")
+	}
+	if m.goalReached() {
+		m.endGame("goal")
+	}
+	m.refreshChoices()
+}
+
+// answerChoice handles picking multiple-choice answer n. A correct pick
+// clears the matching tile like a typed answer; a wrong pick is penalised and
+// remembered as a confusion for the tile's kana.
+func (m *Model) answerChoice(n int) {
+	if m.ChoiceFor == nil || n < 0 || n >= len(m.Choices) {
+		return
+	}
+	romaji := m.Choices[n]
+	if romaji != m.ChoiceFor.Romaji {
+		m.Recorder.Submit(m.now(), romaji)
+		m.Scoring.Wrong()
+		m.Score = m.Scoring.Score()
+		m.recordScore()
+		m.recordConfusion(m.ChoiceFor.Char, romaji)
+		m.observeAchievements(kanacore.EventWrong)
+		return
+	}
+	m.Input = romaji
+	m.checkAnswer()
+	m.Input = ""
+}
+
+// refreshChoices points the multiple-choice answers at the most urgent tile,
+// drawing new answers when that tile changes.
+func (m *Model) refreshChoices() {
+	if !m.Mode.MultipleChoice() {
+		m.ChoiceFor, m.Choices = nil, nil
+		return
+	}
+	candidates := make([]kanacore.Kana, len(m.Kanas))
+	for i, k := range m.Kanas {
+		candidates[i] = *k
+	}
+	var question *kanacore.Kana
+	if i := kanacore.MostUrgent(candidates, float32(m.Height), m.TargetPolicy); i >= 0 {
+		question = m.Kanas[i]
+	}
+	if question == m.ChoiceFor {
+		return
+	}
+	m.ChoiceFor = question
+	m.Choices = nil
+	if question != nil {
+		chars := m.availableCharacters()
+		pool := make([]string, 0, len(chars))
+		for _, char := range chars {
+			if romaji, ok := m.CharacterSet.GetRomaji(char); ok {
+				pool = append(pool, romaji)
 			}
-			return
 		}
+		m.Choices = kanacore.Choices(question.Romaji, pool, m.Confusions[question.Char], kanacore.ChoiceCount, m.Rand)
 	}
 }
 
+// noteConfusion records a typed answer that matched no tile as a confusion
+// for the most urgent single-kana tile, when the answer is another kana's romaji.
+func (m *Model) noteConfusion(input string) {
+	if m.Mode.Production() {
+		return
+	}
+	if _, ok := m.CharacterSet.GetChar(input); !ok {
+		return
+	}
+	candidates := make([]kanacore.Kana, len(m.Kanas))
+	for i, k := range m.Kanas {
+		candidates[i] = *k
+	}
+	i := kanacore.MostUrgent(candidates, float32(m.Height), m.TargetPolicy)
+	if i < 0 || len(kanacore.SplitKana(candidates[i].Char)) != 1 {
+		return
+	}
+	m.recordConfusion(candidates[i].Char, input)
+}
+
+// recordConfusion remembers that answer was given for char.
+func (m *Model) recordConfusion(char, answer string) {
+	if m.Confusions[char] == nil {
+		m.Confusions[char] = make(map[string]int)
+	}
+	m.Confusions[char][answer]++
+	if m.SessionMixups[char] == nil {
+		m.SessionMixups[char] = make(map[string]int)
+	}
+	m.SessionMixups[char][answer]++
+	if m.Store != nil {
+		_ = m.Store.RecordConfusion(char, answer)
+	}
+}
+
+// answer returns the submitted input in the form tiles expect. In production
+// modes typed romaji is converted to kana first.
+func (m *Model) answer() string {
+	if m.Mode.Production() {
+		return kanacore.FinishRomaji(m.Input)
+	}
+	return m.Input
+}
+
 // spawnKana creates a new falling kana at a random position
 func (m *Model) spawnKana() {
+	if m.racingGhost() {
+		return
+	}
 	chars := m.availableCharacters()
 	if len(chars) == 0 {
 		return
 	}
-	char := chars[rand.Intn(len(chars))]
+	char := chars[m.Rand.Intn(len(chars))]
+	if m.Mode == kanacore.ModeLookalike {
+		char = kanacore.NextLookalike(m.Lookalike, m.PrevLookalike, m.Rand)
+		m.PrevLookalike = char
+	}
 	romaji, _ := m.CharacterSet.GetRomaji(char)
 
+	// Word mode drops a word built only from the selected characters,
+	// falling back to a single kana when no word fits the selection.
+	if m.Mode == kanacore.ModeWords {
+		if words := kanacore.FilterWords(m.Words, chars); len(words) > 0 {
+			word := words[m.Rand.Intn(len(words))]
+			char, romaji = word.Kana, word.Romaji
+		}
+	}
+
 	kana := &kanacore.Kana{
-		Char:   char,
-		Romaji: romaji,
-		X:      float32(rand.Intn(m.GameWidth-10) + 5), // Spawn only in game area
-		Y:      0,
-		Speed:  float32(0.15 + rand.Float64()*0.1),
+		Char:    char,
+		Romaji:  romaji,
+		Y:       0,
+		Speed:   float32(0.15 + m.Rand.Float64()*0.1),
+		Reverse: m.Mode.Production(),
 	}
+	maxX := m.GameWidth - kanaCellWidthFor(kana.Display()) - 5
+	if maxX < 5 {
+		maxX = 5
+	}
+	kana.X = float32(m.Rand.Intn(maxX) + 5) // Spawn only in game area
+	kana.Seq = m.Recorder.Spawn(m.now(), *kana, float64(kana.X)/float64(m.GameWidth), float64(kana.Speed)/float64(m.Height))
 	m.Kanas = append(m.Kanas, kana)
+	m.refreshChoices()
+}
+
+// spawnGhostTiles drops the tiles the ghost's run had spawned by now, scaled
+// to the field.
+func (m *Model) spawnGhostTiles() {
+	now := m.now()
+	for _, e := range m.Ghost.Due(now.Sub(m.SessionStart)) {
+		kana := &kanacore.Kana{
+			Char:    e.Char,
+			Romaji:  e.Romaji,
+			X:       float32(e.X) * float32(m.GameWidth),
+			Speed:   float32(e.Speed) * float32(m.Height),
+			Reverse: m.Mode.Production(),
+		}
+		kana.Seq = m.Recorder.Spawn(now, *kana, e.X, e.Speed)
+		m.Kanas = append(m.Kanas, kana)
+	}
+	m.refreshChoices()
+}
+
+// racingGhost reports whether the ghost's run is still dropping the
+// session's tiles.
+func (m *Model) racingGhost() bool {
+	return m.Ghost != nil && m.Ghost.Running(m.now().Sub(m.SessionStart))
 }
 
 // update moves all falling kanas and checks for misses
 func (m *Model) update() {
+	if m.Mode.Timed() && m.TimeRemaining() <= 0 {
+		m.endGame("time")
+		return
+	}
+	if m.goalReached() {
+		m.endGame("goal")
+		return
+	}
+	m.expireMissReveals()
+	if m.Ghost != nil {
+		m.spawnGhostTiles()
+	}
+	m.Recorder.Tick(m.now())
+
 	for i := len(m.Kanas) - 1; i >= 0; i-- {
 		k := m.Kanas[i]
 		k.Y += k.Speed
 		m.Kanas[i] = k
 
 		if int(k.Y) >= m.Height {
-			m.recordMiss(k.Char)
+			m.Scoring.Miss()
+			for _, char := range kanacore.SplitKana(k.Char) {
+				m.recordMiss(char)
+			}
 			m.MissedKanas = append(m.MissedKanas, *k)
 			m.Kanas = append(m.Kanas[:i], m.Kanas[i+1:]...)
 			m.Missed++
-			if m.Missed >= 10 {
+			m.Recorder.Miss(m.now(), k.Seq)
+			m.recordScore()
+			if m.Mode == kanacore.ModeZen {
+				m.MissReveals = append(m.MissReveals, MissReveal{Kana: *k, At: m.now()})
+			}
+			m.showHint(k.Char)
+			if m.Mode.EndsOnMisses() && m.Missed >= 10 {
 				m.endGame("misses")
 			}
 		}
 	}
+	m.refreshChoices()
 }
 
 func (m *Model) applySelectedRows(rows []string) {
@@ -223,11 +604,45 @@ func (m *Model) applySelectedRows(rows []string) {
 	}
 }
 
+// SetSelectedRows selects and persists rows. Choosing anything other than the
+// focus of an active auto-regression ends it.
 func (m *Model) SetSelectedRows(rows []string) {
 	m.applySelectedRows(rows)
 	if m.Store != nil {
 		_ = m.Store.SaveSelectedRows(rows)
 	}
+	if m.Regression != nil && !kanacore.SameRows(rows, m.Regression.Focus) {
+		m.setRegression(nil)
+	}
+}
+
+func (m *Model) applySelectedChars(chars []string) {
+	m.SelectedChars = make(map[string]bool, len(chars))
+	for _, char := range kanacore.NormalizeCharSelection(chars) {
+		m.SelectedChars[char] = true
+	}
+}
+
+// SetSelectedChars practises exactly the given characters instead of whole
+// rows. An empty selection returns to row selection.
+func (m *Model) SetSelectedChars(chars []string) {
+	m.applySelectedChars(chars)
+	if m.Store != nil {
+		_ = m.Store.SaveSelectedChars(m.SelectedCharList())
+	}
+}
+
+// SelectedCharList returns the individually selected characters in row order.
+func (m *Model) SelectedCharList() []string {
+	chars := make([]string, 0, len(m.SelectedChars))
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			if m.SelectedChars[char] {
+				chars = append(chars, char)
+			}
+		}
+	}
+	return chars
 }
 
 func (m *Model) SelectedRowIDs() []string {
@@ -283,33 +698,286 @@ func (m *Model) SetScoreLimit(limit int) {
 	}
 }
 
+// expireMissReveals drops reveals that have been shown long enough.
+func (m *Model) expireMissReveals() {
+	kept := m.MissReveals[:0]
+	for _, r := range m.MissReveals {
+		if m.now().Sub(r.At) < missRevealDuration {
+			kept = append(kept, r)
+		}
+	}
+	m.MissReveals = kept
+}
+
+// sessionGoal returns the goal that ends the session, if the mode has one.
+func (m *Model) sessionGoal() (kanacore.Goal, bool) {
+	switch m.Mode {
+	case kanacore.ModeZen:
+		return m.ZenGoal, true
+	case kanacore.ModeDrill:
+		return kanacore.DrillGoal(len(m.DrillChars)), true
+	case kanacore.ModeLookalike:
+		return kanacore.LookalikeGoal, true
+	}
+	return kanacore.Goal{}, false
+}
+
+// goalReached reports whether the session's goal has been met.
+func (m *Model) goalReached() bool {
+	goal, ok := m.sessionGoal()
+	return ok && goal.Reached(m.Scoring.Breakdown.Hits, m.now().Sub(m.SessionStart))
+}
+
+// startDrill begins a drill session on the characters missed in the session
+// that just ended, ignoring row selection. Reports false when nothing was missed.
+func (m *Model) startDrill() bool {
+	chars := kanacore.DrillCharacters(m.MissedKanas)
+	if len(chars) == 0 {
+		return false
+	}
+	m.mergeSessionStats()
+	m.DrillBefore = make(map[string]kanacore.CharTally, len(chars))
+	for _, char := range chars {
+		m.DrillBefore[char] = m.Tallies[char]
+	}
+	m.DrillChars = chars
+	// Drills are typed as romaji, so they count towards recognition stats.
+	wasProduction := m.Mode.Production()
+	m.Mode = kanacore.ModeDrill
+	if wasProduction {
+		m.loadOverallStats()
+	}
+
+	m.Kanas = m.Kanas[:0]
+	m.Score = 0
+	m.Scoring = kanacore.Scorer{Rules: kanacore.DefaultScoreRules()}
+	m.Missed = 0
+	m.MissedKanas = nil
+	m.MissReveals = nil
+	m.Hint = ""
+	m.PreviousBest = 0
+	m.Input = ""
+	m.ChoiceFor, m.Choices = nil, nil
+	m.Tallies = make(map[string]kanacore.CharTally)
+	m.SessionMixups = make(map[string]map[string]int)
+	m.NewlyUnlocked = nil
+	m.GameOver = false
+	m.GameOverReason = ""
+	m.ShowCalendar = false
+	m.SessionBadges = nil
+	m.Replay, m.Viewer = nil, nil
+	m.GhostResult = ""
+	m.SessionStart = m.now()
+	m.Session++
+	m.reseed()
+	m.Practice.Start(m.SessionStart)
+	return true
+}
+
+// reseed starts the session's tile sequence, the date's sequence in the
+// daily challenge, the ghost's when racing one and a fresh one otherwise,
+// and a new recording of it.
+func (m *Model) reseed() {
+	m.Seed = kanacore.SessionSeed(m.Mode, m.SessionStart)
+	m.Ghost = m.loadGhost()
+	if m.Ghost != nil {
+		m.Seed = m.Ghost.Replay.Seed
+	}
+	m.fitField()
+	m.Rand = kanacore.NewRand(m.Seed)
+	m.Recorder = kanacore.NewRecorder(m.Mode, m.Seed, m.SessionStart)
+}
+
+// fitField sizes the field to the terminal, leaving room for the status bar,
+// the instructions and, while racing a ghost, its score line. The field keeps
+// its size until the terminal's is known.
+func (m *Model) fitField() {
+	if m.WindowHeight == 0 {
+		return
+	}
+	m.Height = m.WindowHeight - 3
+	if m.Ghost != nil {
+		m.Height--
+	}
+}
+
+// loadGhost returns the recording of the best run in the current mode to
+// race, or nil when ghost races are off or no run was recorded. Drills and
+// daily challenges have their own tile sequences and are never raced.
+func (m *Model) loadGhost() *kanacore.Ghost {
+	if !m.GhostRace || m.Store == nil || m.Mode == kanacore.ModeDrill || m.Mode == kanacore.ModeDaily {
+		return nil
+	}
+	ids, err := m.Store.BestSessionIDs(string(m.Mode), m.sessionTimeLimit())
+	if err != nil {
+		return nil
+	}
+	// Sessions played before recording began have no replay; race the best
+	// one that has.
+	for _, id := range ids {
+		if r, err := kanacore.LoadReplay(m.Store.ReplayPath(id)); err == nil {
+			return kanacore.NewGhost(r)
+		}
+	}
+	return nil
+}
+
+// SetGhostRace sets and persists whether sessions race a ghost of the best
+// recorded run, starting with the next session.
+func (m *Model) SetGhostRace(enabled bool) {
+	m.GhostRace = enabled
+	if m.Store != nil {
+		_ = m.Store.SaveGhostRace(enabled)
+	}
+}
+
+// GhostScore returns the ghost's score at this point of the session.
+func (m *Model) GhostScore() int {
+	return m.Ghost.Score(m.now().Sub(m.SessionStart))
+}
+
+// now reads the model's clock.
+func (m *Model) now() time.Time {
+	return m.Clock.Now()
+}
+
+// recordScore adds the score and miss count to the session's recording.
+func (m *Model) recordScore() {
+	m.Recorder.Score(m.now(), m.Score, m.Missed)
+}
+
+// DrillResults compares the drilled characters before and during the drill.
+func (m *Model) DrillResults() []kanacore.DrillComparison {
+	return kanacore.CompareDrill(m.DrillChars, m.DrillBefore, m.Tallies)
+}
+
+// lookalikeGroups returns the curated look-alike groups plus pairs from the
+// player's confusion history.
+func (m *Model) lookalikeGroups() []kanacore.LookalikeGroup {
+	return kanacore.ExtendLookalikes(kanacore.LookalikeGroups, m.Confusions)
+}
+
+// resolveLookalike picks the group look-alike drills use.
+func (m *Model) resolveLookalike() {
+	groups := m.lookalikeGroups()
+	if g, ok := kanacore.FindLookalikeGroup(groups, m.LookalikeChoice); ok {
+		m.Lookalike = g
+		return
+	}
+	m.Lookalike = kanacore.MostConfusedLookalike(groups, m.Confusions)
+}
+
+// SetLookalikeGroup sets and persists the group drilled in look-alike mode.
+// An empty ID drills whichever group the player confuses most.
+func (m *Model) SetLookalikeGroup(id string) {
+	m.LookalikeChoice = id
+	m.resolveLookalike()
+	if m.Store != nil {
+		_ = m.Store.SaveLookalikeGroup(id)
+	}
+}
+
+// LookalikeMixups lists this session's confusions between look-alike kana.
+func (m *Model) LookalikeMixups() []kanacore.LookalikeMixup {
+	return kanacore.LookalikeMixups(m.lookalikeGroups(), m.SessionMixups)
+}
+
+// SetZenGoal sets and persists the goal that ends a zen session.
+func (m *Model) SetZenGoal(goal kanacore.Goal) {
+	m.ZenGoal = goal
+	if m.Store != nil {
+		_ = m.Store.SaveZenGoal(goal.String())
+	}
+}
+
+// SetGameMode selects the session rules and, for timed modes, the countdown length.
+// Switching between recognition and production practice reloads the overall
+// statistics for the new track.
+func (m *Model) SetGameMode(mode kanacore.GameMode, limit time.Duration) {
+	if mode.Production() != m.Mode.Production() {
+		m.mergeSessionStats()
+		m.Mode = mode
+		m.loadOverallStats()
+	}
+	m.Mode = mode
+	m.TimeLimit = kanacore.NormalizeTimeLimit(limit)
+	m.reseed()
+	m.refreshChoices()
+	if m.Store != nil {
+		_ = m.Store.SaveGameMode(string(mode))
+		_ = m.Store.SaveTimeLimit(int(m.TimeLimit / time.Second))
+	}
+}
+
+// Countdown returns the countdown length of the current timed session.
+func (m *Model) Countdown() time.Duration {
+	return m.Mode.Countdown(m.TimeLimit)
+}
+
+// TimeRemaining returns how long is left on the countdown in timed modes.
+func (m *Model) TimeRemaining() time.Duration {
+	remaining := m.Countdown() - m.now().Sub(m.SessionStart)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (m *Model) SetTargetPolicy(policy kanacore.TargetPolicy) {
+	m.TargetPolicy = policy
+	if m.Store != nil {
+		_ = m.Store.SaveTargetPolicy(string(policy))
+	}
+}
+
 func (m *Model) recordCorrect(char string) {
 	streak := m.CurrentStreak[char] + 1
 	m.CurrentStreak[char] = streak
 
+	tally := m.Tallies[char]
+	tally.Correct++
+	m.Tallies[char] = tally
+
 	stat := m.SessionStats[char]
 	stat.Char = char
 	stat.CorrectCount++
 	stat.Streak = streak
+	stat.LastSeen = m.now()
 	m.SessionStats[char] = stat
 	m.SessionDirty = true
 
+	m.RowHistory.Record(char, true)
+	m.checkAutoRegression()
+	m.recordPractice(1)
+
 	// Check for auto-progression
 	if unlocked := m.checkAutoProgression(); len(unlocked) > 0 {
 		m.NewlyUnlocked = append(m.NewlyUnlocked, unlocked...)
 		m.showUnlockMessage(unlocked)
+		m.observeAchievements(kanacore.EventUnlock)
 	}
+	m.observeAchievements(kanacore.EventCorrect)
 }
 
 func (m *Model) recordMiss(char string) {
 	m.CurrentStreak[char] = 0
 
+	tally := m.Tallies[char]
+	tally.Missed++
+	m.Tallies[char] = tally
+
 	stat := m.SessionStats[char]
 	stat.Char = char
 	stat.MissCount++
 	stat.Streak = 0
+	stat.LastSeen = m.now()
 	m.SessionStats[char] = stat
 	m.SessionDirty = true
+
+	m.RowHistory.Record(char, false)
+	m.checkAutoRegression()
+	m.recordPractice(0)
+	m.observeAchievements(kanacore.EventMiss)
 }
 
 func (m *Model) endGame(reason string) {
@@ -318,10 +986,95 @@ func (m *Model) endGame(reason string) {
 		if m.GameOverReason == "" {
 			m.GameOverReason = reason
 		}
+		m.Replay = m.Recorder.Finish(m.now(), m.GameOverReason)
+		m.Recorder = nil
+		if m.Ghost != nil {
+			m.GhostResult = kanacore.GhostResult(m.Score, m.Ghost.Final())
+		}
+		m.saveSession()
+		m.savePracticeDay(m.Practice.Stop(m.now()))
+		m.observeAchievements(kanacore.EventSessionEnd)
 	}
 	m.mergeSessionStats()
 }
 
+// saveSession persists the finished session and its score breakdown.
+func (m *Model) saveSession() {
+	if m.Store == nil {
+		return
+	}
+	timeLimit := m.sessionTimeLimit()
+	switch {
+	case m.Mode == kanacore.ModeDaily:
+		// Daily challenges are compared within the day's shared sequence.
+		y, mo, d := m.SessionStart.Date()
+		day := time.Date(y, mo, d, 0, 0, 0, 0, m.SessionStart.Location())
+		m.PreviousBest, _ = m.Store.BestHitsSince(string(m.Mode), timeLimit, day)
+	case m.Mode.Timed():
+		m.PreviousBest, _ = m.Store.BestHits(string(m.Mode), timeLimit)
+	default:
+		m.PreviousBest, _ = m.Store.BestScore(string(m.Mode), timeLimit)
+	}
+	b := m.Scoring.Breakdown
+	id, err := m.Store.SaveSession(store.SessionRecord{
+		StartedAt:     m.SessionStart,
+		EndedAt:       m.now(),
+		Mode:          string(m.Mode),
+		TimeLimit:     timeLimit,
+		Reason:        m.GameOverReason,
+		Score:         m.Score,
+		Missed:        m.Missed,
+		BasePoints:    b.Base,
+		ComboPoints:   b.Combo,
+		SpeedPoints:   b.Speed,
+		PenaltyPoints: b.Penalty,
+		Hits:          b.Hits,
+		Wrong:         b.Wrong,
+		MaxCombo:      b.MaxCombo,
+	})
+	if err == nil && m.Replay != nil {
+		_ = m.Replay.Save(m.Store.ReplayPath(id))
+	}
+}
+
+// sessionTimeLimit returns the countdown or zen time goal, in seconds, that
+// sessions are compared within; 0 when the session is not timed.
+func (m *Model) sessionTimeLimit() int {
+	switch {
+	case m.Mode.Timed():
+		return int(m.Countdown() / time.Second)
+	case m.Mode == kanacore.ModeZen && m.ZenGoal.Kind == kanacore.GoalTime:
+		return int(m.ZenGoal.Duration / time.Second)
+	}
+	return 0
+}
+
+// statsTrack returns the statistics track the current mode practises.
+func (m *Model) statsTrack() store.StatsTrack {
+	if m.Mode.Production() {
+		return store.TrackProduction
+	}
+	return store.TrackRecognition
+}
+
+// loadOverallStats replaces the overall statistics with those stored for the
+// current track.
+func (m *Model) loadOverallStats() {
+	if m.Store == nil {
+		return
+	}
+	stats, err := m.Store.TrackStatistics(m.statsTrack())
+	if err != nil {
+		return
+	}
+	m.OverallStats = make(map[string]store.KanaStats, len(stats))
+	m.CurrentStreak = make(map[string]int, len(stats))
+	for _, stat := range stats {
+		m.OverallStats[stat.Char] = stat
+		m.CurrentStreak[stat.Char] = stat.Streak
+	}
+}
+
 func (m *Model) mergeSessionStats() {
 	if !m.SessionDirty {
 		return
@@ -330,7 +1083,7 @@ func (m *Model) mergeSessionStats() {
 	// Load baseline from the store to prevent double-counting
 	var baseline map[string]store.KanaStats
 	if m.Store != nil {
-		stats, err := m.Store.KanaStatistics()
+		stats, err := m.Store.TrackStatistics(m.statsTrack())
 		if err != nil {
 			// Don't clobber persisted totals with session-only data.
 			// Leave SessionDirty true so the next merge retries.
@@ -351,8 +1104,11 @@ func (m *Model) mergeSessionStats() {
 		base.CorrectCount += session.CorrectCount
 		base.MissCount += session.MissCount
 		base.Streak = m.CurrentStreak[char]
+		if session.LastSeen.After(base.LastSeen) {
+			base.LastSeen = session.LastSeen
+		}
 		if m.Store != nil {
-			if err := m.Store.SaveKanaStats(char, base.CorrectCount, base.MissCount, base.Streak); err != nil {
+			if err := m.Store.SaveTrackStats(m.statsTrack(), char, base.CorrectCount, base.MissCount, base.Streak, base.LastSeen); err != nil {
 				// don't clear session — try again next merge
 				continue
 			}
@@ -372,9 +1128,27 @@ func (m *Model) sessionCorrectCount(char string) int {
 }
 
 func (m *Model) availableCharacters() []string {
+	if m.Mode == kanacore.ModeDrill {
+		return m.DrillChars
+	}
+	if m.Mode == kanacore.ModeLookalike {
+		return m.Lookalike
+	}
 	chars := m.CharacterSet.GetCharacters()
-	if len(chars) == 0 {
-		return nil
+	if len(chars) == 0 || m.Mode == kanacore.ModeDaily {
+		// The daily challenge ignores the selection so everyone plays the same kana.
+		return chars
+	}
+	if len(m.SelectedChars) > 0 {
+		filtered := make([]string, 0, len(m.SelectedChars))
+		for _, char := range chars {
+			if m.SelectedChars[char] {
+				filtered = append(filtered, char)
+			}
+		}
+		if len(filtered) > 0 {
+			return filtered
+		}
 	}
 	if len(m.SelectedRows) == 0 {
 		return chars
@@ -398,102 +1172,300 @@ func (m *Model) availableCharacters() []string {
 	return filtered
 }
 
-// checkAutoProgression evaluates mastery and unlocks new rows if criteria are met.
-// Returns the IDs of newly unlocked rows.
+// checkAutoProgression unlocks the next lesson of the active curriculum once
+// its unlock rule is met. Returns the IDs of newly unlocked lessons. Nothing
+// is unlocked while hand-picked characters that are not whole lessons or rows
+// are active, nor while an auto-regression has narrowed the rows.
 func (m *Model) checkAutoProgression() []string {
-	if !m.AutoProgress {
+	if !m.AutoProgress || m.Regression != nil {
 		return nil
 	}
-
-	// Find the next row that isn't unlocked yet
-	var nextRow *kanacore.KanaRow
-	for _, row := range kanacore.AllKanaRows {
-		if !m.SelectedRows[row.ID] {
-			nextRow = &row
-			break
-		}
+	if len(m.SelectedChars) > 0 && !m.Curriculum.Covers(m.SelectedChars) {
+		return nil
 	}
 
-	if nextRow == nil {
-		// All rows are already unlocked
+	active := m.activeChars()
+	lesson, ok := m.Curriculum.Next(active, m.MasteryPolicy, m.charRecord, m.now())
+	if !ok {
 		return nil
 	}
 
-	// Check if all currently selected rows are mastered
-	allMastered := true
-	for _, row := range kanacore.AllKanaRows {
-		if !m.SelectedRows[row.ID] {
-			continue
+	// Lessons made of whole rows extend the row selection; any other lesson
+	// switches to practising the unlocked characters.
+	if rows, whole := lesson.Rows(); whole && len(m.SelectedChars) == 0 {
+		for _, id := range rows {
+			m.SelectedRows[id] = true
+		}
+		if m.Store != nil {
+			_ = m.Store.SaveSelectedRows(m.SelectedRowIDs())
 		}
-		if !m.isRowMastered(row) {
-			allMastered = false
-			break
+	} else {
+		chars := append([]string(nil), lesson.Chars...)
+		for char := range active {
+			chars = append(chars, char)
+		}
+		m.applySelectedChars(chars)
+		if m.Store != nil {
+			_ = m.Store.SaveSelectedChars(m.SelectedCharList())
 		}
 	}
+	return []string{lesson.ID}
+}
 
-	if allMastered {
-		// Unlock the next row
-		m.SelectedRows[nextRow.ID] = true
-		if m.Store != nil {
-			_ = m.Store.SaveSelectedRows(m.SelectedRowIDs())
+// activeChars returns the characters practised under the current selection.
+func (m *Model) activeChars() map[string]bool {
+	active := make(map[string]bool)
+	if len(m.SelectedChars) > 0 {
+		for char := range m.SelectedChars {
+			active[char] = true
 		}
-		return []string{nextRow.ID}
+		return active
 	}
+	for _, row := range kanacore.AllKanaRows {
+		if len(m.SelectedRows) == 0 || m.SelectedRows[row.ID] {
+			for _, char := range row.Characters {
+				active[char] = true
+			}
+		}
+	}
+	return active
+}
 
-	return nil
+// SetCurriculum makes the curriculum with the given ID drive auto-progression
+// and persists the choice. Unknown IDs select the default curriculum.
+func (m *Model) SetCurriculum(id string) {
+	c, ok := kanacore.FindCurriculum(m.Curricula, id)
+	if !ok {
+		c = kanacore.DefaultCurriculum()
+	}
+	m.Curriculum = c
+	if m.Store != nil {
+		_ = m.Store.SaveCurriculum(c.ID)
+	}
 }
 
-// isRowMastered checks if at least 80% of characters in a row meet mastery criteria.
-// Mastery criteria: at least 3 correct answers.
-func (m *Model) isRowMastered(row kanacore.KanaRow) bool {
-	if len(row.Characters) == 0 {
-		return true
+// NextLesson returns the curriculum lesson auto-progression unlocks next.
+func (m *Model) NextLesson() (kanacore.Lesson, bool) {
+	return m.Curriculum.Upcoming(m.activeChars())
+}
+
+// checkAutoRegression narrows the rows to a struggling row plus the last
+// mastered one when the struggling row's recent accuracy collapses, and
+// restores the previous rows once it recovers.
+func (m *Model) checkAutoRegression() {
+	if !m.AutoProgress || len(m.SelectedChars) > 0 {
+		return
+	}
+	if m.Mode == kanacore.ModeDrill || m.Mode == kanacore.ModeLookalike {
+		return
+	}
+	if m.Regression != nil {
+		if m.Regression.Recovered(m.RowHistory) {
+			label := m.Regression.RowLabel()
+			m.applySelectedRows(m.Regression.Restore)
+			if m.Store != nil {
+				_ = m.Store.SaveSelectedRows(m.Regression.Restore)
+			}
+			m.setRegression(nil)
+			m.showRegressionMessage("✅ " + label + " recovered, all rows are back")
+		}
+		return
 	}
 
-	masteredCount := 0
+	r, ok := kanacore.DetectRegression(m.RowHistory, m.SelectedRowIDs(), m.isRowMastered, m.now())
+	if !ok {
+		return
+	}
+	m.applySelectedRows(r.Focus)
+	if m.Store != nil {
+		_ = m.Store.SaveSelectedRows(r.Focus)
+	}
+	m.setRegression(&r)
+	m.showRegressionMessage("⚠ Focusing on " + r.RowLabel() + ": " + r.Reason)
+}
+
+// setRegression sets and persists the active auto-regression.
+func (m *Model) setRegression(r *kanacore.Regression) {
+	m.Regression = r
+	if m.Store == nil {
+		return
+	}
+	if r == nil {
+		_ = m.Store.SaveRegression("")
+		return
+	}
+	_ = m.Store.SaveRegression(r.String())
+}
+
+// isRowMastered reports whether enough of the row's characters are green
+// under the mastery policy.
+func (m *Model) isRowMastered(row kanacore.KanaRow) bool {
+	records := make([]kanacore.CharRecord, 0, len(row.Characters))
 	for _, char := range row.Characters {
-		total := m.OverallStats[char].CorrectCount + m.sessionCorrectCount(char)
-		// Consider a character mastered if it has at least 3 correct answers
-		if total >= 3 {
-			masteredCount++
-		}
+		records = append(records, m.charRecord(char))
 	}
+	return m.MasteryPolicy.RowMastered(records, m.now())
+}
 
-	// At least 80% of characters must be mastered
-	threshold := int(float64(len(row.Characters)) * 0.8)
-	if threshold == 0 {
-		threshold = 1
+// charRecord combines a character's overall and session statistics.
+func (m *Model) charRecord(char string) kanacore.CharRecord {
+	overall, session := m.OverallStats[char], m.SessionStats[char]
+	record := kanacore.CharRecord{
+		Correct:  overall.CorrectCount + session.CorrectCount,
+		Missed:   overall.MissCount + session.MissCount,
+		LastSeen: overall.LastSeen,
+	}
+	// Characters not played since the stats were loaded keep their saved streak.
+	if streak, ok := m.CurrentStreak[char]; ok {
+		record.Streak = streak
+	} else {
+		record.Streak = overall.Streak
 	}
+	if session.LastSeen.After(record.LastSeen) {
+		record.LastSeen = session.LastSeen
+	}
+	return record
+}
 
-	return masteredCount >= threshold
+// MasteryLevel grades a character under the mastery policy.
+func (m *Model) MasteryLevel(char string) kanacore.MasteryLevel {
+	return m.MasteryPolicy.Level(m.charRecord(char), m.now())
 }
 
-// showUnlockMessage creates a notification message for newly unlocked rows.
-func (m *Model) showUnlockMessage(rowIDs []string) {
-	if len(rowIDs) == 0 {
+// masteryLevels grades every character under the mastery policy.
+func (m *Model) masteryLevels() map[string]kanacore.MasteryLevel {
+	levels := make(map[string]kanacore.MasteryLevel)
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			levels[char] = m.MasteryLevel(char)
+		}
+	}
+	return levels
+}
+
+// SetMasteryPolicy sets and persists the rules for mastering a character.
+func (m *Model) SetMasteryPolicy(policy kanacore.MasteryPolicy) {
+	m.MasteryPolicy = policy
+	if m.Store != nil {
+		_ = m.Store.SaveMasteryPolicy(policy.String())
+	}
+}
+
+// showUnlockMessage creates a notification message for newly unlocked
+// lessons, with the lesson's intro when there is one.
+func (m *Model) showUnlockMessage(lessonIDs []string) {
+	if len(lessonIDs) == 0 {
 		return
 	}
 
-	// Find the row labels
-	labels := make([]string, 0, len(rowIDs))
-	for _, id := range rowIDs {
-		for _, row := range kanacore.AllKanaRows {
-			if row.ID == id {
-				labels = append(labels, row.Label)
-				break
-			}
+	titles := make([]string, 0, len(lessonIDs))
+	intro := ""
+	for _, id := range lessonIDs {
+		if lesson, ok := m.Curriculum.Lesson(id); ok {
+			titles = append(titles, lesson.Title)
+			intro = lesson.Intro
 		}
 	}
 
-	if len(labels) == 1 {
-		m.UnlockMessage = "🎉 New row unlocked: " + labels[0]
-	} else if len(labels) > 1 {
-		m.UnlockMessage = "🎉 New rows unlocked: " + labels[0]
-		for i := 1; i < len(labels); i++ {
-			m.UnlockMessage += ", " + labels[i]
+	if len(titles) == 1 {
+		m.UnlockMessage = "🎉 New lesson unlocked: " + titles[0]
+		if intro != "" {
+			m.UnlockMessage += " | " + intro
 		}
+	} else if len(titles) > 1 {
+		m.UnlockMessage = "🎉 New lessons unlocked: " + strings.Join(titles, ", ")
 	}
-	m.UnlockMessageAt = time.Now()
+	m.UnlockMessageAt = m.now()
+}
+
+// loadPracticeLog builds the practice log from the stored daily goal and totals.
+func loadPracticeLog(st *store.Store) *kanacore.PracticeLog {
+	goal := kanacore.DefaultDailyGoal
+	if stored, err := st.DailyGoal(); err == nil {
+		goal = kanacore.ParseDailyGoal(stored)
+	}
+	totals, _ := st.DailyTotals()
+	days := make([]kanacore.DayTotal, len(totals))
+	for i, d := range totals {
+		days[i] = kanacore.DayTotal{Day: d.Day, Correct: d.Correct, Played: d.Played, GoalMet: d.GoalMet}
+	}
+	return kanacore.NewPracticeLog(goal, days)
+}
+
+// recordPractice credits an answer or miss to today's practice, announcing
+// the daily goal when it is reached.
+func (m *Model) recordPractice(correct int) {
+	now := m.now()
+	wasMet := m.Practice.Today(now).GoalMet
+	day := m.Practice.Record(now, correct)
+	m.savePracticeDay(day)
+	if day.GoalMet && !wasMet {
+		current, _ := m.Practice.Streaks(now)
+		m.announce(fmt.Sprintf("🎯 Daily goal reached! Streak: %d %s", current, kanacore.DayWord(current)))
+	}
+}
+
+// observeAchievements passes a game event to the achievement tracker, saving
+// and announcing the badges it earns.
+func (m *Model) observeAchievements(kind kanacore.EventKind) {
+	now := m.now()
+	streak, _ := m.Practice.Streaks(now)
+	earned := m.Achievements.Observe(kanacore.AchievementEvent{
+		Kind:      kind,
+		At:        now,
+		Levels:    m.masteryLevels(),
+		DayStreak: streak,
+	})
+	for _, a := range earned {
+		if m.Store != nil {
+			_ = m.Store.SaveAchievement(a.ID, now)
+		}
+		m.SessionBadges = append(m.SessionBadges, a)
+		m.announce("🏅 Badge earned: " + a.Title)
+	}
+}
+
+// announce shows a message in the status line, after any message that is
+// still showing.
+func (m *Model) announce(message string) {
+	now := m.now()
+	if m.UnlockMessage != "" && now.Sub(m.UnlockMessageAt) < unlockMessageDuration {
+		message = m.UnlockMessage + " | " + message
+	}
+	m.UnlockMessage = message
+	m.UnlockMessageAt = now
+}
+
+func (m *Model) savePracticeDay(d kanacore.DayTotal) {
+	if m.Store == nil {
+		return
+	}
+	_ = m.Store.SaveDailyTotal(store.DailyTotal{Day: d.Day, Correct: d.Correct, Played: d.Played, GoalMet: d.GoalMet})
+}
+
+// SetDailyGoal updates and persists the daily practice goal.
+func (m *Model) SetDailyGoal(goal kanacore.Goal) {
+	m.savePracticeDay(m.Practice.SetGoal(goal, m.now()))
+	if m.Store != nil {
+		_ = m.Store.SaveDailyGoal(goal.String())
+	}
+}
+
+// showHint shows the memory hint for a missed kana or word in the status line.
+func (m *Model) showHint(text string) {
+	char, hint := kanacore.KanaMnemonic(text, m.Mnemonics)
+	if hint == "" {
+		return
+	}
+	romaji, _ := m.CharacterSet.GetRomaji(char)
+	m.Hint = fmt.Sprintf("%s (%s): %s", char, romaji, hint)
+	m.HintAt = m.now()
+}
+
+// showRegressionMessage announces an auto-regression change the way unlocks are announced.
+func (m *Model) showRegressionMessage(message string) {
+	m.UnlockMessage = message
+	m.UnlockMessageAt = m.now()
 }
 
 // getActiveRowLabels returns formatted labels for currently selected rows.
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"kana/kanacore"
+)
+
+func TestInitialModelCanSpawnRightAway(t *testing.T) {
+	m := InitialModel(nil, nil)
+	if m.Rand == nil || m.Recorder == nil {
+		t.Fatal("expected InitialModel to seed the session")
+	}
+	m.spawnKana()
+	if len(m.Kanas) != 1 {
+		t.Errorf("expected a tile to spawn, got %d", len(m.Kanas))
+	}
+}
+
+func TestMissRevealsFitTheField(t *testing.T) {
+	m := InitialModel(nil, nil)
+	m.GameWidth = 12
+	for _, k := range []kanacore.Kana{{Char: "たべもの", Romaji: "tabemono"}, {Char: "あ", Romaji: "a"}} {
+		m.MissReveals = append(m.MissReveals, MissReveal{Kana: k, At: time.Now()})
+	}
+	if got := lipgloss.Width(renderMissReveals(m)); got != m.GameWidth {
+		t.Errorf("expected the reveals to fill exactly %d columns, got %d", m.GameWidth, got)
+	}
+}
+
+func TestProductionBackspaceRemovesAKana(t *testing.T) {
+	m := InitialModel(nil, nil)
+	m.SetGameMode(kanacore.ModeReverse, 0)
+	for _, r := range "kani" {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(Model)
+	}
+	if m.Input != "かに" {
+		t.Fatalf("expected the input to be kept as kana, got %q", m.Input)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = next.(Model)
+	if m.Input != "か" {
+		t.Errorf("expected backspace to remove に, got %q", m.Input)
+	}
+}
+
+func TestFieldMakesRoomForTheGhost(t *testing.T) {
+	m := InitialModel(nil, nil)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 30})
+	m = next.(Model)
+	if m.Height != 27 {
+		t.Fatalf("expected a 27-line field, got %d", m.Height)
+	}
+
+	m.Ghost = kanacore.NewGhost(&kanacore.Replay{})
+	m.fitField()
+	if m.Height != 26 {
+		t.Errorf("expected the ghost's line to come out of the field, got %d", m.Height)
+	}
+	m.Ghost = nil
+	m.reseed()
+	if m.Height != 27 {
+		t.Errorf("expected the line back once no ghost is raced, got %d", m.Height)
+	}
+}
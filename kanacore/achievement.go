@@ -0,0 +1,155 @@
+package kanacore
+
+import "time"
+
+// Achievement is a badge awarded once for a milestone.
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// Achievement IDs. They are persisted, so they must not change.
+const (
+	AchievementFirstSession = "first-session"
+	AchievementFirstUnlock  = "first-unlock"
+	AchievementFirstRow     = "first-row"
+	AchievementNoMiss100    = "no-miss-100"
+	AchievementAllGreen     = "all-green"
+	AchievementStreak7      = "streak-7"
+	AchievementStreak30     = "streak-30"
+)
+
+// noMissRun is how many correct answers in a row, with no miss or wrong
+// answer between them, earn AchievementNoMiss100.
+const noMissRun = 100
+
+// Achievements lists every achievement in gallery order.
+var Achievements = []Achievement{
+	{AchievementFirstSession, "First steps", "Finish your first session"},
+	{AchievementFirstUnlock, "Moving on", "Unlock a new lesson"},
+	{AchievementFirstRow, "Row master", "Get every kana of a row to green"},
+	{AchievementNoMiss100, "Untouchable", "Answer 100 kana in a row in one session without a miss or a wrong answer"},
+	{AchievementAllGreen, "Hiragana complete", "Get all 46 hiragana to green"},
+	{AchievementStreak7, "Week of practice", "Meet your daily goal 7 days in a row"},
+	{AchievementStreak30, "Habit formed", "Meet your daily goal 30 days in a row"},
+}
+
+// FindAchievement looks up an achievement by ID.
+func FindAchievement(id string) (Achievement, bool) {
+	for _, a := range Achievements {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Achievement{}, false
+}
+
+// EventKind is what happened in a game that achievements listen for.
+type EventKind string
+
+const (
+	// EventCorrect is a kana answered correctly.
+	EventCorrect EventKind = "correct"
+	// EventMiss is a kana that fell past the bottom.
+	EventMiss EventKind = "miss"
+	// EventWrong is an answer that matched no kana.
+	EventWrong EventKind = "wrong"
+	// EventUnlock is auto-progression unlocking a lesson.
+	EventUnlock EventKind = "unlock"
+	// EventSessionEnd is a session finishing for any reason.
+	EventSessionEnd EventKind = "session-end"
+)
+
+// AchievementEvent is one game event passed to AchievementTracker.Observe,
+// with the state achievements are judged on.
+type AchievementEvent struct {
+	Kind      EventKind
+	At        time.Time
+	Levels    map[string]MasteryLevel // mastery of every kana after the event
+	DayStreak int                     // current daily goal streak
+}
+
+// AchievementTracker awards achievements as game events arrive.
+type AchievementTracker struct {
+	earned map[string]time.Time
+	run    int // correct answers since the last miss or wrong answer this session
+}
+
+// NewAchievementTracker starts a tracker that knows which achievements were
+// already earned and when.
+func NewAchievementTracker(earned map[string]time.Time) *AchievementTracker {
+	t := &AchievementTracker{earned: make(map[string]time.Time, len(earned))}
+	for id, at := range earned {
+		t.earned[id] = at
+	}
+	return t
+}
+
+// Earned returns when an achievement was earned, reporting false if it has
+// not been.
+func (t *AchievementTracker) Earned(id string) (time.Time, bool) {
+	at, ok := t.earned[id]
+	return at, ok
+}
+
+// Observe updates the tracker with an event and returns the achievements it
+// newly earns, in gallery order.
+func (t *AchievementTracker) Observe(e AchievementEvent) []Achievement {
+	met := make(map[string]bool)
+	switch e.Kind {
+	case EventCorrect:
+		t.run++
+		met[AchievementNoMiss100] = t.run >= noMissRun
+	case EventMiss, EventWrong:
+		t.run = 0
+	case EventUnlock:
+		met[AchievementFirstUnlock] = true
+	case EventSessionEnd:
+		met[AchievementFirstSession] = true
+		t.run = 0
+	}
+	if e.Levels != nil {
+		met[AchievementFirstRow] = anyRowGreen(e.Levels)
+		met[AchievementAllGreen] = allGreen(e.Levels)
+	}
+	met[AchievementStreak7] = e.DayStreak >= 7
+	met[AchievementStreak30] = e.DayStreak >= 30
+
+	var earned []Achievement
+	for _, a := range Achievements {
+		if _, ok := t.earned[a.ID]; ok || !met[a.ID] {
+			continue
+		}
+		t.earned[a.ID] = e.At
+		earned = append(earned, a)
+	}
+	return earned
+}
+
+func anyRowGreen(levels map[string]MasteryLevel) bool {
+	for _, row := range AllKanaRows {
+		green := true
+		for _, char := range row.Characters {
+			if levels[char] != LevelGreen {
+				green = false
+				break
+			}
+		}
+		if green {
+			return true
+		}
+	}
+	return false
+}
+
+func allGreen(levels map[string]MasteryLevel) bool {
+	for _, row := range AllKanaRows {
+		for _, char := range row.Characters {
+			if levels[char] != LevelGreen {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func greenLevels(chars ...string) map[string]MasteryLevel {
+	levels := make(map[string]MasteryLevel)
+	for _, row := range AllKanaRows {
+		for _, char := range row.Characters {
+			levels[char] = LevelYellow
+		}
+	}
+	for _, char := range chars {
+		levels[char] = LevelGreen
+	}
+	return levels
+}
+
+func ids(list []Achievement) []string {
+	out := make([]string, len(list))
+	for i, a := range list {
+		out[i] = a.ID
+	}
+	return out
+}
+
+func TestAchievementsAreAwardedOnce(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tr := NewAchievementTracker(nil)
+
+	got := tr.Observe(AchievementEvent{Kind: EventSessionEnd, At: at})
+	if len(got) != 1 || got[0].ID != AchievementFirstSession {
+		t.Fatalf("expected the first session badge, got %v", ids(got))
+	}
+	if when, ok := tr.Earned(AchievementFirstSession); !ok || !when.Equal(at) {
+		t.Errorf("expected the badge to be earned at %v, got %v %v", at, when, ok)
+	}
+	if got := tr.Observe(AchievementEvent{Kind: EventSessionEnd, At: at.Add(time.Hour)}); len(got) != 0 {
+		t.Errorf("expected no repeat award, got %v", ids(got))
+	}
+}
+
+func TestNoMissRunResetsOnMiss(t *testing.T) {
+	tr := NewAchievementTracker(nil)
+	for i := 0; i < noMissRun-1; i++ {
+		tr.Observe(AchievementEvent{Kind: EventCorrect})
+	}
+	tr.Observe(AchievementEvent{Kind: EventMiss})
+	tr.Observe(AchievementEvent{Kind: EventCorrect})
+	if _, ok := tr.Earned(AchievementNoMiss100); ok {
+		t.Fatal("a miss should reset the run")
+	}
+	for i := 0; i < noMissRun-1; i++ {
+		tr.Observe(AchievementEvent{Kind: EventCorrect})
+	}
+	if _, ok := tr.Earned(AchievementNoMiss100); !ok {
+		t.Error("expected 100 correct answers without a miss to earn the badge")
+	}
+}
+
+func TestNoMissRunResetsOnWrongAnswer(t *testing.T) {
+	tr := NewAchievementTracker(nil)
+	for i := 0; i < noMissRun/2; i++ {
+		tr.Observe(AchievementEvent{Kind: EventCorrect})
+	}
+	tr.Observe(AchievementEvent{Kind: EventWrong})
+	for i := 0; i < noMissRun/2; i++ {
+		tr.Observe(AchievementEvent{Kind: EventCorrect})
+	}
+	if _, ok := tr.Earned(AchievementNoMiss100); ok {
+		t.Fatal("a wrong answer should reset the run")
+	}
+	for i := 0; i < noMissRun/2; i++ {
+		tr.Observe(AchievementEvent{Kind: EventCorrect})
+	}
+	if _, ok := tr.Earned(AchievementNoMiss100); !ok {
+		t.Error("expected 100 correct answers after the wrong one to earn the badge")
+	}
+}
+
+func TestMasteryAndStreakAchievements(t *testing.T) {
+	tr := NewAchievementTracker(map[string]time.Time{AchievementFirstSession: time.Now()})
+
+	got := tr.Observe(AchievementEvent{Kind: EventCorrect, Levels: greenLevels("あ", "い", "う", "え")})
+	if len(got) != 0 {
+		t.Fatalf("an incomplete row should not earn anything, got %v", ids(got))
+	}
+	got = tr.Observe(AchievementEvent{Kind: EventCorrect, Levels: greenLevels("あ", "い", "う", "え", "お"), DayStreak: 7})
+	if want := []string{AchievementFirstRow, AchievementStreak7}; len(got) != 2 || got[0].ID != want[0] || got[1].ID != want[1] {
+		t.Fatalf("expected %v, got %v", want, ids(got))
+	}
+
+	var all []string
+	for _, row := range AllKanaRows {
+		all = append(all, row.Characters...)
+	}
+	got = tr.Observe(AchievementEvent{Kind: EventUnlock, Levels: greenLevels(all...)})
+	if want := []string{AchievementFirstUnlock, AchievementAllGreen}; len(got) != 2 || got[0].ID != want[0] || got[1].ID != want[1] {
+		t.Errorf("expected %v, got %v", want, ids(got))
+	}
+}
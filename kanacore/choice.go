@@ -0,0 +1,106 @@
+package kanacore
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// ChoiceCount is the number of answers offered per tile in multiple-choice mode.
+const ChoiceCount = 4
+
+// commonConfusions lists romaji learners often give for each other's kana,
+// used as distractors before the player has any confusion history.
+var commonConfusions = map[string][]string{
+	"a":   {"o", "nu", "me"},
+	"o":   {"a", "no", "wo"},
+	"i":   {"ri", "ko"},
+	"ri":  {"i", "ko"},
+	"ko":  {"ni", "i"},
+	"ni":  {"ko", "ta"},
+	"ta":  {"na", "ni"},
+	"na":  {"ta", "me"},
+	"u":   {"ra", "tsu"},
+	"ra":  {"u", "chi"},
+	"sa":  {"chi", "ki"},
+	"chi": {"sa", "ra"},
+	"ki":  {"sa", "ma"},
+	"ma":  {"ki", "ha", "ho"},
+	"ha":  {"ho", "ma", "ke"},
+	"ho":  {"ha", "ma"},
+	"ke":  {"ha", "ni"},
+	"shi": {"tsu", "mo"},
+	"tsu": {"shi", "so"},
+	"so":  {"tsu", "n"},
+	"n":   {"so", "me"},
+	"nu":  {"me", "a"},
+	"me":  {"nu", "a", "no"},
+	"no":  {"me", "o"},
+	"ne":  {"re", "wa"},
+	"re":  {"ne", "wa"},
+	"wa":  {"ne", "re"},
+	"ru":  {"ro", "ra"},
+	"ro":  {"ru", "ra"},
+	"wo":  {"o", "chi"},
+	"mo":  {"shi", "ni"},
+	"su":  {"o", "mu"},
+	"mu":  {"su", "o"},
+	"e":   {"ra", "n"},
+	"te":  {"to", "ri"},
+	"to":  {"te", "ko"},
+	"ku":  {"he", "shi"},
+	"he":  {"ku", "ha"},
+	"hi":  {"chi", "shi"},
+	"fu":  {"ru", "wa"},
+	"yo":  {"ma", "ha"},
+	"ya":  {"ka", "se"},
+	"ka":  {"ya", "ga"},
+	"se":  {"sa", "ya"},
+	"yu":  {"ro", "ru"},
+	"mi":  {"ko", "ni"},
+}
+
+// Choices returns n romaji answers for a tile whose answer is answer, in
+// random order. Distractors come first from the player's confusions (romaji
+// given for this tile's kana, most frequent first), then from commonly
+// confused kana, then at random from pool, all shuffled with rng. Fewer than
+// n choices are returned when there are not enough distinct candidates.
+func Choices(answer string, pool []string, confusions map[string]int, n int, rng *rand.Rand) []string {
+	if n <= 0 {
+		return nil
+	}
+	seen := map[string]bool{answer: true}
+	choices := []string{answer}
+	add := func(romaji string) {
+		if len(choices) < n && romaji != "" && !seen[romaji] {
+			seen[romaji] = true
+			choices = append(choices, romaji)
+		}
+	}
+
+	confused := make([]string, 0, len(confusions))
+	for romaji, count := range confusions {
+		if count > 0 {
+			confused = append(confused, romaji)
+		}
+	}
+	sort.Slice(confused, func(i, j int) bool {
+		if confusions[confused[i]] != confusions[confused[j]] {
+			return confusions[confused[i]] > confusions[confused[j]]
+		}
+		return confused[i] < confused[j]
+	})
+	for _, romaji := range confused {
+		add(romaji)
+	}
+	for _, romaji := range commonConfusions[answer] {
+		add(romaji)
+	}
+	for _, i := range rng.Perm(len(pool)) {
+		add(pool[i])
+	}
+
+	rng.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	return choices
+}
@@ -0,0 +1,66 @@
+package kanacore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChoicesIncludeAnswerOnce(t *testing.T) {
+	pool := []string{"a", "i", "u", "e", "o", "ka"}
+	for i := 0; i < 20; i++ {
+		choices := Choices("ka", pool, nil, ChoiceCount, NewRand(1))
+		if len(choices) != ChoiceCount {
+			t.Fatalf("expected %d choices, got %v", ChoiceCount, choices)
+		}
+		seen := map[string]bool{}
+		for _, c := range choices {
+			if seen[c] {
+				t.Fatalf("duplicate choice in %v", choices)
+			}
+			seen[c] = true
+		}
+		if !seen["ka"] {
+			t.Fatalf("answer missing from %v", choices)
+		}
+	}
+}
+
+func TestChoicesPreferConfusionHistory(t *testing.T) {
+	confusions := map[string]int{"me": 5, "a": 2, "no": 1, "ro": 0}
+	choices := Choices("nu", []string{"ka", "ki", "ku"}, confusions, ChoiceCount, NewRand(1))
+	want := map[string]bool{"nu": true, "me": true, "a": true, "no": true}
+	for _, c := range choices {
+		if !want[c] {
+			t.Errorf("unexpected choice %q in %v", c, choices)
+		}
+	}
+}
+
+func TestChoicesFallBackToCommonConfusions(t *testing.T) {
+	choices := Choices("shi", nil, nil, 3, NewRand(1))
+	want := map[string]bool{"shi": true, "tsu": true, "mo": true}
+	if len(choices) != 3 {
+		t.Fatalf("expected 3 choices, got %v", choices)
+	}
+	for _, c := range choices {
+		if !want[c] {
+			t.Errorf("unexpected choice %q in %v", c, choices)
+		}
+	}
+}
+
+func TestChoicesShortPool(t *testing.T) {
+	choices := Choices("xyz", []string{"a"}, nil, ChoiceCount, NewRand(1))
+	if len(choices) != 2 {
+		t.Errorf("expected answer plus the only pool entry, got %v", choices)
+	}
+}
+
+func TestChoicesFollowTheSeed(t *testing.T) {
+	pool := []string{"a", "i", "u", "e", "o", "ka", "ki", "ku"}
+	first := Choices("ke", pool, nil, ChoiceCount, NewRand(7))
+	second := Choices("ke", pool, nil, ChoiceCount, NewRand(7))
+	if strings.Join(first, " ") != strings.Join(second, " ") {
+		t.Errorf("expected the same seed to give the same choices, got %v and %v", first, second)
+	}
+}
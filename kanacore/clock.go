@@ -0,0 +1,36 @@
+package kanacore
+
+import "time"
+
+// Clock tells the game engines the time. Engines take one so that tests and
+// recordings can drive a session deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// ManualClock is a Clock that only moves when told to.
+type ManualClock struct {
+	now time.Time
+}
+
+// NewManualClock returns a clock stopped at start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *ManualClock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,343 @@
+package kanacore
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCurriculumID is the curriculum that unlocks rows in gojūon order.
+const DefaultCurriculumID = "gojuon"
+
+// Lesson is one step of a curriculum: a set of characters unlocked together.
+type Lesson struct {
+	ID     string
+	Title  string
+	Chars  []string
+	Intro  string // shown when the lesson unlocks; may be empty
+	Unlock UnlockRule
+}
+
+// UnlockRule says when a lesson unlocks.
+type UnlockRule struct {
+	// Mastered is the share of each unlocked lesson's characters that must
+	// be green under the mastery policy.
+	Mastered float64
+}
+
+// DefaultUnlockRule matches a row counting as mastered.
+var DefaultUnlockRule = UnlockRule{Mastered: rowMasteryShare}
+
+// Curriculum orders the kana into lessons that auto-progression unlocks one
+// at a time.
+type Curriculum struct {
+	ID      string
+	Name    string
+	Lessons []Lesson
+}
+
+//go:embed data/curricula/*.txt
+var curriculumFiles embed.FS
+
+var (
+	defaultCurriculaOnce sync.Once
+	defaultCurricula     []Curriculum
+)
+
+// DefaultCurricula returns the bundled curricula, the default one first.
+func DefaultCurricula() []Curriculum {
+	defaultCurriculaOnce.Do(func() {
+		entries, _ := curriculumFiles.ReadDir("data/curricula")
+		for _, entry := range entries {
+			data, err := curriculumFiles.ReadFile(path.Join("data/curricula", entry.Name()))
+			if err != nil {
+				continue
+			}
+			c, err := ParseCurriculum(strings.TrimSuffix(entry.Name(), ".txt"), string(data))
+			if err != nil {
+				continue
+			}
+			defaultCurricula = append(defaultCurricula, c)
+		}
+		sort.SliceStable(defaultCurricula, func(i, j int) bool {
+			return defaultCurricula[i].ID == DefaultCurriculumID
+		})
+	})
+	return append([]Curriculum(nil), defaultCurricula...)
+}
+
+// DefaultCurriculum returns the gojūon row curriculum.
+func DefaultCurriculum() Curriculum {
+	c, _ := FindCurriculum(DefaultCurricula(), DefaultCurriculumID)
+	return c
+}
+
+// LoadCurricula returns the bundled curricula followed by the user's files in
+// dir. A user file replaces a bundled curriculum with the same ID. A missing
+// dir is not an error; files that fail to parse are skipped and reported in
+// the returned error.
+func LoadCurricula(dir string) ([]Curriculum, error) {
+	list := DefaultCurricula()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return list, err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c, err := ParseCurriculum(strings.TrimSuffix(filepath.Base(p), ".txt"), string(data))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+			continue
+		}
+		replaced := false
+		for i := range list {
+			if list[i].ID == c.ID {
+				list[i], replaced = c, true
+			}
+		}
+		if !replaced {
+			list = append(list, c)
+		}
+	}
+	return list, errors.Join(errs...)
+}
+
+// FindCurriculum looks up a curriculum by ID.
+func FindCurriculum(list []Curriculum, id string) (Curriculum, bool) {
+	for _, c := range list {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Curriculum{}, false
+}
+
+// ParseCurriculum reads a curriculum file; see data/curricula/gojuon.txt for
+// the format. Every character must be a basic hiragana and may appear in only
+// one lesson.
+func ParseCurriculum(id, data string) (Curriculum, error) {
+	c := Curriculum{ID: id, Name: id}
+	seen := make(map[string]string)
+	var lesson *Lesson
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Curriculum{}, fmt.Errorf("line %d: expected key: value", n+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key != "name" && key != "lesson" && lesson == nil {
+			return Curriculum{}, fmt.Errorf("line %d: %s before the first lesson", n+1, key)
+		}
+		switch key {
+		case "name":
+			c.Name = value
+		case "lesson":
+			lessonID, title, _ := strings.Cut(value, " ")
+			if lessonID == "" {
+				return Curriculum{}, fmt.Errorf("line %d: lesson needs an id", n+1)
+			}
+			if _, ok := c.Lesson(lessonID); ok {
+				return Curriculum{}, fmt.Errorf("line %d: duplicate lesson %q", n+1, lessonID)
+			}
+			title = strings.TrimSpace(title)
+			if title == "" {
+				title = lessonID
+			}
+			c.Lessons = append(c.Lessons, Lesson{ID: lessonID, Title: title, Unlock: DefaultUnlockRule})
+			lesson = &c.Lessons[len(c.Lessons)-1]
+		case "chars":
+			for _, char := range strings.Fields(value) {
+				if _, ok := CharToRow[char]; !ok {
+					return Curriculum{}, fmt.Errorf("line %d: %q is not a basic hiragana", n+1, char)
+				}
+				if other, ok := seen[char]; ok {
+					return Curriculum{}, fmt.Errorf("line %d: %s is already in lesson %q", n+1, char, other)
+				}
+				seen[char] = lesson.ID
+				lesson.Chars = append(lesson.Chars, char)
+			}
+		case "unlock":
+			rule, err := parseUnlockRule(value)
+			if err != nil {
+				return Curriculum{}, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			lesson.Unlock = rule
+		case "intro":
+			lesson.Intro = value
+		default:
+			return Curriculum{}, fmt.Errorf("line %d: unknown key %q", n+1, key)
+		}
+	}
+	if len(c.Lessons) == 0 {
+		return Curriculum{}, errors.New("no lessons")
+	}
+	for _, l := range c.Lessons {
+		if len(l.Chars) == 0 {
+			return Curriculum{}, fmt.Errorf("lesson %q has no characters", l.ID)
+		}
+	}
+	return c, nil
+}
+
+func parseUnlockRule(value string) (UnlockRule, error) {
+	rule := DefaultUnlockRule
+	for _, field := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return UnlockRule{}, fmt.Errorf("unlock: expected key=value, got %q", field)
+		}
+		switch strings.TrimSpace(key) {
+		case "mastered":
+			pct, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil || pct < 0 || pct > 100 {
+				return UnlockRule{}, fmt.Errorf("unlock: mastered must be 0-100, got %q", raw)
+			}
+			rule.Mastered = float64(pct) / 100
+		default:
+			return UnlockRule{}, fmt.Errorf("unlock: unknown criterion %q", key)
+		}
+	}
+	return rule, nil
+}
+
+// Lesson looks up a lesson by ID.
+func (c Curriculum) Lesson(id string) (Lesson, bool) {
+	for _, l := range c.Lessons {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return Lesson{}, false
+}
+
+// Rows returns the rows a lesson covers when its characters are exactly whole
+// rows, so it can be unlocked as a row selection.
+func (l Lesson) Rows() ([]string, bool) {
+	chars := make(map[string]bool, len(l.Chars))
+	for _, char := range l.Chars {
+		chars[char] = true
+	}
+	var rows []string
+	for _, row := range AllKanaRows {
+		covered := 0
+		for _, char := range row.Characters {
+			if chars[char] {
+				covered++
+			}
+		}
+		switch covered {
+		case 0:
+		case len(row.Characters):
+			rows = append(rows, row.ID)
+		default:
+			return nil, false
+		}
+	}
+	return rows, true
+}
+
+// unlocked reports whether every character of the lesson is active.
+func (l Lesson) unlocked(active map[string]bool) bool {
+	for _, char := range l.Chars {
+		if !active[char] {
+			return false
+		}
+	}
+	return true
+}
+
+// Covers reports whether the characters are exactly a union of whole lessons
+// and whole rows, as a selection made by unlocking lessons on top of a row
+// selection is.
+func (c Curriculum) Covers(chars map[string]bool) bool {
+	covered := make(map[string]bool, len(chars))
+	for _, l := range c.Lessons {
+		if l.unlocked(chars) {
+			for _, char := range l.Chars {
+				covered[char] = true
+			}
+		}
+	}
+	for _, row := range AllKanaRows {
+		whole := true
+		for _, char := range row.Characters {
+			whole = whole && chars[char]
+		}
+		if whole {
+			for _, char := range row.Characters {
+				covered[char] = true
+			}
+		}
+	}
+	return len(covered) == len(chars)
+}
+
+// Upcoming returns the first lesson that is not yet fully active, reporting
+// false when every lesson is.
+func (c Curriculum) Upcoming(active map[string]bool) (Lesson, bool) {
+	for _, l := range c.Lessons {
+		if !l.unlocked(active) {
+			return l, true
+		}
+	}
+	return Lesson{}, false
+}
+
+// Next returns the Upcoming lesson once its unlock rule is met: every fully
+// active lesson, and any active characters outside them, must have enough
+// green characters. It reports false when every lesson is active or the rule
+// is not met yet.
+func (c Curriculum) Next(active map[string]bool, policy MasteryPolicy, record func(char string) CharRecord, now time.Time) (Lesson, bool) {
+	next, ok := c.Upcoming(active)
+	if !ok {
+		return Lesson{}, false
+	}
+	rule := next.Unlock
+
+	inLesson := make(map[string]bool, len(active))
+	for _, l := range c.Lessons {
+		if !l.unlocked(active) {
+			continue
+		}
+		records := make([]CharRecord, 0, len(l.Chars))
+		for _, char := range l.Chars {
+			inLesson[char] = true
+			records = append(records, record(char))
+		}
+		if !policy.SetMastered(records, now, rule.Mastered) {
+			return Lesson{}, false
+		}
+	}
+
+	var loose []CharRecord
+	for _, row := range AllKanaRows {
+		for _, char := range row.Characters {
+			if active[char] && !inLesson[char] {
+				loose = append(loose, record(char))
+			}
+		}
+	}
+	if !policy.SetMastered(loose, now, rule.Mastered) {
+		return Lesson{}, false
+	}
+	return next, true
+}
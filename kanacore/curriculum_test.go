@@ -0,0 +1,153 @@
+package kanacore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultCurriculaCoverEveryKanaOnce(t *testing.T) {
+	list := DefaultCurricula()
+	if len(list) < 2 || list[0].ID != DefaultCurriculumID {
+		t.Fatalf("expected the gojūon curriculum first among the bundled ones, got %d", len(list))
+	}
+	for _, c := range list {
+		count := make(map[string]int)
+		for _, l := range c.Lessons {
+			for _, char := range l.Chars {
+				count[char]++
+			}
+		}
+		if len(count) != len(CharToRow) {
+			t.Errorf("%s covers %d kana, want %d", c.ID, len(count), len(CharToRow))
+		}
+	}
+}
+
+func TestGojuonCurriculumFollowsRows(t *testing.T) {
+	c := DefaultCurriculum()
+	if len(c.Lessons) != len(AllKanaRows) {
+		t.Fatalf("expected one lesson per row, got %d", len(c.Lessons))
+	}
+	for i, row := range AllKanaRows {
+		l := c.Lessons[i]
+		rows, whole := l.Rows()
+		if l.ID != row.ID || l.Title != row.Label || !whole || len(rows) != 1 || rows[0] != row.ID {
+			t.Errorf("lesson %d = %s %q %v, want row %s", i, l.ID, l.Title, rows, row.ID)
+		}
+	}
+}
+
+func TestParseCurriculum(t *testing.T) {
+	c, err := ParseCurriculum("mine", `
+# comment
+name: Mine
+lesson: first First steps
+chars: あ い
+intro: Hello: world
+lesson: second
+chars: か
+unlock: mastered=50
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Mine" || len(c.Lessons) != 2 {
+		t.Fatalf("got %+v", c)
+	}
+	if c.Lessons[0].Intro != "Hello: world" || c.Lessons[0].Unlock != DefaultUnlockRule {
+		t.Errorf("first lesson = %+v", c.Lessons[0])
+	}
+	if c.Lessons[1].Title != "second" || c.Lessons[1].Unlock.Mastered != 0.5 {
+		t.Errorf("second lesson = %+v", c.Lessons[1])
+	}
+	if _, whole := c.Lessons[0].Rows(); whole {
+		t.Error("expected a partial row not to count as whole rows")
+	}
+
+	for name, data := range map[string]string{
+		"empty":          "name: x",
+		"unknown kana":   "lesson: a\nchars: カ",
+		"duplicate kana": "lesson: a\nchars: あ\nlesson: b\nchars: あ",
+		"no chars":       "lesson: a",
+		"bad unlock":     "lesson: a\nchars: あ\nunlock: mastered=120",
+		"before lesson":  "chars: あ",
+		"unknown key":    "lesson: a\nchars: あ\ncolour: red",
+	} {
+		if _, err := ParseCurriculum("x", data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadCurriculaAddsAndReplaces(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("gojuon.txt", "name: Mine\nlesson: all\nchars: あ")
+	write("extra.txt", "lesson: a\nchars: い")
+	write("broken.txt", "lesson: a")
+
+	list, err := LoadCurricula(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.txt") {
+		t.Errorf("expected the broken file to be reported, got %v", err)
+	}
+	if c, _ := FindCurriculum(list, DefaultCurriculumID); c.Name != "Mine" {
+		t.Errorf("expected the user file to replace the bundled one, got %q", c.Name)
+	}
+	if _, ok := FindCurriculum(list, "extra"); !ok {
+		t.Error("expected the extra curriculum to load")
+	}
+	if _, err := LoadCurricula(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("expected a missing directory to be fine, got %v", err)
+	}
+}
+
+func TestCurriculumNextFollowsLessonsAndRules(t *testing.T) {
+	c, err := ParseCurriculum("x", "lesson: one\nchars: の に\nlesson: two\nchars: あ い\nunlock: mastered=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := DefaultMasteryPolicy()
+	now := time.Now()
+	green := map[string]bool{}
+	record := func(char string) CharRecord {
+		if green[char] {
+			return CharRecord{Correct: 5, Streak: 5, LastSeen: now}
+		}
+		return CharRecord{Correct: 1, Missed: 1}
+	}
+
+	active := map[string]bool{"の": true, "に": true}
+	if _, ok := c.Next(active, policy, record, now); ok {
+		t.Error("expected nothing to unlock before any kana is green")
+	}
+	green["の"] = true
+	l, ok := c.Next(active, policy, record, now)
+	if !ok || l.ID != "two" {
+		t.Errorf("expected lesson two at 50%% green, got %v %v", l.ID, ok)
+	}
+
+	active["あ"], active["い"] = true, true
+	if _, ok := c.Next(active, policy, record, now); ok {
+		t.Error("expected nothing left to unlock")
+	}
+	if !c.Covers(active) {
+		t.Error("expected two whole lessons to be covered")
+	}
+	delete(active, "い")
+	if c.Covers(active) {
+		t.Error("expected a partial lesson not to be covered")
+	}
+	for _, char := range AllKanaRows[0].Characters {
+		active[char] = true
+	}
+	if !c.Covers(active) {
+		t.Error("expected a lesson and a whole row to be covered")
+	}
+}
@@ -0,0 +1,254 @@
+package kanacore
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DailyGoals lists the selectable daily practice goals in display order.
+var DailyGoals = []Goal{
+	{Kind: GoalCount, Count: 20},
+	{Kind: GoalCount, Count: 50},
+	{Kind: GoalCount, Count: 100},
+	{Kind: GoalTime, Duration: 5 * time.Minute},
+	{Kind: GoalTime, Duration: 10 * time.Minute},
+	{Kind: GoalTime, Duration: 20 * time.Minute},
+	{Kind: GoalNone},
+}
+
+// DefaultDailyGoal is used when no daily goal has been configured.
+var DefaultDailyGoal = Goal{Kind: GoalCount, Count: 50}
+
+// ParseDailyGoal decodes a stored daily goal, falling back to DefaultDailyGoal.
+func ParseDailyGoal(value string) Goal {
+	return parseGoal(value, DefaultDailyGoal)
+}
+
+// DailyLabel describes the goal as a daily target.
+func (g Goal) DailyLabel() string {
+	if g.Kind == GoalNone {
+		return "No daily goal (any practice keeps the streak)"
+	}
+	return g.Label() + " a day"
+}
+
+// dayLayout formats day keys.
+const dayLayout = "2006-01-02"
+
+// DayKey names the local calendar day of t, for example "2024-05-01".
+func DayKey(t time.Time) string {
+	return t.Format(dayLayout)
+}
+
+// maxActivityGap caps the play time credited between two answers, so a game
+// left running unattended does not count as practice.
+const maxActivityGap = time.Minute
+
+// DayTotal is one day's practice.
+type DayTotal struct {
+	Day     string // DayKey of the day
+	Correct int
+	Played  time.Duration
+	GoalMet bool // whether the daily goal in force at the time was met
+}
+
+// practised reports whether anything was practised on the day.
+func (d DayTotal) practised() bool {
+	return d.Correct > 0 || d.Played > 0
+}
+
+// PracticeLog keeps per-day practice totals and credits play towards the
+// daily goal. A day's GoalMet is settled when it is reached, so changing the
+// goal later does not rewrite past streaks.
+type PracticeLog struct {
+	Goal Goal
+	days map[string]DayTotal
+	last time.Time // last credited activity; zero while not playing
+}
+
+// NewPracticeLog starts a log from the stored day totals.
+func NewPracticeLog(goal Goal, days []DayTotal) *PracticeLog {
+	l := &PracticeLog{Goal: goal, days: make(map[string]DayTotal, len(days))}
+	for _, d := range days {
+		l.days[d.Day] = d
+	}
+	return l
+}
+
+// Start begins timing play at now.
+func (l *PracticeLog) Start(now time.Time) {
+	l.last = now
+}
+
+// Record credits the play time since the last activity and correct answers
+// to now's day, returning the updated day to persist.
+func (l *PracticeLog) Record(now time.Time, correct int) DayTotal {
+	d := l.Today(now)
+	if !l.last.IsZero() && now.After(l.last) {
+		d.Played += min(now.Sub(l.last), maxActivityGap)
+	}
+	l.last = now
+	d.Correct += correct
+	return l.settle(d)
+}
+
+// Stop credits the play time up to now and stops timing until the next
+// Start, returning the updated day to persist.
+func (l *PracticeLog) Stop(now time.Time) DayTotal {
+	d := l.Record(now, 0)
+	l.last = time.Time{}
+	return d
+}
+
+// SetGoal changes the daily goal, returning today's total to persist since
+// it may meet the new goal already.
+func (l *PracticeLog) SetGoal(goal Goal, now time.Time) DayTotal {
+	l.Goal = goal
+	return l.settle(l.Today(now))
+}
+
+// settle marks the day as meeting the goal once it does and stores it.
+func (l *PracticeLog) settle(d DayTotal) DayTotal {
+	if !d.GoalMet && l.met(d) {
+		d.GoalMet = true
+	}
+	l.days[d.Day] = d
+	return d
+}
+
+// met reports whether the day meets the current goal. Without a goal any
+// practice counts.
+func (l *PracticeLog) met(d DayTotal) bool {
+	if l.Goal.Kind == GoalNone {
+		return d.practised()
+	}
+	return l.Goal.Reached(d.Correct, d.Played)
+}
+
+// Today returns now's day total.
+func (l *PracticeLog) Today(now time.Time) DayTotal {
+	key := DayKey(now)
+	if d, ok := l.days[key]; ok {
+		return d
+	}
+	return DayTotal{Day: key}
+}
+
+// Day returns the total for a day key.
+func (l *PracticeLog) Day(key string) DayTotal {
+	if d, ok := l.days[key]; ok {
+		return d
+	}
+	return DayTotal{Day: key}
+}
+
+// Progress renders today's progress towards the goal.
+func (l *PracticeLog) Progress(now time.Time) string {
+	d := l.Today(now)
+	switch l.Goal.Kind {
+	case GoalCount:
+		return fmt.Sprintf("%d/%d correct today", d.Correct, l.Goal.Count)
+	case GoalTime:
+		return fmt.Sprintf("%d/%d min today", int(d.Played/time.Minute), int(l.Goal.Duration/time.Minute))
+	default:
+		return fmt.Sprintf("%d correct today", d.Correct)
+	}
+}
+
+// Streaks returns the current and longest runs of consecutive days on which
+// the goal was met. The current streak runs up to today, or up to yesterday
+// while today's goal is still open.
+func (l *PracticeLog) Streaks(now time.Time) (current, longest int) {
+	var met []time.Time
+	for key, d := range l.days {
+		if !d.GoalMet {
+			continue
+		}
+		if day, err := time.ParseInLocation(dayLayout, key, now.Location()); err == nil {
+			met = append(met, day)
+		}
+	}
+	sort.Slice(met, func(i, j int) bool { return met[i].Before(met[j]) })
+
+	run := 0
+	for i, day := range met {
+		if i > 0 && sameDay(met[i-1].AddDate(0, 0, 1), day) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	today := l.Today(now)
+	end := now
+	if !today.GoalMet {
+		end = now.AddDate(0, 0, -1)
+	}
+	for l.Day(DayKey(end)).GoalMet {
+		current++
+		end = end.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+func sameDay(a, b time.Time) bool {
+	return DayKey(a) == DayKey(b)
+}
+
+// CalendarWeekCount is how many weeks the practice calendar shows.
+const CalendarWeekCount = 12
+
+// HeatLevels is how many shades the practice calendar uses, 0 being no practice.
+const HeatLevels = 5
+
+// HeatLevel grades a day for the practice calendar: 0 for no practice,
+// HeatLevels-1 when the goal was met and the steps between for progress
+// towards it.
+func (l *PracticeLog) HeatLevel(key string) int {
+	d := l.Day(key)
+	switch {
+	case d.GoalMet:
+		return HeatLevels - 1
+	case !d.practised():
+		return 0
+	}
+	var share float64
+	switch l.Goal.Kind {
+	case GoalCount:
+		share = float64(d.Correct) / float64(l.Goal.Count)
+	case GoalTime:
+		share = float64(d.Played) / float64(l.Goal.Duration)
+	}
+	return 1 + min(int(share*float64(HeatLevels-2)), HeatLevels-3)
+}
+
+// CalendarWeeks lays out the weeks days ending with the week of end as
+// columns of seven day keys, Monday first. Days after end are "".
+func CalendarWeeks(end time.Time, weeks int) [][7]string {
+	offset := (int(end.Weekday()) + 6) % 7 // days since Monday
+	start := end.AddDate(0, 0, -offset-7*(weeks-1))
+	last := DayKey(end)
+	cols := make([][7]string, weeks)
+	past := false
+	for w := range cols {
+		for d := range cols[w] {
+			if past {
+				continue
+			}
+			key := DayKey(start.AddDate(0, 0, 7*w+d))
+			cols[w][d] = key
+			past = key == last
+		}
+	}
+	return cols
+}
+
+// DayWord returns "day" or "days" to follow a count of n days.
+func DayWord(n int) string {
+	if n == 1 {
+		return "day"
+	}
+	return "days"
+}
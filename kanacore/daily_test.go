@@ -0,0 +1,121 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDailyGoalRoundTripAndFallback(t *testing.T) {
+	for _, g := range DailyGoals {
+		if got := ParseDailyGoal(g.String()); got != g {
+			t.Errorf("ParseDailyGoal(%q) = %+v, want %+v", g.String(), got, g)
+		}
+	}
+	if got := ParseDailyGoal(""); got != DefaultDailyGoal {
+		t.Errorf("ParseDailyGoal(\"\") = %+v, want default", got)
+	}
+}
+
+func TestPracticeLogCreditsCappedPlayTime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	l := NewPracticeLog(Goal{Kind: GoalTime, Duration: 2 * time.Minute}, nil)
+
+	l.Start(start)
+	l.Record(start.Add(30*time.Second), 1)
+	d := l.Record(start.Add(10*time.Minute), 1) // idle gap is capped
+	if d.Played != 90*time.Second || d.Correct != 2 {
+		t.Fatalf("expected 90s and 2 correct, got %+v", d)
+	}
+	if d.GoalMet {
+		t.Error("goal should not be met after 90s of a 2 minute goal")
+	}
+	d = l.Stop(start.Add(10*time.Minute + 30*time.Second))
+	if !d.GoalMet {
+		t.Errorf("expected the goal met at %v played", d.Played)
+	}
+
+	// Time between sessions is not credited.
+	l.Start(start.Add(time.Hour))
+	if d := l.Record(start.Add(time.Hour+time.Second), 0); d.Played != 2*time.Minute+time.Second {
+		t.Errorf("expected only play time to count, got %v", d.Played)
+	}
+}
+
+func TestPracticeLogStreaks(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+	day := func(offset int) string { return DayKey(now.AddDate(0, 0, offset)) }
+	l := NewPracticeLog(DefaultDailyGoal, []DayTotal{
+		{Day: day(-9), GoalMet: true},
+		{Day: day(-8), GoalMet: true},
+		{Day: day(-7), GoalMet: true},
+		{Day: day(-6), GoalMet: true},
+		{Day: day(-5), Correct: 10}, // practised but short of the goal
+		{Day: day(-2), GoalMet: true},
+		{Day: day(-1), GoalMet: true},
+	})
+
+	current, longest := l.Streaks(now)
+	if current != 2 || longest != 4 {
+		t.Errorf("expected a current streak of 2 while today is open and longest 4, got %d and %d", current, longest)
+	}
+
+	l.Start(now)
+	l.Record(now, DefaultDailyGoal.Count)
+	if current, _ := l.Streaks(now); current != 3 {
+		t.Errorf("expected meeting today's goal to extend the streak to 3, got %d", current)
+	}
+	if current, _ := l.Streaks(now.AddDate(0, 0, 2)); current != 0 {
+		t.Errorf("expected a missed day to break the streak, got %d", current)
+	}
+}
+
+func TestPracticeLogGoalChangeKeepsPastDays(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+	yesterday := DayKey(now.AddDate(0, 0, -1))
+	l := NewPracticeLog(Goal{Kind: GoalCount, Count: 20}, []DayTotal{{Day: yesterday, Correct: 20, GoalMet: true}})
+
+	l.Record(now, 30)
+	if d := l.SetGoal(Goal{Kind: GoalCount, Count: 100}, now); !d.GoalMet {
+		t.Error("a goal met today should stay met after raising the goal")
+	}
+	if !l.Day(yesterday).GoalMet {
+		t.Error("raising the goal should not rewrite past days")
+	}
+	if got := l.Progress(now); got != "30/100 correct today" {
+		t.Errorf("unexpected progress %q", got)
+	}
+}
+
+func TestHeatLevel(t *testing.T) {
+	l := NewPracticeLog(Goal{Kind: GoalCount, Count: 30}, []DayTotal{
+		{Day: "2024-05-01", Correct: 5},
+		{Day: "2024-05-02", Correct: 25},
+		{Day: "2024-05-03", Correct: 30, GoalMet: true},
+	})
+	want := map[string]int{"2024-04-30": 0, "2024-05-01": 1, "2024-05-02": 3, "2024-05-03": HeatLevels - 1}
+	for key, level := range want {
+		if got := l.HeatLevel(key); got != level {
+			t.Errorf("HeatLevel(%s) = %d, want %d", key, got, level)
+		}
+	}
+}
+
+func TestCalendarWeeks(t *testing.T) {
+	end := time.Date(2024, 5, 8, 9, 0, 0, 0, time.Local) // a Wednesday
+	weeks := CalendarWeeks(end, 2)
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+	if weeks[0][0] != "2024-04-29" {
+		t.Errorf("expected the first column to start on Monday 2024-04-29, got %s", weeks[0][0])
+	}
+	if weeks[1][2] != "2024-05-08" || weeks[1][3] != "" {
+		t.Errorf("expected the last week to stop at the end day, got %v", weeks[1])
+	}
+}
+
+func TestDayWord(t *testing.T) {
+	if DayWord(1) != "day" || DayWord(0) != "days" || DayWord(3) != "days" {
+		t.Errorf("unexpected day words %q %q %q", DayWord(0), DayWord(1), DayWord(3))
+	}
+}
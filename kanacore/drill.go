@@ -0,0 +1,85 @@
+package kanacore
+
+import "fmt"
+
+// ModeDrill practises only the characters missed in the previous session.
+// It is started from the game-over screen rather than chosen in settings.
+const ModeDrill GameMode = "drill"
+
+const (
+	// drillHitsPerChar is how many correct answers a drill asks for per character.
+	drillHitsPerChar = 3
+	// drillMinHits is the smallest count goal a drill uses.
+	drillMinHits = 10
+)
+
+// CharTally counts one character's results within a session.
+type CharTally struct {
+	Correct int
+	Missed  int
+}
+
+// Attempts returns how often the character was answered or missed.
+func (t CharTally) Attempts() int {
+	return t.Correct + t.Missed
+}
+
+// Accuracy returns the share of attempts answered correctly, or 0 without attempts.
+func (t CharTally) Accuracy() float64 {
+	if t.Attempts() == 0 {
+		return 0
+	}
+	return float64(t.Correct) / float64(t.Attempts())
+}
+
+// String renders the tally as "correct/attempts (percent)".
+func (t CharTally) String() string {
+	return fmt.Sprintf("%d/%d (%.0f%%)", t.Correct, t.Attempts(), t.Accuracy()*100)
+}
+
+// DrillComparison pairs a character's results in the session that was
+// drilled with its results during the drill.
+type DrillComparison struct {
+	Char   string
+	Before CharTally
+	After  CharTally
+}
+
+// Improved reports whether accuracy went up during the drill.
+func (c DrillComparison) Improved() bool {
+	return c.After.Accuracy() > c.Before.Accuracy()
+}
+
+// DrillCharacters returns the distinct kana on the missed tiles in the order
+// they were first missed. Missed words contribute each of their kana.
+func DrillCharacters(missed []Kana) []string {
+	seen := make(map[string]bool)
+	var chars []string
+	for _, k := range missed {
+		for _, char := range SplitKana(k.Char) {
+			if !seen[char] {
+				seen[char] = true
+				chars = append(chars, char)
+			}
+		}
+	}
+	return chars
+}
+
+// DrillGoal returns the count goal for drilling n characters.
+func DrillGoal(n int) Goal {
+	count := n * drillHitsPerChar
+	if count < drillMinHits {
+		count = drillMinHits
+	}
+	return Goal{Kind: GoalCount, Count: count}
+}
+
+// CompareDrill lists before and after tallies for each drilled character.
+func CompareDrill(chars []string, before, after map[string]CharTally) []DrillComparison {
+	out := make([]DrillComparison, 0, len(chars))
+	for _, char := range chars {
+		out = append(out, DrillComparison{Char: char, Before: before[char], After: after[char]})
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package kanacore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrillCharactersDedupesInMissOrder(t *testing.T) {
+	missed := []Kana{{Char: "ね"}, {Char: "か"}, {Char: "ねこ"}, {Char: "か"}}
+	got := DrillCharacters(missed)
+	want := []string{"ね", "か", "こ"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DrillCharacters = %v, want %v", got, want)
+	}
+	if DrillCharacters(nil) != nil {
+		t.Error("expected no characters without misses")
+	}
+}
+
+func TestDrillGoal(t *testing.T) {
+	if g := DrillGoal(2); g.Kind != GoalCount || g.Count != drillMinHits {
+		t.Errorf("small drill goal = %+v, want count %d", g, drillMinHits)
+	}
+	if g := DrillGoal(6); g.Count != 6*drillHitsPerChar {
+		t.Errorf("drill goal for 6 = %d, want %d", g.Count, 6*drillHitsPerChar)
+	}
+}
+
+func TestCharTally(t *testing.T) {
+	tally := CharTally{Correct: 3, Missed: 1}
+	if tally.Accuracy() != 0.75 {
+		t.Errorf("accuracy = %v, want 0.75", tally.Accuracy())
+	}
+	if tally.String() != "3/4 (75%)" {
+		t.Errorf("String = %q", tally.String())
+	}
+	if (CharTally{}).Accuracy() != 0 {
+		t.Error("expected zero accuracy without attempts")
+	}
+}
+
+func TestCompareDrill(t *testing.T) {
+	before := map[string]CharTally{"か": {Correct: 1, Missed: 2}}
+	after := map[string]CharTally{"か": {Correct: 4}, "き": {Missed: 1}}
+	got := CompareDrill([]string{"か", "き"}, before, after)
+	if len(got) != 2 || !got[0].Improved() || got[1].Improved() {
+		t.Errorf("unexpected comparison %+v", got)
+	}
+}
+
+func TestDrillModeRules(t *testing.T) {
+	if ModeDrill.EndsOnMisses() || ModeDrill.UsesScoreLimit() || !ModeDrill.HasGoal() {
+		t.Error("drills should end only on their goal")
+	}
+	if ParseGameMode(string(ModeDrill)) != DefaultGameMode {
+		t.Error("drill mode should not be restored from settings")
+	}
+}
@@ -0,0 +1,86 @@
+package kanacore
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ghost replays a recorded run alongside a new session: its tiles fall again
+// at the moments they first fell and its score climbs as it did, so the
+// player races their past self on the same sequence.
+type Ghost struct {
+	Replay *Replay
+	next   int // index of the first event Due has not looked at
+}
+
+// NewGhost races the recorded run r.
+func NewGhost(r *Replay) *Ghost {
+	return &Ghost{Replay: r}
+}
+
+// Due returns the tiles the recorded run spawned up to offset at that
+// earlier calls have not returned.
+func (g *Ghost) Due(at time.Duration) []ReplayEvent {
+	var due []ReplayEvent
+	for ; g.next < len(g.Replay.Events); g.next++ {
+		e := g.Replay.Events[g.next]
+		if e.At > at {
+			break
+		}
+		if e.Kind == ReplaySpawn {
+			due = append(due, e)
+		}
+	}
+	return due
+}
+
+// Running reports whether the recorded run was still going at offset at.
+// Once it has ended, sessions go back to spawning their own tiles.
+func (g *Ghost) Running(at time.Duration) bool {
+	return at < g.Replay.Length
+}
+
+// Score returns the recorded run's score at offset at.
+func (g *Ghost) Score(at time.Duration) int {
+	score := 0
+	for _, e := range g.Replay.Events {
+		if e.At > at {
+			break
+		}
+		if e.Kind == ReplayScore {
+			score = e.Score
+		}
+	}
+	return score
+}
+
+// Final returns the score the recorded run ended with.
+func (g *Ghost) Final() int {
+	return g.Score(g.Replay.Length)
+}
+
+// GhostResult announces how a finished session's score compares with the
+// ghost's final score.
+func GhostResult(score, ghost int) string {
+	switch {
+	case score > ghost:
+		return fmt.Sprintf("You beat your best run by %d points!", score-ghost)
+	case score < ghost:
+		return fmt.Sprintf("Your best run stays ahead by %d points.", ghost-score)
+	default:
+		return "You tied your best run."
+	}
+}
+
+// GhostGap describes the player's score against the ghost's at the same
+// moment.
+func GhostGap(score, ghost int) string {
+	switch {
+	case score > ghost:
+		return fmt.Sprintf("you lead by %d", score-ghost)
+	case score < ghost:
+		return fmt.Sprintf("you trail by %d", ghost-score)
+	default:
+		return "level"
+	}
+}
@@ -0,0 +1,55 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGhostDropsTheRecordedTilesOnce(t *testing.T) {
+	g := NewGhost(recordSample())
+
+	due := g.Due(50 * time.Millisecond)
+	if len(due) != 1 || due[0].Char != "か" || due[0].X != 0.25 {
+		t.Fatalf("expected か first, got %+v", due)
+	}
+	if again := g.Due(50 * time.Millisecond); len(again) != 0 {
+		t.Errorf("expected か to be returned once, got %+v", again)
+	}
+	due = g.Due(time.Second)
+	if len(due) != 1 || due[0].Char != "ね" || due[0].Speed != 0.2 {
+		t.Errorf("expected ね next, got %+v", due)
+	}
+}
+
+func TestGhostScoreFollowsTheRecording(t *testing.T) {
+	g := NewGhost(recordSample())
+
+	if got := g.Score(100 * time.Millisecond); got != 0 {
+		t.Errorf("expected no score before the first clear, got %d", got)
+	}
+	if got := g.Score(200 * time.Millisecond); got != 10 {
+		t.Errorf("expected 10 after the clear, got %d", got)
+	}
+	if got := g.Final(); got != 10 {
+		t.Errorf("expected a final score of 10, got %d", got)
+	}
+	if !g.Running(250*time.Millisecond) || g.Running(300*time.Millisecond) {
+		t.Error("expected the ghost to run until the recording ends")
+	}
+}
+
+func TestGhostResult(t *testing.T) {
+	cases := []struct {
+		score, ghost int
+		want         string
+	}{
+		{120, 100, "You beat your best run by 20 points!"},
+		{80, 100, "Your best run stays ahead by 20 points."},
+		{100, 100, "You tied your best run."},
+	}
+	for _, c := range cases {
+		if got := GhostResult(c.score, c.ghost); got != c.want {
+			t.Errorf("GhostResult(%d, %d) = %q, want %q", c.score, c.ghost, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package kanacore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoalKind selects what a session goal measures.
+type GoalKind string
+
+const (
+	// GoalNone runs until the player quits.
+	GoalNone GoalKind = "none"
+	// GoalCount ends after a number of correct answers.
+	GoalCount GoalKind = "count"
+	// GoalTime ends after a length of play.
+	GoalTime GoalKind = "time"
+)
+
+// Goal bounds a session that has no fail state.
+type Goal struct {
+	Kind     GoalKind
+	Count    int
+	Duration time.Duration
+}
+
+// ZenGoals lists the selectable zen goals in display order.
+var ZenGoals = []Goal{
+	{Kind: GoalCount, Count: 20},
+	{Kind: GoalCount, Count: 50},
+	{Kind: GoalCount, Count: 100},
+	{Kind: GoalTime, Duration: 5 * time.Minute},
+	{Kind: GoalTime, Duration: 10 * time.Minute},
+	{Kind: GoalNone},
+}
+
+// DefaultZenGoal is used when no zen goal has been configured.
+var DefaultZenGoal = Goal{Kind: GoalCount, Count: 50}
+
+// String encodes the goal for storage, for example "count:50" or "time:300".
+func (g Goal) String() string {
+	switch g.Kind {
+	case GoalCount:
+		return fmt.Sprintf("count:%d", g.Count)
+	case GoalTime:
+		return fmt.Sprintf("time:%d", int(g.Duration/time.Second))
+	default:
+		return string(GoalNone)
+	}
+}
+
+// ParseGoal decodes a stored goal, falling back to DefaultZenGoal.
+func ParseGoal(value string) Goal {
+	return parseGoal(value, DefaultZenGoal)
+}
+
+func parseGoal(value string, fallback Goal) Goal {
+	kind, amount, _ := strings.Cut(strings.TrimSpace(value), ":")
+	n, err := strconv.Atoi(amount)
+	switch GoalKind(kind) {
+	case GoalNone:
+		return Goal{Kind: GoalNone}
+	case GoalCount:
+		if err == nil && n > 0 {
+			return Goal{Kind: GoalCount, Count: n}
+		}
+	case GoalTime:
+		if err == nil && n > 0 {
+			return Goal{Kind: GoalTime, Duration: time.Duration(n) * time.Second}
+		}
+	}
+	return fallback
+}
+
+// Label returns a human readable description of the goal.
+func (g Goal) Label() string {
+	switch g.Kind {
+	case GoalCount:
+		return fmt.Sprintf("%d correct kana", g.Count)
+	case GoalTime:
+		return fmt.Sprintf("%d minutes", int(g.Duration/time.Minute))
+	default:
+		return "No goal (quit when done)"
+	}
+}
+
+// Reached reports whether a session with hits correct answers after elapsed
+// play has met the goal.
+func (g Goal) Reached(hits int, elapsed time.Duration) bool {
+	switch g.Kind {
+	case GoalCount:
+		return hits >= g.Count
+	case GoalTime:
+		return elapsed >= g.Duration
+	default:
+		return false
+	}
+}
+
+// Progress renders how far a session is towards the goal.
+func (g Goal) Progress(hits int, elapsed time.Duration) string {
+	switch g.Kind {
+	case GoalCount:
+		return fmt.Sprintf("%d/%d kana", hits, g.Count)
+	case GoalTime:
+		return FormatClock(g.Duration-elapsed) + " left"
+	default:
+		return fmt.Sprintf("%d kana", hits)
+	}
+}
@@ -0,0 +1,44 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoalRoundTrip(t *testing.T) {
+	for _, g := range ZenGoals {
+		if got := ParseGoal(g.String()); got != g {
+			t.Errorf("ParseGoal(%q) = %+v, want %+v", g.String(), got, g)
+		}
+	}
+}
+
+func TestParseGoalFallsBack(t *testing.T) {
+	for _, in := range []string{"", "count:0", "time:-5", "weekly:3", "count:x"} {
+		if got := ParseGoal(in); got != DefaultZenGoal {
+			t.Errorf("ParseGoal(%q) = %+v, want default", in, got)
+		}
+	}
+}
+
+func TestGoalReached(t *testing.T) {
+	count := Goal{Kind: GoalCount, Count: 3}
+	if count.Reached(2, time.Hour) {
+		t.Error("count goal should ignore elapsed time")
+	}
+	if !count.Reached(3, 0) {
+		t.Error("count goal should be reached at 3 hits")
+	}
+
+	timed := Goal{Kind: GoalTime, Duration: time.Minute}
+	if timed.Reached(100, 59*time.Second) {
+		t.Error("time goal should ignore hits")
+	}
+	if !timed.Reached(0, time.Minute) {
+		t.Error("time goal should be reached after a minute")
+	}
+
+	if (Goal{Kind: GoalNone}).Reached(1000, 24*time.Hour) {
+		t.Error("open-ended goal should never be reached")
+	}
+}
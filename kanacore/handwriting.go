@@ -0,0 +1,251 @@
+package kanacore
+
+import (
+	_ "embed"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Point is a position on a drawing surface. Y grows downward.
+type Point struct {
+	X, Y float64
+}
+
+// Stroke is the path of one pen-down to pen-up movement, in drawing order.
+type Stroke []Point
+
+// Drawing is a handwritten character as its strokes in the order drawn.
+type Drawing []Stroke
+
+// StrokeTemplate is a reference drawing of a kana.
+type StrokeTemplate struct {
+	Char    string
+	Strokes Drawing
+}
+
+// Match is one candidate from Recognize. Lower distances are closer.
+type Match struct {
+	Char     string
+	Distance float64
+}
+
+// resamplePoints is how many points each stroke is resampled to before comparison.
+const resamplePoints = 16
+
+// MaxMatchDistance is the largest distance at which Recognize still reports
+// a candidate. Drawings further from every template are unrecognised.
+const MaxMatchDistance = 0.3
+
+//go:embed data/hiragana_strokes.txt
+var hiraganaStrokeData string
+
+var (
+	templatesOnce sync.Once
+	templates     []StrokeTemplate
+)
+
+// HiraganaTemplates returns the bundled stroke templates for the basic hiragana.
+func HiraganaTemplates() []StrokeTemplate {
+	templatesOnce.Do(func() {
+		templates = parseStrokeTemplates(hiraganaStrokeData)
+	})
+	return templates
+}
+
+func parseStrokeTemplates(data string) []StrokeTemplate {
+	var parsed []StrokeTemplate
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		char, rest, _ := strings.Cut(line, " ")
+		var drawing Drawing
+		for _, field := range strings.Split(rest, "|") {
+			var stroke Stroke
+			for _, pair := range strings.Fields(field) {
+				xs, ys, ok := strings.Cut(pair, ",")
+				if !ok {
+					continue
+				}
+				x, errX := strconv.ParseFloat(xs, 64)
+				y, errY := strconv.ParseFloat(ys, 64)
+				if errX != nil || errY != nil {
+					continue
+				}
+				stroke = append(stroke, Point{X: x, Y: y})
+			}
+			if len(stroke) > 0 {
+				drawing = append(drawing, stroke)
+			}
+		}
+		if len(drawing) > 0 {
+			parsed = append(parsed, StrokeTemplate{Char: char, Strokes: drawing})
+		}
+	}
+	return parsed
+}
+
+// Recognize compares a drawing with the bundled templates and returns the
+// candidates closest first, one per character. Only templates with the same
+// stroke count are considered, and strokes are compared in order and
+// direction, so writing a kana the wrong way scores poorly. When allowed is
+// non-empty only those characters are considered.
+func Recognize(d Drawing, allowed []string) []Match {
+	return RecognizeWith(HiraganaTemplates(), d, allowed)
+}
+
+// RecognizeWith is Recognize against a custom template set.
+func RecognizeWith(set []StrokeTemplate, d Drawing, allowed []string) []Match {
+	d = dropEmptyStrokes(d)
+	if len(d) == 0 {
+		return nil
+	}
+	var permitted map[string]bool
+	if len(allowed) > 0 {
+		permitted = make(map[string]bool, len(allowed))
+		for _, c := range allowed {
+			permitted[c] = true
+		}
+	}
+
+	input := normalizeDrawing(d)
+	best := make(map[string]float64)
+	for _, t := range set {
+		if len(t.Strokes) != len(d) || (permitted != nil && !permitted[t.Char]) {
+			continue
+		}
+		dist := drawingDistance(input, normalizeDrawing(t.Strokes))
+		if dist > MaxMatchDistance {
+			continue
+		}
+		if prev, ok := best[t.Char]; !ok || dist < prev {
+			best[t.Char] = dist
+		}
+	}
+
+	matches := make([]Match, 0, len(best))
+	for char, dist := range best {
+		matches = append(matches, Match{Char: char, Distance: dist})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Char < matches[j].Char
+	})
+	return matches
+}
+
+func dropEmptyStrokes(d Drawing) Drawing {
+	kept := make(Drawing, 0, len(d))
+	for _, s := range d {
+		if len(s) > 0 {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// normalizeDrawing resamples every stroke, scales the drawing uniformly so its
+// larger side is 1 and moves its centroid to the origin.
+func normalizeDrawing(d Drawing) Drawing {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, s := range d {
+		for _, p := range s {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+			minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+		}
+	}
+	size := math.Max(maxX-minX, maxY-minY)
+	if size == 0 {
+		size = 1
+	}
+
+	out := make(Drawing, len(d))
+	var cx, cy float64
+	count := 0
+	for i, s := range d {
+		r := resample(s, resamplePoints)
+		for j := range r {
+			r[j] = Point{X: (r[j].X - minX) / size, Y: (r[j].Y - minY) / size}
+			cx += r[j].X
+			cy += r[j].Y
+			count++
+		}
+		out[i] = r
+	}
+	cx /= float64(count)
+	cy /= float64(count)
+	for _, s := range out {
+		for j := range s {
+			s[j].X -= cx
+			s[j].Y -= cy
+		}
+	}
+	return out
+}
+
+// resample returns n points evenly spaced along the stroke's path.
+func resample(s Stroke, n int) Stroke {
+	out := make(Stroke, 0, n)
+	length := 0.0
+	for i := 1; i < len(s); i++ {
+		length += distance(s[i-1], s[i])
+	}
+	if length == 0 {
+		for len(out) < n {
+			out = append(out, s[0])
+		}
+		return out
+	}
+
+	interval := length / float64(n-1)
+	out = append(out, s[0])
+	acc := 0.0
+	prev := s[0]
+	for i := 1; i < len(s) && len(out) < n; {
+		seg := distance(prev, s[i])
+		if acc+seg >= interval && seg > 0 {
+			t := (interval - acc) / seg
+			q := Point{X: prev.X + t*(s[i].X-prev.X), Y: prev.Y + t*(s[i].Y-prev.Y)}
+			out = append(out, q)
+			prev = q
+			acc = 0
+			continue
+		}
+		acc += seg
+		prev = s[i]
+		i++
+	}
+	// Rounding can leave the last point off; pad with the stroke's end.
+	for len(out) < n {
+		out = append(out, s[len(s)-1])
+	}
+	return out
+}
+
+// drawingDistance averages the point-to-point distance of matching strokes.
+// Both drawings must be normalised and have the same stroke count.
+func drawingDistance(a, b Drawing) float64 {
+	total := 0.0
+	count := 0
+	for i := range a {
+		for j := range a[i] {
+			total += distance(a[i][j], b[i][j])
+			count++
+		}
+	}
+	if count == 0 {
+		return math.Inf(1)
+	}
+	return total / float64(count)
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
@@ -0,0 +1,99 @@
+package kanacore
+
+import "testing"
+
+func TestHiraganaTemplatesCoverCharacterSet(t *testing.T) {
+	have := map[string]bool{}
+	for _, tmpl := range HiraganaTemplates() {
+		have[tmpl.Char] = true
+	}
+	for _, char := range Hiragana().GetCharacters() {
+		if !have[char] {
+			t.Errorf("no stroke template for %s", char)
+		}
+	}
+}
+
+func TestRecognizeTemplatesAsThemselves(t *testing.T) {
+	for _, tmpl := range HiraganaTemplates() {
+		matches := Recognize(tmpl.Strokes, nil)
+		if len(matches) == 0 || matches[0].Char != tmpl.Char {
+			t.Errorf("template %s recognised as %v", tmpl.Char, matches)
+		}
+	}
+}
+
+// transform scales, shifts and slightly wobbles a drawing, as a player's
+// imprecise copy drawn at a different size on the pad would.
+func transform(d Drawing, scale, dx, dy, wobble float64) Drawing {
+	out := make(Drawing, len(d))
+	for i, s := range d {
+		for j, p := range s {
+			w := wobble
+			if (i+j)%2 == 1 {
+				w = -wobble
+			}
+			out[i] = append(out[i], Point{X: p.X*scale + dx + w, Y: p.Y*scale + dy - w})
+		}
+	}
+	return out
+}
+
+func TestRecognizeToleratesScaleAndWobble(t *testing.T) {
+	for _, tmpl := range HiraganaTemplates() {
+		drawn := transform(tmpl.Strokes, 2.5, 40, -10, 4)
+		matches := Recognize(drawn, nil)
+		if len(matches) == 0 || matches[0].Char != tmpl.Char {
+			t.Errorf("scaled %s recognised as %v", tmpl.Char, matches)
+		}
+	}
+}
+
+func TestRecognizeRespectsStrokeDirection(t *testing.T) {
+	ku := Drawing{{{65, 10}, {30, 50}, {65, 90}}}
+	backwards := Drawing{{{65, 90}, {30, 50}, {65, 10}}}
+	if m := Recognize(ku, []string{"く"}); len(m) != 1 {
+		t.Fatalf("expected く to be recognised, got %v", m)
+	}
+	if m := Recognize(backwards, []string{"く"}); len(m) != 0 {
+		t.Errorf("expected く drawn bottom-up to be rejected, got %v", m)
+	}
+}
+
+func TestRecognizeRespectsStrokeOrder(t *testing.T) {
+	var ni Drawing
+	for _, tmpl := range HiraganaTemplates() {
+		if tmpl.Char == "に" {
+			ni = tmpl.Strokes
+		}
+	}
+	swapped := Drawing{ni[1], ni[2], ni[0]}
+	if m := Recognize(swapped, []string{"に"}); len(m) != 0 {
+		t.Errorf("expected に with strokes out of order to be rejected, got %v", m)
+	}
+}
+
+func TestRecognizeRequiresMatchingStrokeCount(t *testing.T) {
+	strokes := map[string]int{}
+	for _, tmpl := range HiraganaTemplates() {
+		strokes[tmpl.Char] = len(tmpl.Strokes)
+	}
+	single := Drawing{{{20, 20}, {80, 20}}}
+	for _, m := range Recognize(single, nil) {
+		if strokes[m.Char] != 1 {
+			t.Errorf("one stroke matched %d-stroke %s", strokes[m.Char], m.Char)
+		}
+	}
+	if m := Recognize(nil, nil); m != nil {
+		t.Errorf("expected no matches for an empty drawing, got %v", m)
+	}
+}
+
+func TestRecognizeAllowedFilter(t *testing.T) {
+	ku := Drawing{{{65, 10}, {30, 50}, {65, 90}}}
+	for _, m := range Recognize(ku, []string{"し", "へ"}) {
+		if m.Char != "し" && m.Char != "へ" {
+			t.Errorf("expected only allowed characters, got %v", m)
+		}
+	}
+}
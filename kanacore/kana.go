@@ -1,12 +1,32 @@
 package kanacore
 
+import "sort"
+
 // Kana represents a falling character in the game.
 type Kana struct {
-	Char   string
-	Romaji string
-	X      float32
-	Y      float32
-	Speed  float32
+	Char    string
+	Romaji  string
+	X       float32
+	Y       float32
+	Speed   float32
+	Reverse bool // show the romaji and expect the kana as the answer
+	Seq     int  // spawn number within the session, linking replay events to the tile
+}
+
+// Display returns the text shown on the tile.
+func (k Kana) Display() string {
+	if k.Reverse {
+		return k.Romaji
+	}
+	return k.Char
+}
+
+// Answer returns the text the player must enter to clear the tile.
+func (k Kana) Answer() string {
+	if k.Reverse {
+		return k.Char
+	}
+	return k.Romaji
 }
 
 // CharacterSet represents a collection of kana characters with their romaji.
@@ -34,12 +54,14 @@ func Hiragana() CharacterSet {
 	}
 }
 
-// GetCharacters returns a slice of all characters in the set.
+// GetCharacters returns a slice of all characters in the set, sorted so a
+// seeded spawn sequence picks the same kana every time.
 func (cs CharacterSet) GetCharacters() []string {
 	chars := make([]string, 0, len(cs.Data))
 	for char := range cs.Data {
 		chars = append(chars, char)
 	}
+	sort.Strings(chars)
 	return chars
 }
 
@@ -48,3 +70,13 @@ func (cs CharacterSet) GetRomaji(char string) (string, bool) {
 	romaji, exists := cs.Data[char]
 	return romaji, exists
 }
+
+// GetChar returns the character whose romaji is romaji.
+func (cs CharacterSet) GetChar(romaji string) (string, bool) {
+	for char, r := range cs.Data {
+		if r == romaji {
+			return char, true
+		}
+	}
+	return "", false
+}
@@ -1,5 +1,7 @@
 package kanacore
 
+import "slices"
+
 // KanaRow groups related kana characters by their consonant row.
 type KanaRow struct {
 	ID         string
@@ -42,3 +44,42 @@ func DefaultRowIDs() []string {
 	}
 	return ids
 }
+
+// NormalizeRowSelection drops duplicates and unknown IDs from a row
+// selection and returns the rest in row order.
+func NormalizeRowSelection(ids []string) []string {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	normalized := make([]string, 0, len(wanted))
+	for _, row := range AllKanaRows {
+		if wanted[row.ID] {
+			normalized = append(normalized, row.ID)
+		}
+	}
+	return normalized
+}
+
+// SameRows reports whether two row ID lists select the same rows.
+func SameRows(a, b []string) bool {
+	return slices.Equal(NormalizeRowSelection(a), NormalizeRowSelection(b))
+}
+
+// NormalizeCharSelection drops duplicates and characters outside AllKanaRows
+// from a character selection and returns the rest in row order.
+func NormalizeCharSelection(chars []string) []string {
+	wanted := make(map[string]bool, len(chars))
+	for _, char := range chars {
+		wanted[char] = true
+	}
+	normalized := make([]string, 0, len(wanted))
+	for _, row := range AllKanaRows {
+		for _, char := range row.Characters {
+			if wanted[char] {
+				normalized = append(normalized, char)
+			}
+		}
+	}
+	return normalized
+}
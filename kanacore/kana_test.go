@@ -41,3 +41,28 @@ func TestDefaultRowIDs(t *testing.T) {
 		t.Fatalf("expected 11 row IDs, got %d", len(ids))
 	}
 }
+
+func TestNormalizeCharSelection(t *testing.T) {
+	got := NormalizeCharSelection([]string{"ぬ", "あ", "x", "め", "ぬ", ""})
+	want := []string{"あ", "ぬ", "め"}
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeCharSelection = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("NormalizeCharSelection = %v, want %v", got, want)
+		}
+	}
+	if got := NormalizeCharSelection(nil); len(got) != 0 {
+		t.Errorf("expected empty selection, got %v", got)
+	}
+}
+
+func TestSameRowsIgnoresOrderAndDuplicates(t *testing.T) {
+	if !SameRows([]string{"k", "vowels", "k"}, []string{"vowels", "k"}) {
+		t.Error("expected order and duplicates not to matter")
+	}
+	if SameRows([]string{"vowels"}, []string{"vowels", "k"}) {
+		t.Error("expected different selections to differ")
+	}
+}
@@ -0,0 +1,198 @@
+package kanacore
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ModeLookalike drills one group of look-alike kana in rapid alternation.
+const ModeLookalike GameMode = "lookalike"
+
+// LookalikeGoal is the count goal that ends a look-alike drill.
+var LookalikeGoal = Goal{Kind: GoalCount, Count: 20}
+
+// lookalikeSpawnInterval is how often a look-alike drill drops a new tile.
+const lookalikeSpawnInterval = 1500 * time.Millisecond
+
+// LookalikeMinConfusions is how often two kana must have been mistaken for
+// each other before ExtendLookalikes groups them.
+const LookalikeMinConfusions = 3
+
+// LookalikeGroup is a set of kana that are easily mistaken for each other.
+type LookalikeGroup []string
+
+// ID identifies the group in settings; it is the kana joined together.
+func (g LookalikeGroup) ID() string {
+	return strings.Join(g, "")
+}
+
+// Label renders the group as "ぬ / め".
+func (g LookalikeGroup) Label() string {
+	return strings.Join(g, " / ")
+}
+
+// Contains reports whether char belongs to the group.
+func (g LookalikeGroup) Contains(char string) bool {
+	for _, c := range g {
+		if c == char {
+			return true
+		}
+	}
+	return false
+}
+
+// LookalikeGroups are the curated groups of classic hiragana confusions.
+var LookalikeGroups = []LookalikeGroup{
+	{"ぬ", "め"},
+	{"る", "ろ"},
+	{"わ", "れ", "ね"},
+	{"さ", "ち"},
+	{"は", "ほ"},
+	{"あ", "お"},
+	{"い", "り"},
+	{"こ", "に"},
+	{"う", "ら"},
+	{"き", "さ"},
+}
+
+// LookalikeMixup is one look-alike confusion: Given's romaji was answered
+// for Shown, Count times.
+type LookalikeMixup struct {
+	Shown string
+	Given string
+	Count int
+}
+
+// ExtendLookalikes adds a pair group for every two kana the player has
+// mistaken for each other at least LookalikeMinConfusions times in total
+// that do not already share a group. Confusions map a kana to the romaji
+// answered for it, as recorded by the store. Added pairs follow the given
+// groups, most confused first.
+func ExtendLookalikes(groups []LookalikeGroup, confusions map[string]map[string]int) []LookalikeGroup {
+	pairs := make(map[[2]string]int)
+	for _, m := range confusionMixups(confusions) {
+		a, b := m.Shown, m.Given
+		if b < a {
+			a, b = b, a
+		}
+		pairs[[2]string{a, b}] += m.Count
+	}
+
+	extended := append([]LookalikeGroup(nil), groups...)
+	var added []LookalikeGroup
+	counts := make(map[string]int)
+	for pair, count := range pairs {
+		if count < LookalikeMinConfusions || sameGroup(groups, pair[0], pair[1]) {
+			continue
+		}
+		g := LookalikeGroup{pair[0], pair[1]}
+		added = append(added, g)
+		counts[g.ID()] = count
+	}
+	sort.Slice(added, func(i, j int) bool {
+		if counts[added[i].ID()] != counts[added[j].ID()] {
+			return counts[added[i].ID()] > counts[added[j].ID()]
+		}
+		return added[i].ID() < added[j].ID()
+	})
+	return append(extended, added...)
+}
+
+// FindLookalikeGroup returns the group with the given ID.
+func FindLookalikeGroup(groups []LookalikeGroup, id string) (LookalikeGroup, bool) {
+	for _, g := range groups {
+		if g.ID() == id {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+// MostConfusedLookalike returns the group whose kana the player has mistaken
+// for each other most often, or the first group without any history.
+func MostConfusedLookalike(groups []LookalikeGroup, confusions map[string]map[string]int) LookalikeGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+	mixups := confusionMixups(confusions)
+	best, bestCount := groups[0], 0
+	for _, g := range groups {
+		count := 0
+		for _, m := range mixups {
+			if g.Contains(m.Shown) && g.Contains(m.Given) {
+				count += m.Count
+			}
+		}
+		if count > bestCount {
+			best, bestCount = g, count
+		}
+	}
+	return best
+}
+
+// NextLookalike picks the next kana to drop in a look-alike drill: any
+// member of the group except prev, so the group alternates.
+func NextLookalike(g LookalikeGroup, prev string, rng *rand.Rand) string {
+	if len(g) == 0 {
+		return ""
+	}
+	options := make([]string, 0, len(g))
+	for _, c := range g {
+		if c != prev {
+			options = append(options, c)
+		}
+	}
+	if len(options) == 0 {
+		return g[0]
+	}
+	return options[rng.Intn(len(options))]
+}
+
+// LookalikeMixups returns the confusions between kana that share a group,
+// most frequent first.
+func LookalikeMixups(groups []LookalikeGroup, confusions map[string]map[string]int) []LookalikeMixup {
+	var out []LookalikeMixup
+	for _, m := range confusionMixups(confusions) {
+		if sameGroup(groups, m.Shown, m.Given) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// confusionMixups converts recorded confusions into kana pairs, most frequent
+// first. Answers that are not a single hiragana's romaji are skipped.
+func confusionMixups(confusions map[string]map[string]int) []LookalikeMixup {
+	set := Hiragana()
+	var out []LookalikeMixup
+	for shown, answers := range confusions {
+		for romaji, count := range answers {
+			given, ok := set.GetChar(romaji)
+			if !ok || given == shown || count <= 0 {
+				continue
+			}
+			out = append(out, LookalikeMixup{Shown: shown, Given: given, Count: count})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		if out[i].Shown != out[j].Shown {
+			return out[i].Shown < out[j].Shown
+		}
+		return out[i].Given < out[j].Given
+	})
+	return out
+}
+
+func sameGroup(groups []LookalikeGroup, a, b string) bool {
+	for _, g := range groups {
+		if g.Contains(a) && g.Contains(b) {
+			return true
+		}
+	}
+	return false
+}
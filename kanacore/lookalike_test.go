@@ -0,0 +1,90 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookalikeGroupsAreKnownKana(t *testing.T) {
+	for _, g := range LookalikeGroups {
+		if len(g) < 2 {
+			t.Errorf("group %s has fewer than two kana", g.Label())
+		}
+		for _, char := range g {
+			if _, ok := CharToRow[char]; !ok {
+				t.Errorf("group %s contains unknown kana %s", g.Label(), char)
+			}
+		}
+	}
+}
+
+func TestExtendLookalikesAddsFrequentPairs(t *testing.T) {
+	confusions := map[string]map[string]int{
+		"く": {"he": 2},
+		"へ": {"ku": 1},
+		"て": {"to": 1},
+		"ぬ": {"me": 5},
+	}
+	groups := ExtendLookalikes(LookalikeGroups, confusions)
+	if len(groups) != len(LookalikeGroups)+1 {
+		t.Fatalf("expected one added group, got %d groups", len(groups))
+	}
+	added := groups[len(groups)-1]
+	if !added.Contains("く") || !added.Contains("へ") {
+		t.Errorf("expected く/へ to be added, got %s", added.Label())
+	}
+}
+
+func TestMostConfusedLookalike(t *testing.T) {
+	confusions := map[string]map[string]int{
+		"る": {"ro": 2},
+		"ろ": {"ru": 2},
+		"ぬ": {"me": 3},
+		"さ": {"ka": 9},
+	}
+	if got := MostConfusedLookalike(LookalikeGroups, confusions); got.ID() != "るろ" {
+		t.Errorf("expected るろ, got %s", got.ID())
+	}
+	if got := MostConfusedLookalike(LookalikeGroups, nil); got.ID() != LookalikeGroups[0].ID() {
+		t.Errorf("expected the first group without history, got %s", got.ID())
+	}
+}
+
+func TestNextLookalikeAlternates(t *testing.T) {
+	g := LookalikeGroup{"ぬ", "め"}
+	prev := "ぬ"
+	rng := NewRand(1)
+	for i := 0; i < 10; i++ {
+		next := NextLookalike(g, prev, rng)
+		if next == prev || !g.Contains(next) {
+			t.Fatalf("expected the other kana after %s, got %s", prev, next)
+		}
+		prev = next
+	}
+}
+
+func TestLookalikeMixupsOnlyPairsInAGroup(t *testing.T) {
+	confusions := map[string]map[string]int{
+		"ぬ": {"me": 1},
+		"わ": {"ne": 2, "ka": 4},
+	}
+	got := LookalikeMixups(LookalikeGroups, confusions)
+	if len(got) != 2 {
+		t.Fatalf("expected two mixups, got %v", got)
+	}
+	if got[0] != (LookalikeMixup{Shown: "わ", Given: "ね", Count: 2}) {
+		t.Errorf("expected わ/ね first, got %v", got[0])
+	}
+}
+
+func TestLookalikeModeRules(t *testing.T) {
+	if ModeLookalike.EndsOnMisses() || !ModeLookalike.HasGoal() {
+		t.Error("expected a look-alike drill to end on its goal, not on misses")
+	}
+	if ModeLookalike.SpawnInterval() >= ModeClassic.SpawnInterval() {
+		t.Error("expected look-alike tiles to drop faster than classic ones")
+	}
+	if ModeClassic.SpawnInterval() != 4*time.Second {
+		t.Errorf("expected classic tiles every 4s, got %v", ModeClassic.SpawnInterval())
+	}
+}
@@ -0,0 +1,157 @@
+package kanacore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MasteryLevel grades how well a character is known, from red (struggling)
+// through yellow (learning) to green (mastered).
+type MasteryLevel string
+
+const (
+	// LevelUnseen marks a character that has never been answered or missed.
+	LevelUnseen MasteryLevel = "unseen"
+	// LevelRed marks a character that is often missed or was just missed.
+	LevelRed MasteryLevel = "red"
+	// LevelYellow marks a character that is being learned, or was mastered
+	// but has not been practised recently.
+	LevelYellow MasteryLevel = "yellow"
+	// LevelGreen marks a character that meets every mastery criterion.
+	LevelGreen MasteryLevel = "green"
+)
+
+// rowMasteryShare is the share of a row's characters that must be green for
+// the row to count as mastered.
+const rowMasteryShare = 0.8
+
+// CharRecord is everything known about a player's results for one character.
+type CharRecord struct {
+	Correct  int
+	Missed   int
+	Streak   int       // consecutive correct answers up to now
+	LastSeen time.Time // when the character was last answered or missed; zero if unknown
+}
+
+// Attempts returns how often the character was answered or missed.
+func (r CharRecord) Attempts() int {
+	return r.Correct + r.Missed
+}
+
+// Accuracy returns the share of attempts answered correctly, or 0 without attempts.
+func (r CharRecord) Accuracy() float64 {
+	if r.Attempts() == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Attempts())
+}
+
+// MasteryPolicy decides when a character counts as mastered.
+type MasteryPolicy struct {
+	MinCorrect  int           // correct answers needed in total
+	MinAccuracy float64       // share of attempts answered correctly, 0 to 1
+	MinStreak   int           // consecutive correct answers needed
+	MaxAge      time.Duration // mastery fades to yellow after this long without practice; 0 never fades
+}
+
+// DefaultMasteryPolicy returns the policy used when none has been configured.
+func DefaultMasteryPolicy() MasteryPolicy {
+	return MasteryPolicy{
+		MinCorrect:  3,
+		MinAccuracy: 0.8,
+		MinStreak:   3,
+		MaxAge:      14 * 24 * time.Hour,
+	}
+}
+
+// Level grades a character's record at time now. A character is red when its
+// accuracy is below the policy or its last attempt was a miss, green when it
+// meets every criterion and was practised recently enough, and yellow otherwise.
+func (p MasteryPolicy) Level(r CharRecord, now time.Time) MasteryLevel {
+	if r.Attempts() == 0 {
+		return LevelUnseen
+	}
+	if r.Accuracy() < p.MinAccuracy || (r.Missed > 0 && r.Streak == 0) {
+		return LevelRed
+	}
+	if r.Correct < p.MinCorrect || r.Streak < p.MinStreak {
+		return LevelYellow
+	}
+	if p.MaxAge > 0 && !r.LastSeen.IsZero() && now.Sub(r.LastSeen) > p.MaxAge {
+		return LevelYellow
+	}
+	return LevelGreen
+}
+
+// RowMastered reports whether enough of a row's characters are green.
+// A row without characters is mastered.
+func (p MasteryPolicy) RowMastered(records []CharRecord, now time.Time) bool {
+	return p.SetMastered(records, now, rowMasteryShare)
+}
+
+// SetMastered reports whether at least share of the characters are green,
+// needing at least one. A set without characters is mastered.
+func (p MasteryPolicy) SetMastered(records []CharRecord, now time.Time, share float64) bool {
+	if len(records) == 0 {
+		return true
+	}
+	green := 0
+	for _, r := range records {
+		if p.Level(r, now) == LevelGreen {
+			green++
+		}
+	}
+	threshold := int(float64(len(records)) * share)
+	if threshold == 0 {
+		threshold = 1
+	}
+	return green >= threshold
+}
+
+// String encodes the policy for storage, for example
+// "correct=3,accuracy=80,streak=3,days=14".
+func (p MasteryPolicy) String() string {
+	return fmt.Sprintf("correct=%d,accuracy=%d,streak=%d,days=%d",
+		p.MinCorrect, int(p.MinAccuracy*100+0.5), p.MinStreak, int(p.MaxAge/(24*time.Hour)))
+}
+
+// Label returns a human readable summary of the policy.
+func (p MasteryPolicy) Label() string {
+	label := fmt.Sprintf("%d correct, %d%% accuracy, streak of %d",
+		p.MinCorrect, int(p.MinAccuracy*100+0.5), p.MinStreak)
+	if p.MaxAge > 0 {
+		label += fmt.Sprintf(", practised within %d days", int(p.MaxAge/(24*time.Hour)))
+	}
+	return label
+}
+
+// ParseMasteryPolicy decodes a stored policy. Missing or invalid fields keep
+// their DefaultMasteryPolicy values.
+func ParseMasteryPolicy(value string) MasteryPolicy {
+	p := DefaultMasteryPolicy()
+	for _, field := range strings.Split(value, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || n < 0 {
+			continue
+		}
+		switch key {
+		case "correct":
+			p.MinCorrect = n
+		case "accuracy":
+			if n <= 100 {
+				p.MinAccuracy = float64(n) / 100
+			}
+		case "streak":
+			p.MinStreak = n
+		case "days":
+			p.MaxAge = time.Duration(n) * 24 * time.Hour
+		}
+	}
+	return p
+}
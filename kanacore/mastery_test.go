@@ -0,0 +1,70 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMasteryLevel(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := DefaultMasteryPolicy()
+	tests := []struct {
+		name   string
+		record CharRecord
+		want   MasteryLevel
+	}{
+		{"unseen", CharRecord{}, LevelUnseen},
+		{"learning", CharRecord{Correct: 1, Streak: 1, LastSeen: now}, LevelYellow},
+		{"mastered", CharRecord{Correct: 10, Missed: 1, Streak: 5, LastSeen: now}, LevelGreen},
+		{"missed often", CharRecord{Correct: 20, Missed: 20, Streak: 5, LastSeen: now}, LevelRed},
+		{"just missed", CharRecord{Correct: 10, Missed: 1, Streak: 0, LastSeen: now}, LevelRed},
+		{"short streak", CharRecord{Correct: 10, Missed: 1, Streak: 2, LastSeen: now}, LevelYellow},
+		{"stale", CharRecord{Correct: 10, Streak: 10, LastSeen: now.Add(-15 * 24 * time.Hour)}, LevelYellow},
+		{"unknown recency", CharRecord{Correct: 10, Streak: 10}, LevelGreen},
+	}
+	for _, tt := range tests {
+		if got := p.Level(tt.record, now); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMasteryIgnoresAgeWhenDisabled(t *testing.T) {
+	now := time.Now()
+	p := DefaultMasteryPolicy()
+	p.MaxAge = 0
+	old := CharRecord{Correct: 10, Streak: 10, LastSeen: now.Add(-365 * 24 * time.Hour)}
+	if got := p.Level(old, now); got != LevelGreen {
+		t.Errorf("expected green without a recency limit, got %s", got)
+	}
+}
+
+func TestRowMastered(t *testing.T) {
+	now := time.Now()
+	p := DefaultMasteryPolicy()
+	green := CharRecord{Correct: 5, Streak: 5, LastSeen: now}
+	red := CharRecord{Correct: 5, Missed: 20, LastSeen: now}
+	if !p.RowMastered([]CharRecord{green, green, green, green, red}, now) {
+		t.Error("expected 4 of 5 green characters to master the row")
+	}
+	if p.RowMastered([]CharRecord{green, green, green, red, red}, now) {
+		t.Error("expected 3 of 5 green characters not to master the row")
+	}
+	if !p.RowMastered(nil, now) {
+		t.Error("expected an empty row to be mastered")
+	}
+}
+
+func TestMasteryPolicyRoundTrip(t *testing.T) {
+	p := MasteryPolicy{MinCorrect: 5, MinAccuracy: 0.9, MinStreak: 4, MaxAge: 7 * 24 * time.Hour}
+	if got := ParseMasteryPolicy(p.String()); got != p {
+		t.Errorf("round trip of %q gave %+v", p.String(), got)
+	}
+	if got := ParseMasteryPolicy(""); got != DefaultMasteryPolicy() {
+		t.Errorf("expected defaults for an empty value, got %+v", got)
+	}
+	got := ParseMasteryPolicy("correct=7,accuracy=250,streak=x")
+	if got.MinCorrect != 7 || got.MinAccuracy != DefaultMasteryPolicy().MinAccuracy || got.MinStreak != DefaultMasteryPolicy().MinStreak {
+		t.Errorf("expected only the valid field to apply, got %+v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package kanacore
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+//go:embed data/mnemonics.txt
+var mnemonicData string
+
+var (
+	mnemonicsOnce sync.Once
+	mnemonics     map[string]string
+)
+
+// DefaultMnemonics returns the bundled memory hints keyed by kana. The map is
+// shared and must not be modified.
+func DefaultMnemonics() map[string]string {
+	mnemonicsOnce.Do(func() {
+		mnemonics = make(map[string]string)
+		for _, line := range strings.Split(mnemonicData, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			char, hint, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			mnemonics[char] = strings.TrimSpace(hint)
+		}
+	})
+	return mnemonics
+}
+
+// Mnemonic returns the player's hint for char from overrides, falling back
+// to the bundled one. It returns "" when neither has a hint.
+func Mnemonic(char string, overrides map[string]string) string {
+	if hint := overrides[char]; hint != "" {
+		return hint
+	}
+	return DefaultMnemonics()[char]
+}
+
+// KanaMnemonic returns the first hint for the kana in text, which may be a
+// word, and the kana it belongs to.
+func KanaMnemonic(text string, overrides map[string]string) (char, hint string) {
+	for _, c := range SplitKana(text) {
+		if hint := Mnemonic(c, overrides); hint != "" {
+			return c, hint
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,28 @@
+package kanacore
+
+import "testing"
+
+func TestDefaultMnemonicsCoverEveryKana(t *testing.T) {
+	hints := DefaultMnemonics()
+	for char := range CharToRow {
+		if hints[char] == "" {
+			t.Errorf("no mnemonic for %s", char)
+		}
+	}
+}
+
+func TestMnemonicOverrides(t *testing.T) {
+	overrides := map[string]string{"ぬ": "my noodles"}
+	if got := Mnemonic("ぬ", overrides); got != "my noodles" {
+		t.Errorf("expected the override, got %q", got)
+	}
+	if got := Mnemonic("め", overrides); got != DefaultMnemonics()["め"] {
+		t.Errorf("expected the bundled hint, got %q", got)
+	}
+	if got := Mnemonic("x", nil); got != "" {
+		t.Errorf("expected no hint, got %q", got)
+	}
+	if char, hint := KanaMnemonic("ねこ", overrides); char != "ね" || hint == "" {
+		t.Errorf("expected the word's first hint, got %s %q", char, hint)
+	}
+}
@@ -0,0 +1,159 @@
+package kanacore
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameMode selects the rules that decide how a session is played and ends.
+type GameMode string
+
+const (
+	// ModeClassic ends on the score limit, 10 misses or quitting.
+	ModeClassic GameMode = "classic"
+	// ModeTimeAttack ends when the countdown clock runs out.
+	ModeTimeAttack GameMode = "time_attack"
+	// ModeDaily is the daily challenge: a time attack over every basic
+	// hiragana whose tiles are seeded by the date, so everyone plays the
+	// same sequence and can compare scores.
+	ModeDaily GameMode = "daily"
+	// ModeZen never ends on misses; it ends on its Goal or when the player quits.
+	ModeZen GameMode = "zen"
+	// ModeWords drops short vocabulary words instead of single characters.
+	ModeWords GameMode = "words"
+	// ModeReverse shows romaji and expects the kana as the answer.
+	ModeReverse GameMode = "reverse"
+	// ModeChoice offers a few romaji answers to pick from instead of typing.
+	ModeChoice GameMode = "choice"
+	// ModeHandwriting shows romaji and asks the player to draw the kana.
+	ModeHandwriting GameMode = "handwriting"
+)
+
+// DefaultGameMode is used when no mode has been configured.
+const DefaultGameMode = ModeClassic
+
+// AllGameModes lists the selectable modes in display order.
+var AllGameModes = []GameMode{ModeClassic, ModeTimeAttack, ModeDaily, ModeZen, ModeWords, ModeReverse, ModeChoice, ModeLookalike, ModeHandwriting}
+
+// TimeAttackDurations lists the selectable countdown lengths.
+var TimeAttackDurations = []time.Duration{60 * time.Second, 120 * time.Second, 300 * time.Second}
+
+// DefaultTimeAttackDuration is used when no countdown length has been configured.
+const DefaultTimeAttackDuration = 60 * time.Second
+
+// DailyChallengeDuration is the countdown length of the daily challenge.
+const DailyChallengeDuration = 120 * time.Second
+
+// defaultSpawnInterval is how often a new tile drops in most modes.
+const defaultSpawnInterval = 4 * time.Second
+
+// ParseGameMode converts a stored value into a mode, falling back to the default.
+func ParseGameMode(value string) GameMode {
+	for _, m := range AllGameModes {
+		if string(m) == value {
+			return m
+		}
+	}
+	return DefaultGameMode
+}
+
+// Label returns a human readable name for the mode.
+func (m GameMode) Label() string {
+	switch m {
+	case ModeTimeAttack:
+		return "Time attack"
+	case ModeDaily:
+		return "Daily challenge"
+	case ModeZen:
+		return "Zen (no fail)"
+	case ModeWords:
+		return "Vocabulary words"
+	case ModeReverse:
+		return "Reverse (romaji → kana)"
+	case ModeChoice:
+		return "Multiple choice"
+	case ModeHandwriting:
+		return "Handwriting (draw kana)"
+	case ModeDrill:
+		return "Drill missed"
+	case ModeLookalike:
+		return "Look-alike drill"
+	default:
+		return "Classic"
+	}
+}
+
+// Timed reports whether sessions in this mode run against a countdown.
+func (m GameMode) Timed() bool {
+	return m == ModeTimeAttack || m == ModeDaily
+}
+
+// Countdown returns the countdown length of a timed session given the
+// configured time attack length. The daily challenge always runs for
+// DailyChallengeDuration.
+func (m GameMode) Countdown(configured time.Duration) time.Duration {
+	if m == ModeDaily {
+		return DailyChallengeDuration
+	}
+	return configured
+}
+
+// EndsOnMisses reports whether 10 misses end a session in this mode.
+func (m GameMode) EndsOnMisses() bool {
+	return m != ModeZen && m != ModeDrill && m != ModeLookalike
+}
+
+// HasGoal reports whether sessions in this mode end on a Goal.
+func (m GameMode) HasGoal() bool {
+	return m == ModeZen || m == ModeDrill || m == ModeLookalike
+}
+
+// UsesScoreLimit reports whether reaching the score limit ends a session in this mode.
+func (m GameMode) UsesScoreLimit() bool {
+	return m == ModeClassic || m == ModeWords || m == ModeReverse || m == ModeChoice || m == ModeHandwriting
+}
+
+// MultipleChoice reports whether answers are picked from offered choices
+// rather than typed.
+func (m GameMode) MultipleChoice() bool {
+	return m == ModeChoice
+}
+
+// Production reports whether the mode asks the player to produce kana rather
+// than recognise them. Production practice is tracked separately.
+func (m GameMode) Production() bool {
+	return m == ModeReverse || m == ModeHandwriting
+}
+
+// Drawn reports whether answers are drawn on a pad, which only the desktop
+// app offers.
+func (m GameMode) Drawn() bool {
+	return m == ModeHandwriting
+}
+
+// SpawnInterval returns how often a new tile drops in this mode.
+func (m GameMode) SpawnInterval() time.Duration {
+	if m == ModeLookalike {
+		return lookalikeSpawnInterval
+	}
+	return defaultSpawnInterval
+}
+
+// NormalizeTimeLimit snaps d to one of TimeAttackDurations, falling back to the default.
+func NormalizeTimeLimit(d time.Duration) time.Duration {
+	for _, allowed := range TimeAttackDurations {
+		if d == allowed {
+			return d
+		}
+	}
+	return DefaultTimeAttackDuration
+}
+
+// FormatClock renders a remaining duration as m:ss, rounding up to the next second.
+func FormatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	secs := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
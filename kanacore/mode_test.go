@@ -0,0 +1,51 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGameMode(t *testing.T) {
+	if got := ParseGameMode("time_attack"); got != ModeTimeAttack {
+		t.Errorf("expected time attack, got %q", got)
+	}
+	if got := ParseGameMode("unknown"); got != DefaultGameMode {
+		t.Errorf("expected default mode for unknown value, got %q", got)
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{-time.Second, "0:00"},
+		{59*time.Second + 100*time.Millisecond, "1:00"},
+		{61 * time.Second, "1:01"},
+		{300 * time.Second, "5:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatClock(tt.in); got != tt.want {
+			t.Errorf("FormatClock(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTimeLimit(t *testing.T) {
+	if got := NormalizeTimeLimit(120 * time.Second); got != 120*time.Second {
+		t.Errorf("expected 120s to be kept, got %v", got)
+	}
+	if got := NormalizeTimeLimit(7 * time.Second); got != DefaultTimeAttackDuration {
+		t.Errorf("expected unsupported limit to fall back to default, got %v", got)
+	}
+}
+
+func TestHandwritingIsDrawnProduction(t *testing.T) {
+	if !ModeHandwriting.Drawn() || !ModeHandwriting.Production() {
+		t.Error("expected handwriting to be a drawn production mode")
+	}
+	if ModeReverse.Drawn() || ModeClassic.Production() {
+		t.Error("only handwriting should need the drawing pad")
+	}
+}
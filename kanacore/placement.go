@@ -0,0 +1,205 @@
+package kanacore
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	// PlacementSamples is how many kana of each row the placement test asks.
+	PlacementSamples = 2
+	// placementStopAfter is how many unknown rows in a row end the test early.
+	placementStopAfter = 2
+)
+
+// PlacementTest is a short adaptive quiz estimating which rows a new player
+// already knows. Rows are tested in AllKanaRows order with PlacementSamples
+// random kana each; a row is known when every sample is answered. A miss
+// moves straight on to the next row, and the test stops after
+// placementStopAfter unknown rows in a row.
+type PlacementTest struct {
+	cs         CharacterSet
+	rng        *rand.Rand // picks each row's samples
+	row        int        // index into AllKanaRows of the row being tested
+	samples    []string   // kana asked for the current row
+	asked      int        // samples of the current row answered so far
+	answers    map[string]bool
+	known      []string
+	unknownRun int
+	done       bool
+}
+
+// NewPlacementTest starts a placement test over the character set, sampling
+// each row's kana with rng.
+func NewPlacementTest(cs CharacterSet, rng *rand.Rand) *PlacementTest {
+	p := &PlacementTest{cs: cs, rng: rng, answers: make(map[string]bool)}
+	p.startRow(0)
+	return p
+}
+
+// startRow picks the samples for the row at index i, or ends the test.
+func (p *PlacementTest) startRow(i int) {
+	p.row, p.asked = i, 0
+	if i >= len(AllKanaRows) || p.unknownRun >= placementStopAfter {
+		p.done = true
+		return
+	}
+	chars := AllKanaRows[i].Characters
+	n := min(PlacementSamples, len(chars))
+	p.samples = p.samples[:0]
+	for _, j := range p.rng.Perm(len(chars))[:n] {
+		p.samples = append(p.samples, chars[j])
+	}
+}
+
+// Current returns the kana to answer next, reporting false once the test is done.
+func (p *PlacementTest) Current() (Kana, bool) {
+	if p.done {
+		return Kana{}, false
+	}
+	char := p.samples[p.asked]
+	romaji, _ := p.cs.GetRomaji(char)
+	return Kana{Char: char, Romaji: romaji}, true
+}
+
+// Answer grades the romaji given for the current kana and moves on,
+// reporting whether it was right.
+func (p *PlacementTest) Answer(input string) bool {
+	k, ok := p.Current()
+	if !ok {
+		return false
+	}
+	correct := strings.ToLower(strings.TrimSpace(input)) == k.Romaji
+	p.answers[k.Char] = correct
+	p.asked++
+
+	switch {
+	case !correct:
+		p.unknownRun++
+		p.startRow(p.row + 1)
+	case p.asked == len(p.samples):
+		p.known = append(p.known, AllKanaRows[p.row].ID)
+		p.unknownRun = 0
+		p.startRow(p.row + 1)
+	}
+	return correct
+}
+
+// Done reports whether the test has finished.
+func (p *PlacementTest) Done() bool {
+	return p.done
+}
+
+// Progress returns the 1-based number of the row being tested and the row count.
+func (p *PlacementTest) Progress() (row, rows int) {
+	return min(p.row+1, len(AllKanaRows)), len(AllKanaRows)
+}
+
+// Result summarises the answers given so far.
+func (p *PlacementTest) Result() PlacementResult {
+	r := PlacementResult{
+		Known:   append([]string(nil), p.known...),
+		Answers: make(map[string]bool, len(p.answers)),
+	}
+	for char, ok := range p.answers {
+		r.Answers[char] = ok
+	}
+	return r
+}
+
+// PlacementResult is the outcome of a placement test.
+type PlacementResult struct {
+	Known   []string        // IDs of rows every sample of which was answered
+	Answers map[string]bool // kana asked and whether each was answered
+}
+
+// Rows returns the rows to start practising: the known rows plus the first
+// row that is not known, so there is always something new to learn.
+func (r PlacementResult) Rows() []string {
+	known := make(map[string]bool, len(r.Known))
+	for _, id := range r.Known {
+		known[id] = true
+	}
+	rows := make([]string, 0, len(r.Known)+1)
+	added := false
+	for _, row := range AllKanaRows {
+		switch {
+		case known[row.ID]:
+			rows = append(rows, row.ID)
+		case !added:
+			rows = append(rows, row.ID)
+			added = true
+		}
+	}
+	return rows
+}
+
+// Summary describes which rows the result found known and which row
+// practice starts on.
+func (r PlacementResult) Summary() string {
+	known := make(map[string]bool, len(r.Known))
+	for _, id := range r.Known {
+		known[id] = true
+	}
+	var knownLabels, newLabels []string
+	for _, row := range AllKanaRows {
+		if known[row.ID] {
+			knownLabels = append(knownLabels, row.Label)
+		}
+	}
+	for _, id := range r.Rows() {
+		if known[id] {
+			continue
+		}
+		for _, row := range AllKanaRows {
+			if row.ID == id {
+				newLabels = append(newLabels, row.Label)
+			}
+		}
+	}
+
+	var b strings.Builder
+	if len(knownLabels) == 0 {
+		b.WriteString("Starting from the beginning.")
+	} else {
+		b.WriteString("You already know: " + strings.Join(knownLabels, ", ") + ".")
+	}
+	if len(newLabels) > 0 {
+		b.WriteString("\nNew to practise: " + strings.Join(newLabels, ", ") + ".")
+	}
+	return b.String()
+}
+
+// Baseline returns starting statistics implied by the result: every kana of a
+// known row counts as mastered under policy, and other kana that were asked
+// keep the single answer given.
+func (r PlacementResult) Baseline(policy MasteryPolicy, now time.Time) map[string]CharRecord {
+	mastered := CharRecord{
+		Correct:  max(policy.MinCorrect, 1),
+		Streak:   max(policy.MinStreak, 1),
+		LastSeen: now,
+	}
+	baseline := make(map[string]CharRecord)
+	for _, id := range r.Known {
+		for _, row := range AllKanaRows {
+			if row.ID != id {
+				continue
+			}
+			for _, char := range row.Characters {
+				baseline[char] = mastered
+			}
+		}
+	}
+	for char, ok := range r.Answers {
+		if _, seeded := baseline[char]; seeded {
+			continue
+		}
+		if ok {
+			baseline[char] = CharRecord{Correct: 1, Streak: 1, LastSeen: now}
+		} else {
+			baseline[char] = CharRecord{Missed: 1, LastSeen: now}
+		}
+	}
+	return baseline
+}
@@ -0,0 +1,89 @@
+package kanacore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// answerPlacement answers every question, correctly only for the given rows.
+func answerPlacement(p *PlacementTest, knownRows ...string) {
+	known := make(map[string]bool)
+	for _, id := range knownRows {
+		known[id] = true
+	}
+	for {
+		k, ok := p.Current()
+		if !ok {
+			return
+		}
+		if known[CharToRow[k.Char]] {
+			p.Answer(" " + k.Romaji + " ")
+		} else {
+			p.Answer("?")
+		}
+	}
+}
+
+func TestPlacementTestStopsAfterUnknownRows(t *testing.T) {
+	p := NewPlacementTest(Hiragana(), NewRand(1))
+	answerPlacement(p, "vowels", "k")
+	if !p.Done() {
+		t.Fatal("expected the test to finish")
+	}
+	r := p.Result()
+	if len(r.Known) != 2 || r.Known[0] != "vowels" || r.Known[1] != "k" {
+		t.Errorf("expected vowels and k known, got %v", r.Known)
+	}
+	// Two samples for each known row, then one miss each for the S- and T-rows.
+	if len(r.Answers) != 2*PlacementSamples+placementStopAfter {
+		t.Errorf("expected the test to stop early, got %d answers", len(r.Answers))
+	}
+	if rows := r.Rows(); len(rows) != 3 || rows[2] != "s" {
+		t.Errorf("expected to start on vowels, k and s, got %v", rows)
+	}
+}
+
+func TestPlacementTestSkipsPastAnIsolatedGap(t *testing.T) {
+	p := NewPlacementTest(Hiragana(), NewRand(1))
+	answerPlacement(p, "vowels", "s", "t")
+	r := p.Result()
+	if len(r.Known) != 3 {
+		t.Errorf("expected one unknown row not to end the test, got %v", r.Known)
+	}
+	if rows := r.Rows(); len(rows) != 4 || rows[1] != "k" {
+		t.Errorf("expected the K-row as the new row, got %v", rows)
+	}
+}
+
+func TestPlacementBaseline(t *testing.T) {
+	policy := DefaultMasteryPolicy()
+	now := time.Now()
+	r := PlacementResult{Known: []string{"vowels"}, Answers: map[string]bool{"あ": true, "か": false, "さ": true}}
+	baseline := r.Baseline(policy, now)
+
+	for _, char := range AllKanaRows[0].Characters {
+		if level := policy.Level(baseline[char], now); level != LevelGreen {
+			t.Errorf("expected %s to start green, got %s", char, level)
+		}
+	}
+	if level := policy.Level(baseline["か"], now); level != LevelRed {
+		t.Errorf("expected a missed kana to start red, got %s", level)
+	}
+	if baseline["さ"].Correct != 1 {
+		t.Errorf("expected a single correct answer for さ, got %+v", baseline["さ"])
+	}
+	if _, ok := baseline["こ"]; ok {
+		t.Error("expected kana that were not asked to have no baseline")
+	}
+}
+
+func TestPlacementSummaryNamesKnownAndNewRows(t *testing.T) {
+	r := PlacementResult{Known: []string{"vowels", "k"}}
+	if got, want := r.Summary(), "You already know: Vowels (あ), K-row (か).\nNew to practise: S-row (さ)."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := (PlacementResult{}).Summary(); !strings.HasPrefix(got, "Starting from the beginning.") {
+		t.Errorf("expected a fresh start, got %q", got)
+	}
+}
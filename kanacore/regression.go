@@ -0,0 +1,161 @@
+package kanacore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// regressionWindow is how many recent answers per row RowHistory keeps.
+	regressionWindow = 10
+	// regressionMinAttempts is how many recent answers a row needs before its
+	// accuracy is judged.
+	regressionMinAttempts = 6
+	// RegressionAccuracy is the recent accuracy below which a row collapses.
+	RegressionAccuracy = 0.5
+	// RecoveryAccuracy is the recent accuracy at which a collapsed row recovers.
+	RecoveryAccuracy = 0.8
+)
+
+// RowHistory keeps the most recent results per kana row, oldest first.
+type RowHistory map[string][]bool
+
+// Record adds a result for char to its row's history. Characters outside
+// AllKanaRows are ignored.
+func (h RowHistory) Record(char string, correct bool) {
+	rowID, ok := CharToRow[char]
+	if !ok {
+		return
+	}
+	results := append(h[rowID], correct)
+	if len(results) > regressionWindow {
+		results = results[len(results)-regressionWindow:]
+	}
+	h[rowID] = results
+}
+
+// Accuracy returns the share of the row's recent results that were correct
+// and how many results that covers.
+func (h RowHistory) Accuracy(rowID string) (float64, int) {
+	results := h[rowID]
+	if len(results) == 0 {
+		return 0, 0
+	}
+	correct := 0
+	for _, ok := range results {
+		if ok {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(results)), len(results)
+}
+
+// Regression records that practice was narrowed to a struggling row.
+type Regression struct {
+	Row      string    `json:"row"`      // row whose accuracy collapsed
+	Focus    []string  `json:"focus"`    // rows practised until it recovers
+	Restore  []string  `json:"restore"`  // rows selected before, restored on recovery
+	Accuracy float64   `json:"accuracy"` // recent accuracy that triggered the regression
+	Reason   string    `json:"reason"`
+	At       time.Time `json:"at"`
+}
+
+// DetectRegression looks for a selected row whose recent accuracy has fallen
+// below RegressionAccuracy and, if one is found, proposes narrowing practice
+// to that row plus the last mastered selected row. The worst row wins. No
+// regression is proposed when the focus would not narrow the selection.
+func DetectRegression(h RowHistory, selected []string, mastered func(KanaRow) bool, now time.Time) (Regression, bool) {
+	isSelected := make(map[string]bool, len(selected))
+	for _, id := range selected {
+		isSelected[id] = true
+	}
+
+	var worst *KanaRow
+	worstAcc := RegressionAccuracy
+	for i := range AllKanaRows {
+		row := &AllKanaRows[i]
+		if !isSelected[row.ID] {
+			continue
+		}
+		acc, attempts := h.Accuracy(row.ID)
+		if attempts >= regressionMinAttempts && acc < worstAcc {
+			worst, worstAcc = row, acc
+		}
+	}
+	if worst == nil {
+		return Regression{}, false
+	}
+
+	var anchor *KanaRow
+	for i := range AllKanaRows {
+		row := &AllKanaRows[i]
+		if isSelected[row.ID] && row.ID != worst.ID && mastered(*row) {
+			anchor = row
+		}
+	}
+	focus := make([]string, 0, 2)
+	for _, row := range AllKanaRows {
+		if row.ID == worst.ID || (anchor != nil && row.ID == anchor.ID) {
+			focus = append(focus, row.ID)
+		}
+	}
+	if len(focus) >= len(selected) {
+		return Regression{}, false
+	}
+
+	restore := make([]string, 0, len(selected))
+	for _, row := range AllKanaRows {
+		if isSelected[row.ID] {
+			restore = append(restore, row.ID)
+		}
+	}
+	return Regression{
+		Row:      worst.ID,
+		Focus:    focus,
+		Restore:  restore,
+		Accuracy: worstAcc,
+		Reason: fmt.Sprintf("%s accuracy fell to %.0f%% over the last %d answers",
+			worst.Label, worstAcc*100, len(h[worst.ID])),
+		At: now,
+	}, true
+}
+
+// Recovered reports whether the collapsed row's recent accuracy is back to
+// RecoveryAccuracy.
+func (r Regression) Recovered(h RowHistory) bool {
+	acc, attempts := h.Accuracy(r.Row)
+	return attempts >= regressionMinAttempts && acc >= RecoveryAccuracy
+}
+
+// RowLabel returns the label of the collapsed row.
+func (r Regression) RowLabel() string {
+	for _, row := range AllKanaRows {
+		if row.ID == r.Row {
+			return row.Label
+		}
+	}
+	return r.Row
+}
+
+// String encodes the regression for storage.
+func (r Regression) String() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseRegression decodes a stored regression. It reports false for an empty
+// or invalid value.
+func ParseRegression(value string) (Regression, bool) {
+	if value == "" {
+		return Regression{}, false
+	}
+	var r Regression
+	if err := json.Unmarshal([]byte(value), &r); err != nil || r.Row == "" || len(r.Restore) == 0 {
+		return Regression{}, false
+	}
+	return r, true
+}
@@ -0,0 +1,94 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRowHistoryKeepsRecentResults(t *testing.T) {
+	h := RowHistory{}
+	for i := 0; i < regressionWindow; i++ {
+		h.Record("ま", false)
+	}
+	for i := 0; i < regressionWindow; i++ {
+		h.Record("み", true)
+	}
+	if acc, n := h.Accuracy("m"); acc != 1 || n != regressionWindow {
+		t.Errorf("expected only the last %d results, got %.2f over %d", regressionWindow, acc, n)
+	}
+	h.Record("x", false)
+	if _, n := h.Accuracy(""); n != 0 {
+		t.Error("expected unknown characters to be ignored")
+	}
+}
+
+func TestDetectRegressionFocusesWorstRow(t *testing.T) {
+	h := RowHistory{}
+	for i := 0; i < 8; i++ {
+		h.Record("ま", i%4 == 0) // 25%
+		h.Record("か", i%2 == 0) // 50%, not below the threshold
+	}
+	selected := DefaultRowIDs()
+	mastered := func(row KanaRow) bool { return row.ID == "vowels" || row.ID == "k" || row.ID == "h" }
+	now := time.Now()
+
+	r, ok := DetectRegression(h, selected, mastered, now)
+	if !ok {
+		t.Fatal("expected a regression")
+	}
+	if r.Row != "m" {
+		t.Errorf("expected the M-row to collapse, got %s", r.Row)
+	}
+	if len(r.Focus) != 2 || r.Focus[0] != "h" || r.Focus[1] != "m" {
+		t.Errorf("expected focus on the H-row and M-row, got %v", r.Focus)
+	}
+	if len(r.Restore) != len(selected) {
+		t.Errorf("expected every selected row to be restored later, got %v", r.Restore)
+	}
+	if r.Reason == "" || !r.At.Equal(now) {
+		t.Errorf("expected a reason and time, got %+v", r)
+	}
+}
+
+func TestDetectRegressionNeedsEnoughAnswersAndRoomToNarrow(t *testing.T) {
+	h := RowHistory{}
+	for i := 0; i < regressionMinAttempts-1; i++ {
+		h.Record("ま", false)
+	}
+	never := func(KanaRow) bool { return false }
+	if _, ok := DetectRegression(h, DefaultRowIDs(), never, time.Now()); ok {
+		t.Error("expected no regression before enough answers")
+	}
+	h.Record("ま", false)
+	if _, ok := DetectRegression(h, []string{"m"}, never, time.Now()); ok {
+		t.Error("expected no regression when only the collapsed row is selected")
+	}
+	if _, ok := DetectRegression(h, []string{"vowels", "m"}, never, time.Now()); !ok {
+		t.Error("expected a regression once there is room to narrow")
+	}
+}
+
+func TestRegressionRecoveryAndRoundTrip(t *testing.T) {
+	r := Regression{Row: "m", Focus: []string{"m"}, Restore: []string{"vowels", "m"}, Reason: "why", At: time.Unix(100, 0).UTC()}
+	h := RowHistory{}
+	for i := 0; i < regressionWindow; i++ {
+		h.Record("ま", false)
+	}
+	if r.Recovered(h) {
+		t.Error("expected no recovery while still missing")
+	}
+	for i := 0; i < 8; i++ {
+		h.Record("む", true)
+	}
+	if !r.Recovered(h) {
+		t.Error("expected recovery at 80% recent accuracy")
+	}
+
+	got, ok := ParseRegression(r.String())
+	if !ok || got.Row != r.Row || got.Reason != r.Reason || !got.At.Equal(r.At) || len(got.Restore) != 2 {
+		t.Errorf("round trip gave %+v", got)
+	}
+	if _, ok := ParseRegression(""); ok {
+		t.Error("expected an empty value to parse as no regression")
+	}
+}
@@ -0,0 +1,375 @@
+package kanacore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReplaySpeeds lists the playback speeds replay viewers offer.
+var ReplaySpeeds = []int{1, 2, 4}
+
+// ReplayEventKind is what a replay event records.
+type ReplayEventKind byte
+
+const (
+	// ReplaySpawn is a tile starting to fall.
+	ReplaySpawn ReplayEventKind = 's'
+	// ReplayTick is the engine moving every tile one step down.
+	ReplayTick ReplayEventKind = 't'
+	// ReplayInput is the input buffer changing.
+	ReplayInput ReplayEventKind = 'i'
+	// ReplaySubmit is an answer being submitted.
+	ReplaySubmit ReplayEventKind = 'a'
+	// ReplayClear is a tile being answered.
+	ReplayClear ReplayEventKind = 'c'
+	// ReplayMiss is a tile falling past the bottom.
+	ReplayMiss ReplayEventKind = 'm'
+	// ReplayScore is the score or miss count changing.
+	ReplayScore ReplayEventKind = 'p'
+)
+
+// ReplayEvent is one recorded moment of a session. Which fields are set
+// depends on Kind.
+type ReplayEvent struct {
+	At     time.Duration // since the session started
+	Kind   ReplayEventKind
+	Tile   int     // spawn number of the tile spawned, cleared or missed
+	Char   string  // spawned tile's kana
+	Romaji string  // spawned tile's romaji
+	X      float64 // spawned tile's left edge as a fraction of the field width
+	Speed  float64 // spawned tile's fall per tick as a fraction of the field height
+	Text   string  // input buffer or submitted answer
+	Score  int
+	Missed int
+}
+
+// Replay is a recorded session that can be played back frame by frame.
+type Replay struct {
+	Mode   GameMode
+	Seed   int64
+	Start  time.Time
+	Length time.Duration
+	Reason string // why the session ended
+	Events []ReplayEvent
+}
+
+// Recorder builds a Replay while a session is played. A nil Recorder records
+// nothing, so engines can call it whether or not they are recording.
+type Recorder struct {
+	replay Replay
+	tiles  int // tiles spawned so far
+	input  string
+	score  int
+	missed int
+}
+
+// NewRecorder starts recording a session.
+func NewRecorder(mode GameMode, seed int64, start time.Time) *Recorder {
+	return &Recorder{replay: Replay{Mode: mode, Seed: seed, Start: start}}
+}
+
+func (r *Recorder) add(now time.Time, e ReplayEvent) {
+	e.At = now.Sub(r.replay.Start)
+	r.replay.Events = append(r.replay.Events, e)
+}
+
+// Spawn records a tile starting to fall and returns its spawn number, which
+// the engine keeps in Kana.Seq to report the tile's clear or miss. x and
+// speed are fractions of the field as in ReplayEvent.
+func (r *Recorder) Spawn(now time.Time, k Kana, x, speed float64) int {
+	if r == nil {
+		return 0
+	}
+	r.tiles++
+	r.add(now, ReplayEvent{Kind: ReplaySpawn, Tile: r.tiles, Char: k.Char, Romaji: k.Romaji, X: x, Speed: speed})
+	return r.tiles
+}
+
+// Tick records the engine moving every tile one step down.
+func (r *Recorder) Tick(now time.Time) {
+	if r != nil {
+		r.add(now, ReplayEvent{Kind: ReplayTick})
+	}
+}
+
+// Input records the input buffer, when it changed.
+func (r *Recorder) Input(now time.Time, text string) {
+	if r == nil || text == r.input {
+		return
+	}
+	r.input = text
+	r.add(now, ReplayEvent{Kind: ReplayInput, Text: text})
+}
+
+// Submit records an answer being submitted.
+func (r *Recorder) Submit(now time.Time, text string) {
+	if r != nil {
+		r.add(now, ReplayEvent{Kind: ReplaySubmit, Text: text})
+	}
+}
+
+// Clear records the tile with spawn number tile being answered.
+func (r *Recorder) Clear(now time.Time, tile int) {
+	if r != nil {
+		r.add(now, ReplayEvent{Kind: ReplayClear, Tile: tile})
+	}
+}
+
+// Miss records the tile with spawn number tile falling past the bottom.
+func (r *Recorder) Miss(now time.Time, tile int) {
+	if r != nil {
+		r.add(now, ReplayEvent{Kind: ReplayMiss, Tile: tile})
+	}
+}
+
+// Score records the score and miss count, when either changed.
+func (r *Recorder) Score(now time.Time, score, missed int) {
+	if r == nil || (score == r.score && missed == r.missed) {
+		return
+	}
+	r.score, r.missed = score, missed
+	r.add(now, ReplayEvent{Kind: ReplayScore, Score: score, Missed: missed})
+}
+
+// Finish ends the recording and returns the replay.
+func (r *Recorder) Finish(now time.Time, reason string) *Replay {
+	if r == nil {
+		return nil
+	}
+	replay := r.replay
+	replay.Length = now.Sub(replay.Start)
+	replay.Reason = reason
+	replay.Events = append([]ReplayEvent(nil), r.replay.Events...)
+	return &replay
+}
+
+// ReplayFrame is what the session looked like at one moment of its replay.
+type ReplayFrame struct {
+	At     time.Duration
+	Tiles  []Kana // falling tiles, X and Y as fractions of the field
+	Input  string // input buffer
+	Answer string // last submitted answer
+	Score  int
+	Missed int
+}
+
+// Frame rebuilds the session at offset at. Frames are computed from the
+// start, so seeking anywhere is as cheap as playing on.
+func (r *Replay) Frame(at time.Duration) ReplayFrame {
+	f := ReplayFrame{At: at}
+	for _, e := range r.Events {
+		if e.At > at {
+			break
+		}
+		switch e.Kind {
+		case ReplaySpawn:
+			f.Tiles = append(f.Tiles, Kana{
+				Char:    e.Char,
+				Romaji:  e.Romaji,
+				X:       float32(e.X),
+				Speed:   float32(e.Speed),
+				Reverse: r.Mode.Production(),
+				Seq:     e.Tile,
+			})
+		case ReplayTick:
+			for i := range f.Tiles {
+				f.Tiles[i].Y += f.Tiles[i].Speed
+			}
+		case ReplayClear, ReplayMiss:
+			for i, k := range f.Tiles {
+				if k.Seq == e.Tile {
+					f.Tiles = append(f.Tiles[:i], f.Tiles[i+1:]...)
+					break
+				}
+			}
+		case ReplayInput:
+			f.Input = e.Text
+		case ReplaySubmit:
+			f.Answer = e.Text
+		case ReplayScore:
+			f.Score, f.Missed = e.Score, e.Missed
+		}
+	}
+	return f
+}
+
+// replayHeader starts every replay file, naming the format version.
+const replayHeader = "kana-replay 1"
+
+// Encode writes the replay as gzipped text: a header, then one tab-separated
+// line per event with its offset in milliseconds.
+func (r *Replay) Encode(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+	fmt.Fprintln(bw, replayHeader)
+	fmt.Fprintf(bw, "mode\t%s\n", r.Mode)
+	fmt.Fprintf(bw, "seed\t%d\n", r.Seed)
+	fmt.Fprintf(bw, "start\t%s\n", r.Start.Format(time.RFC3339Nano))
+	fmt.Fprintf(bw, "end\t%d\t%s\n", r.Length.Milliseconds(), strconv.Quote(r.Reason))
+	for _, e := range r.Events {
+		fields := []string{string(e.Kind), strconv.FormatInt(e.At.Milliseconds(), 10)}
+		switch e.Kind {
+		case ReplaySpawn:
+			fields = append(fields, strconv.Itoa(e.Tile), strconv.Quote(e.Char), strconv.Quote(e.Romaji),
+				strconv.FormatFloat(e.X, 'f', 4, 64), strconv.FormatFloat(e.Speed, 'f', 5, 64))
+		case ReplayClear, ReplayMiss:
+			fields = append(fields, strconv.Itoa(e.Tile))
+		case ReplayInput, ReplaySubmit:
+			fields = append(fields, strconv.Quote(e.Text))
+		case ReplayScore:
+			fields = append(fields, strconv.Itoa(e.Score), strconv.Itoa(e.Missed))
+		}
+		fmt.Fprintln(bw, strings.Join(fields, "\t"))
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// DecodeReplay reads a replay written by Encode.
+func DecodeReplay(rd io.Reader) (*Replay, error) {
+	zr, err := gzip.NewReader(rd)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	sc := bufio.NewScanner(zr)
+	if !sc.Scan() || sc.Text() != replayHeader {
+		return nil, errors.New("not a kana replay")
+	}
+	r := &Replay{}
+	for n := 2; sc.Scan(); n++ {
+		fields := strings.Split(sc.Text(), "\t")
+		if err := r.decodeLine(fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Replay) decodeLine(fields []string) error {
+	p := fieldParser{fields: fields}
+	switch fields[0] {
+	case "mode":
+		r.Mode = GameMode(p.text(1))
+	case "seed":
+		r.Seed = int64(p.int(1))
+	case "start":
+		start, err := time.Parse(time.RFC3339Nano, p.text(1))
+		if err != nil {
+			return err
+		}
+		r.Start = start
+	case "end":
+		r.Length = p.millis(1)
+		r.Reason = p.quoted(2)
+	default:
+		if len(fields[0]) != 1 {
+			return fmt.Errorf("unknown record %q", fields[0])
+		}
+		e := ReplayEvent{Kind: ReplayEventKind(fields[0][0]), At: p.millis(1)}
+		switch e.Kind {
+		case ReplaySpawn:
+			e.Tile, e.Char, e.Romaji = p.int(2), p.quoted(3), p.quoted(4)
+			e.X, e.Speed = p.float(5), p.float(6)
+		case ReplayTick:
+		case ReplayClear, ReplayMiss:
+			e.Tile = p.int(2)
+		case ReplayInput, ReplaySubmit:
+			e.Text = p.quoted(2)
+		case ReplayScore:
+			e.Score, e.Missed = p.int(2), p.int(3)
+		default:
+			return fmt.Errorf("unknown event %q", fields[0])
+		}
+		r.Events = append(r.Events, e)
+	}
+	return p.err
+}
+
+// fieldParser reads typed fields of a replay line, keeping the first error.
+type fieldParser struct {
+	fields []string
+	err    error
+}
+
+func (p *fieldParser) text(i int) string {
+	if i >= len(p.fields) {
+		if p.err == nil {
+			p.err = fmt.Errorf("missing field %d", i+1)
+		}
+		return ""
+	}
+	return p.fields[i]
+}
+
+func (p *fieldParser) quoted(i int) string {
+	s, err := strconv.Unquote(p.text(i))
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("field %d: %w", i+1, err)
+	}
+	return s
+}
+
+func (p *fieldParser) int(i int) int {
+	n, err := strconv.Atoi(p.text(i))
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("field %d: %w", i+1, err)
+	}
+	return n
+}
+
+func (p *fieldParser) float(i int) float64 {
+	f, err := strconv.ParseFloat(p.text(i), 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("field %d: %w", i+1, err)
+	}
+	return f
+}
+
+func (p *fieldParser) millis(i int) time.Duration {
+	return time.Duration(p.int(i)) * time.Millisecond
+}
+
+// Save writes the replay to path, creating its directory.
+func (r *Replay) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadReplay reads a replay saved with Save.
+func LoadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := DecodeReplay(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
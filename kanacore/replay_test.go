@@ -0,0 +1,90 @@
+package kanacore
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func recordSample() *Replay {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	r := NewRecorder(ModeClassic, 7, start)
+
+	first := r.Spawn(clock.Now(), Kana{Char: "か", Romaji: "ka"}, 0.25, 0.1)
+	clock.Advance(100 * time.Millisecond)
+	r.Tick(clock.Now())
+	second := r.Spawn(clock.Now(), Kana{Char: "ね", Romaji: "ne"}, 0.5, 0.2)
+	r.Input(clock.Now(), "k")
+	r.Input(clock.Now(), "k") // unchanged, not recorded
+	clock.Advance(100 * time.Millisecond)
+	r.Tick(clock.Now())
+	r.Input(clock.Now(), "ka")
+	r.Submit(clock.Now(), "ka")
+	r.Clear(clock.Now(), first)
+	r.Input(clock.Now(), "")
+	r.Score(clock.Now(), 10, 0)
+	clock.Advance(100 * time.Millisecond)
+	r.Miss(clock.Now(), second)
+	r.Score(clock.Now(), 10, 1)
+	return r.Finish(clock.Now(), "quit")
+}
+
+func TestReplayFrameRebuildsTheSession(t *testing.T) {
+	replay := recordSample()
+	if replay.Length != 300*time.Millisecond || replay.Reason != "quit" {
+		t.Fatalf("unexpected length %v and reason %q", replay.Length, replay.Reason)
+	}
+
+	f := replay.Frame(100 * time.Millisecond)
+	if len(f.Tiles) != 2 || f.Input != "k" {
+		t.Fatalf("expected two tiles and input k at 100ms, got %+v", f)
+	}
+	if f.Tiles[0].Y != 0.1 || f.Tiles[1].Y != 0 {
+		t.Errorf("expected only the first tile to have moved, got %v and %v", f.Tiles[0].Y, f.Tiles[1].Y)
+	}
+
+	f = replay.Frame(200 * time.Millisecond)
+	if len(f.Tiles) != 1 || f.Tiles[0].Char != "ね" || f.Tiles[0].Y != 0.2 {
+		t.Errorf("expected only ね left at 0.2 after clearing か, got %+v", f.Tiles)
+	}
+	if f.Answer != "ka" || f.Input != "" || f.Score != 10 {
+		t.Errorf("unexpected input state %+v", f)
+	}
+
+	f = replay.Frame(replay.Length)
+	if len(f.Tiles) != 0 || f.Missed != 1 {
+		t.Errorf("expected the miss at the end, got %+v", f)
+	}
+}
+
+func TestReplayEncodeRoundTrip(t *testing.T) {
+	replay := recordSample()
+	var buf bytes.Buffer
+	if err := replay.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeReplay(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Start.Equal(replay.Start) {
+		t.Errorf("start %v, want %v", got.Start, replay.Start)
+	}
+	got.Start = replay.Start
+	if !reflect.DeepEqual(got, replay) {
+		t.Errorf("round trip changed the replay:\n got %+v\nwant %+v", got, replay)
+	}
+}
+
+func TestNilRecorderRecordsNothing(t *testing.T) {
+	var r *Recorder
+	if n := r.Spawn(time.Now(), Kana{Char: "あ"}, 0, 0); n != 0 {
+		t.Errorf("expected spawn number 0, got %d", n)
+	}
+	r.Tick(time.Now())
+	if r.Finish(time.Now(), "quit") != nil {
+		t.Error("expected no replay")
+	}
+}
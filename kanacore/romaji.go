@@ -0,0 +1,244 @@
+package kanacore
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// KanaScript selects which kana a Converter produces.
+type KanaScript string
+
+const (
+	// ScriptHiragana produces hiragana.
+	ScriptHiragana KanaScript = "hiragana"
+	// ScriptKatakana produces katakana.
+	ScriptKatakana KanaScript = "katakana"
+)
+
+// romajiTable maps romaji spellings to hiragana. It covers Hepburn and
+// Kunrei spellings, dakuten, yōon, small kana via the x/l prefixes and the
+// common extended syllables. ん and っ are handled by Converter's rules.
+var romajiTable = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"sa": "さ", "shi": "し", "si": "し", "su": "す", "se": "せ", "so": "そ",
+	"ta": "た", "chi": "ち", "ti": "ち", "tsu": "つ", "tu": "つ", "te": "て", "to": "と",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"ha": "は", "hi": "ひ", "fu": "ふ", "hu": "ふ", "he": "へ", "ho": "ほ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"wa": "わ", "wo": "を",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"za": "ざ", "ji": "じ", "zi": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"vu": "ゔ",
+
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sha": "しゃ", "shu": "しゅ", "sho": "しょ", "she": "しぇ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ", "che": "ちぇ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"ja": "じゃ", "ju": "じゅ", "jo": "じょ", "je": "じぇ",
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dyo": "ぢょ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"va": "ゔぁ", "vi": "ゔぃ", "ve": "ゔぇ", "vo": "ゔぉ",
+	"wi": "うぃ", "we": "うぇ", "ye": "いぇ",
+	"thi": "てぃ", "dhi": "でぃ", "twu": "とぅ", "dwu": "どぅ",
+
+	"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+	"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ",
+	"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ",
+	"lya": "ゃ", "lyu": "ゅ", "lyo": "ょ",
+	"xtu": "っ", "xtsu": "っ", "ltu": "っ", "ltsu": "っ",
+	"xwa": "ゎ", "lwa": "ゎ", "xka": "ゕ", "lka": "ゕ", "xke": "ゖ", "lke": "ゖ",
+	"xn": "ん",
+
+	"-": "ー",
+}
+
+// romajiPrefixes holds every proper prefix of a romajiTable key, so the
+// converter knows when to wait for more keystrokes.
+var romajiPrefixes = func() map[string]bool {
+	prefixes := make(map[string]bool)
+	for key := range romajiTable {
+		for i := 1; i < len(key); i++ {
+			prefixes[key[:i]] = true
+		}
+	}
+	return prefixes
+}()
+
+// Converter turns romaji keystrokes into kana the way a Japanese IME does.
+// Letters that may still become part of a syllable stay pending until the
+// next keystroke or Flush decides them.
+type Converter struct {
+	Script  KanaScript
+	kana    strings.Builder
+	pending string
+}
+
+// NewConverter creates a Converter producing the given script.
+func NewConverter(script KanaScript) *Converter {
+	return &Converter{Script: script}
+}
+
+// Feed processes one keystroke. Letters are matched case-insensitively;
+// runes outside ASCII, such as kana from an OS IME, pass through unchanged.
+func (c *Converter) Feed(r rune) {
+	if r >= utf8.RuneSelf {
+		c.Flush()
+		c.kana.WriteRune(r)
+		return
+	}
+	if r >= 'A' && r <= 'Z' {
+		r += 'a' - 'A'
+	}
+	c.pending += string(r)
+	c.resolve()
+}
+
+// FeedString feeds every rune of s.
+func (c *Converter) FeedString(s string) {
+	for _, r := range s {
+		c.Feed(r)
+	}
+}
+
+// resolve converts as much of the pending romaji as can be decided.
+func (c *Converter) resolve() {
+	for c.pending != "" {
+		p := c.pending
+		switch {
+		case p == "nn":
+			c.emit("ん")
+			c.pending = ""
+		case p == "n'":
+			c.emit("ん")
+			c.pending = ""
+		case len(p) >= 2 && p[0] == 'n' && !strings.ContainsRune("aiueoyn'", rune(p[1])):
+			// n before another consonant is ん.
+			c.emit("ん")
+			c.pending = p[1:]
+		case len(p) >= 2 && p[0] == p[1] && isSokuonConsonant(p[0]):
+			// A doubled consonant is a small っ followed by the syllable.
+			c.emit("っ")
+			c.pending = p[1:]
+		case strings.HasPrefix(p, "tc"):
+			// "tch" as in "matcha" also marks a small っ.
+			c.emit("っ")
+			c.pending = p[1:]
+		default:
+			if kana, ok := romajiTable[p]; ok {
+				c.emit(kana)
+				c.pending = ""
+				return
+			}
+			if romajiPrefixes[p] || p == "n" {
+				return
+			}
+			// Nothing can start with p: keep its first letter as typed.
+			c.kana.WriteByte(p[0])
+			c.pending = p[1:]
+		}
+	}
+}
+
+// isSokuonConsonant reports whether doubling b produces っ.
+func isSokuonConsonant(b byte) bool {
+	return b >= 'a' && b <= 'z' && !strings.ContainsRune("aiueon", rune(b))
+}
+
+func (c *Converter) emit(hiragana string) {
+	if c.Script == ScriptKatakana {
+		hiragana = ToKatakana(hiragana)
+	}
+	c.kana.WriteString(hiragana)
+}
+
+// Flush settles pending letters: a trailing "n" becomes ん and anything else
+// is kept as typed. Call it when the player submits their answer.
+func (c *Converter) Flush() {
+	if c.pending == "n" {
+		c.emit("ん")
+	} else {
+		c.kana.WriteString(c.pending)
+	}
+	c.pending = ""
+}
+
+// Backspace removes the last pending letter, or the last kana when nothing is pending.
+func (c *Converter) Backspace() {
+	if c.pending != "" {
+		c.pending = c.pending[:len(c.pending)-1]
+		return
+	}
+	text := []rune(c.kana.String())
+	if len(text) == 0 {
+		return
+	}
+	c.kana.Reset()
+	c.kana.WriteString(string(text[:len(text)-1]))
+}
+
+// Reset clears all converted and pending text.
+func (c *Converter) Reset() {
+	c.kana.Reset()
+	c.pending = ""
+}
+
+// Kana returns the text converted so far, excluding pending letters.
+func (c *Converter) Kana() string {
+	return c.kana.String()
+}
+
+// Pending returns the letters still waiting to form a syllable.
+func (c *Converter) Pending() string {
+	return c.pending
+}
+
+// String returns the converted text followed by any pending letters.
+func (c *Converter) String() string {
+	return c.kana.String() + c.pending
+}
+
+// ToKatakana converts the hiragana in s to katakana, leaving other runes alone.
+func ToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + ('ァ' - 'ぁ')
+		}
+		return r
+	}, s)
+}
+
+// RomajiToHiragana converts typed romaji into hiragana as far as possible.
+// Letters that may still become part of a syllable (such as a trailing "k")
+// are left unconverted at the end of the result.
+func RomajiToHiragana(input string) string {
+	c := NewConverter(ScriptHiragana)
+	c.FeedString(input)
+	return c.String()
+}
+
+// FinishRomaji converts input like RomajiToHiragana, additionally treating a
+// trailing "n" as ん. Use it when the player submits their answer.
+func FinishRomaji(input string) string {
+	c := NewConverter(ScriptHiragana)
+	c.FeedString(input)
+	c.Flush()
+	return c.String()
+}
@@ -0,0 +1,194 @@
+package kanacore
+
+import "testing"
+
+func TestConverterHiragana(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"vowels", "aiueo", "あいうえお"},
+		{"hepburn", "shichitsufu", "しちつふ"},
+		{"kunrei", "sitituhu", "しちつふ"},
+		{"word", "sakana", "さかな"},
+		{"dakuten", "gazadaba", "がざだば"},
+		{"handakuten", "pipo", "ぴぽ"},
+		{"ji", "jizi", "じじ"},
+		{"wo", "wo", "を"},
+		{"uppercase", "NeKo", "ねこ"},
+
+		{"nn", "nn", "ん"},
+		{"n apostrophe", "kan'i", "かんい"},
+		{"n before consonant", "honda", "ほんだ"},
+		{"n before n-syllable", "konnnichiha", "こんにちは"},
+		{"n before vowel", "kani", "かに"},
+		{"n before y", "konya", "こにゃ"},
+		{"n apostrophe before y", "kon'ya", "こんや"},
+		{"trailing n pending", "hon", "ほn"},
+		{"xn", "xn", "ん"},
+
+		{"double k", "kitte", "きって"},
+		{"double s", "zasshi", "ざっし"},
+		{"double t", "mattaku", "まったく"},
+		{"double p", "ippai", "いっぱい"},
+		{"tch", "matcha", "まっちゃ"},
+		{"double vowel", "okaasan", "おかあさn"},
+
+		{"kya", "kyakyukyo", "きゃきゅきょ"},
+		{"sha", "shashusho", "しゃしゅしょ"},
+		{"sya", "syasyusyo", "しゃしゅしょ"},
+		{"cha", "chachucho", "ちゃちゅちょ"},
+		{"tya", "tyatyutyo", "ちゃちゅちょ"},
+		{"nya", "nyanyunyo", "にゃにゅにょ"},
+		{"ja", "jajujo", "じゃじゅじょ"},
+		{"jya", "jyajyujyo", "じゃじゅじょ"},
+		{"rya", "ryokou", "りょこう"},
+		{"pya", "happyaku", "はっぴゃく"},
+		{"extended", "fafithi", "ふぁふぃてぃ"},
+
+		{"small x", "xaxixuxexo", "ぁぃぅぇぉ"},
+		{"small l", "lalilulelo", "ぁぃぅぇぉ"},
+		{"small y", "xyalyuxyo", "ゃゅょ"},
+		{"small tsu", "xtultuxtsu", "っっっ"},
+		{"small wa", "xwa", "ゎ"},
+
+		{"long vowel", "ra-men", "らーめn"},
+		{"pending consonant", "nek", "ねk"},
+		{"pending yoon", "ky", "ky"},
+		{"invalid consonant", "qa", "qあ"},
+		{"kana passthrough", "かka", "かか"},
+		{"kana settles pending n", "nか", "んか"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConverter(ScriptHiragana)
+			c.FeedString(tt.input)
+			if got := c.String(); got != tt.want {
+				t.Errorf("%q converted to %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverterKatakana(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"kamera", "カメラ"},
+		{"ko-hi-", "コーヒー"},
+		{"kyandhi-", "キャンディー"},
+		{"beddo", "ベッド"},
+		{"vaiorin", "ヴァイオリn"},
+		{"xkaxke", "ヵヶ"},
+		{"fan'", "ファン"},
+	}
+	for _, tt := range tests {
+		c := NewConverter(ScriptKatakana)
+		c.FeedString(tt.input)
+		if got := c.String(); got != tt.want {
+			t.Errorf("%q converted to %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConverterStreams(t *testing.T) {
+	steps := []struct {
+		key     rune
+		kana    string
+		pending string
+	}{
+		{'k', "", "k"},
+		{'y', "", "ky"},
+		{'o', "きょ", ""},
+		{'n', "きょ", "n"},
+		{'t', "きょん", "t"},
+		{'t', "きょんっ", "t"},
+		{'o', "きょんっと", ""},
+	}
+	c := NewConverter(ScriptHiragana)
+	for i, step := range steps {
+		c.Feed(step.key)
+		if c.Kana() != step.kana || c.Pending() != step.pending {
+			t.Errorf("step %d (%q): got kana %q pending %q, want %q %q",
+				i, step.key, c.Kana(), c.Pending(), step.kana, step.pending)
+		}
+	}
+}
+
+func TestConverterFlush(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"hon", "ほん"},
+		{"n", "ん"},
+		{"nek", "ねk"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		c := NewConverter(ScriptHiragana)
+		c.FeedString(tt.input)
+		c.Flush()
+		if got := c.String(); got != tt.want {
+			t.Errorf("Flush after %q = %q, want %q", tt.input, got, tt.want)
+		}
+		if c.Pending() != "" {
+			t.Errorf("Flush after %q left %q pending", tt.input, c.Pending())
+		}
+	}
+}
+
+func TestConverterBackspaceAndReset(t *testing.T) {
+	c := NewConverter(ScriptHiragana)
+	c.FeedString("kak")
+	c.Backspace()
+	if got := c.String(); got != "か" {
+		t.Errorf("after removing pending letter got %q, want か", got)
+	}
+	c.Backspace()
+	if got := c.String(); got != "" {
+		t.Errorf("after removing kana got %q, want empty", got)
+	}
+	c.Backspace()
+	c.FeedString("sushi")
+	c.Reset()
+	if c.String() != "" {
+		t.Errorf("expected Reset to clear text, got %q", c.String())
+	}
+}
+
+func TestRomajiToHiragana(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ka", "か"},
+		{"shi", "し"},
+		{"k", "k"},
+		{"n", "n"},
+		{"hon", "ほn"},
+		{"か", "か"},
+	}
+	for _, tt := range tests {
+		if got := RomajiToHiragana(tt.input); got != tt.want {
+			t.Errorf("RomajiToHiragana(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFinishRomajiConvertsTrailingN(t *testing.T) {
+	if got := FinishRomaji("hon"); got != "ほん" {
+		t.Errorf("FinishRomaji(hon) = %q, want ほん", got)
+	}
+	if got := FinishRomaji("n"); got != "ん" {
+		t.Errorf("FinishRomaji(n) = %q, want ん", got)
+	}
+}
+
+func TestToKatakana(t *testing.T) {
+	if got := ToKatakana("ひらがな ー abc"); got != "ヒラガナ ー abc" {
+		t.Errorf("ToKatakana = %q", got)
+	}
+}
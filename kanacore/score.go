@@ -0,0 +1,122 @@
+package kanacore
+
+import "math"
+
+// ScoreRules configures how points are awarded during a session.
+type ScoreRules struct {
+	BasePoints    int // points for every correct answer
+	ComboStep     int // consecutive hits needed to raise the multiplier by one
+	MaxMultiplier int // upper bound for the combo multiplier
+	SpeedBonusMax int // bonus for answering a tile the moment it spawns
+	WrongPenalty  int // points deducted for a submission that matches nothing
+}
+
+// DefaultScoreRules returns the rules used by both frontends.
+func DefaultScoreRules() ScoreRules {
+	return ScoreRules{
+		BasePoints:    10,
+		ComboStep:     5,
+		MaxMultiplier: 4,
+		SpeedBonusMax: 5,
+		WrongPenalty:  2,
+	}
+}
+
+// ScoreBreakdown records where a session's points came from.
+type ScoreBreakdown struct {
+	Base     int
+	Combo    int
+	Speed    int
+	Penalty  int
+	Hits     int
+	Wrong    int
+	MaxCombo int
+}
+
+// Total returns the net score represented by the breakdown.
+func (b ScoreBreakdown) Total() int {
+	return b.Base + b.Combo + b.Speed - b.Penalty
+}
+
+// Scorer tracks the running combo and breakdown for one session.
+type Scorer struct {
+	Rules     ScoreRules
+	Combo     int
+	Breakdown ScoreBreakdown
+}
+
+// NewScorer creates a Scorer using the given rules.
+func NewScorer(rules ScoreRules) *Scorer {
+	return &Scorer{Rules: rules}
+}
+
+// Multiplier returns the combo multiplier earned by the current combo.
+func (s *Scorer) Multiplier() int {
+	return s.multiplierFor(s.Combo)
+}
+
+func (s *Scorer) multiplierFor(combo int) int {
+	if s.Rules.ComboStep <= 0 {
+		return 1
+	}
+	m := 1 + (combo-1)/s.Rules.ComboStep
+	if m < 1 {
+		m = 1
+	}
+	if s.Rules.MaxMultiplier > 0 && m > s.Rules.MaxMultiplier {
+		m = s.Rules.MaxMultiplier
+	}
+	return m
+}
+
+// Hit scores a correct answer for k, which was cleared while floor units from
+// the top. Tiles cleared higher up earn a larger speed bonus. Returns the
+// points awarded.
+func (s *Scorer) Hit(k Kana, floor float32) int {
+	s.Combo++
+	if s.Combo > s.Breakdown.MaxCombo {
+		s.Breakdown.MaxCombo = s.Combo
+	}
+
+	base := s.Rules.BasePoints
+	combo := base*s.multiplierFor(s.Combo) - base
+
+	speed := 0
+	if floor > 0 && s.Rules.SpeedBonusMax > 0 {
+		remaining := float64(1 - k.Y/floor)
+		remaining = math.Max(0, math.Min(1, remaining))
+		speed = int(math.Round(remaining * float64(s.Rules.SpeedBonusMax)))
+	}
+
+	s.Breakdown.Hits++
+	s.Breakdown.Base += base
+	s.Breakdown.Combo += combo
+	s.Breakdown.Speed += speed
+	return base + combo + speed
+}
+
+// Wrong penalises a submission that matched no tile and breaks the combo.
+// The penalty never takes the total below zero. Returns the points deducted.
+func (s *Scorer) Wrong() int {
+	s.Combo = 0
+	s.Breakdown.Wrong++
+	penalty := s.Rules.WrongPenalty
+	if total := s.Breakdown.Total(); penalty > total {
+		penalty = total
+	}
+	if penalty < 0 {
+		penalty = 0
+	}
+	s.Breakdown.Penalty += penalty
+	return penalty
+}
+
+// Miss breaks the combo when a tile reaches the floor.
+func (s *Scorer) Miss() {
+	s.Combo = 0
+}
+
+// Score returns the current net score.
+func (s *Scorer) Score() int {
+	return s.Breakdown.Total()
+}
@@ -0,0 +1,74 @@
+package kanacore
+
+import "testing"
+
+func TestScorerComboMultiplier(t *testing.T) {
+	s := NewScorer(ScoreRules{BasePoints: 10, ComboStep: 2, MaxMultiplier: 3})
+	// At the floor there is no speed bonus, so only base and combo count.
+	k := Kana{Y: 100}
+	want := []int{10, 10, 20, 20, 30, 30, 30}
+	for i, w := range want {
+		if got := s.Hit(k, 100); got != w {
+			t.Errorf("hit %d: got %d points, want %d", i+1, got, w)
+		}
+	}
+	if s.Breakdown.MaxCombo != len(want) {
+		t.Errorf("max combo = %d, want %d", s.Breakdown.MaxCombo, len(want))
+	}
+	if s.Breakdown.Base != 70 || s.Breakdown.Combo != 80 {
+		t.Errorf("breakdown base/combo = %d/%d, want 70/80", s.Breakdown.Base, s.Breakdown.Combo)
+	}
+}
+
+func TestScorerSpeedBonus(t *testing.T) {
+	tests := []struct {
+		y    float32
+		want int
+	}{
+		{0, 15},
+		{50, 13},
+		{100, 10},
+		{120, 10},
+	}
+	for _, tt := range tests {
+		s := NewScorer(ScoreRules{BasePoints: 10, SpeedBonusMax: 5})
+		if got := s.Hit(Kana{Y: tt.y}, 100); got != tt.want {
+			t.Errorf("Y=%v: got %d, want %d", tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestScorerMissAndWrongBreakCombo(t *testing.T) {
+	s := NewScorer(DefaultScoreRules())
+	s.Hit(Kana{Y: 10}, 10)
+	s.Hit(Kana{Y: 10}, 10)
+	s.Miss()
+	if s.Combo != 0 {
+		t.Fatalf("expected miss to reset combo, got %d", s.Combo)
+	}
+	s.Hit(Kana{Y: 10}, 10)
+	s.Wrong()
+	if s.Combo != 0 {
+		t.Fatalf("expected wrong answer to reset combo, got %d", s.Combo)
+	}
+	if s.Breakdown.MaxCombo != 2 {
+		t.Errorf("max combo = %d, want 2", s.Breakdown.MaxCombo)
+	}
+}
+
+func TestScorerWrongNeverGoesNegative(t *testing.T) {
+	s := NewScorer(ScoreRules{BasePoints: 10, WrongPenalty: 4})
+	if got := s.Wrong(); got != 0 {
+		t.Errorf("penalty at zero score = %d, want 0", got)
+	}
+	s.Hit(Kana{}, 0)
+	s.Wrong()
+	s.Wrong()
+	s.Wrong()
+	if s.Score() != 0 {
+		t.Errorf("score = %d, want 0", s.Score())
+	}
+	if s.Breakdown.Penalty != 10 || s.Breakdown.Wrong != 4 {
+		t.Errorf("penalty/wrong = %d/%d, want 10/4", s.Breakdown.Penalty, s.Breakdown.Wrong)
+	}
+}
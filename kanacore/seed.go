@@ -0,0 +1,30 @@
+package kanacore
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// NewRand returns a random source for spawning tiles. The same seed always
+// yields the same sequence of draws.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// DailySeed derives the daily challenge seed from the local date of t, so
+// every player gets the same tile sequence on the same day.
+func DailySeed(t time.Time) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("daily:" + DayKey(t)))
+	return int64(h.Sum64())
+}
+
+// SessionSeed returns the seed for a session in mode starting at now: the
+// date's seed for the daily challenge and a fresh one otherwise.
+func SessionSeed(mode GameMode, now time.Time) int64 {
+	if mode == ModeDaily {
+		return DailySeed(now)
+	}
+	return now.UnixNano()
+}
@@ -0,0 +1,38 @@
+package kanacore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailySeedIsSharedForTheDay(t *testing.T) {
+	morning := time.Date(2024, 5, 1, 7, 0, 0, 0, time.Local)
+	evening := time.Date(2024, 5, 1, 22, 30, 0, 0, time.Local)
+	if DailySeed(morning) != DailySeed(evening) {
+		t.Error("expected one seed for the whole day")
+	}
+	if DailySeed(morning) == DailySeed(morning.AddDate(0, 0, 1)) {
+		t.Error("expected a new seed the next day")
+	}
+	if SessionSeed(ModeDaily, evening) != DailySeed(morning) {
+		t.Error("expected the daily challenge to use the date's seed")
+	}
+}
+
+func TestNewRandRepeatsForASeed(t *testing.T) {
+	a, b := NewRand(42), NewRand(42)
+	for i := 0; i < 20; i++ {
+		if x, y := a.Intn(1000), b.Intn(1000); x != y {
+			t.Fatalf("draw %d differs: %d and %d", i, x, y)
+		}
+	}
+}
+
+func TestDailyCountdownIsFixed(t *testing.T) {
+	if got := ModeDaily.Countdown(300 * time.Second); got != DailyChallengeDuration {
+		t.Errorf("expected the daily challenge to ignore the configured length, got %v", got)
+	}
+	if got := ModeTimeAttack.Countdown(300 * time.Second); got != 300*time.Second {
+		t.Errorf("expected time attack to use the configured length, got %v", got)
+	}
+}
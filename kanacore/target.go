@@ -0,0 +1,95 @@
+package kanacore
+
+// TargetPolicy decides which tile an answer clears when several falling kana
+// share the same romaji.
+type TargetPolicy string
+
+const (
+	// TargetLowest clears the tile closest to the floor (largest Y).
+	TargetLowest TargetPolicy = "lowest"
+	// TargetSoonest clears the tile that will reach the floor first given its speed.
+	TargetSoonest TargetPolicy = "soonest"
+	// TargetOldest clears the tile that spawned first (slice order).
+	TargetOldest TargetPolicy = "oldest"
+)
+
+// DefaultTargetPolicy is used when no policy has been configured.
+const DefaultTargetPolicy = TargetLowest
+
+// AllTargetPolicies lists the selectable policies in display order.
+var AllTargetPolicies = []TargetPolicy{TargetLowest, TargetSoonest, TargetOldest}
+
+// ParseTargetPolicy converts a stored value into a policy, falling back to the default.
+func ParseTargetPolicy(value string) TargetPolicy {
+	for _, p := range AllTargetPolicies {
+		if string(p) == value {
+			return p
+		}
+	}
+	return DefaultTargetPolicy
+}
+
+// Label returns a human readable name for the policy.
+func (p TargetPolicy) Label() string {
+	switch p {
+	case TargetSoonest:
+		return "Soonest to land"
+	case TargetOldest:
+		return "Oldest first"
+	default:
+		return "Lowest on screen"
+	}
+}
+
+// TicksToFloor estimates how many ticks remain before k reaches floor.
+func (k Kana) TicksToFloor(floor float32) float32 {
+	remaining := floor - k.Y
+	if remaining < 0 {
+		remaining = 0
+	}
+	if k.Speed <= 0 {
+		return remaining
+	}
+	return remaining / k.Speed
+}
+
+// MoreUrgent reports whether a should be cleared before b under the policy.
+// Ties keep slice order, so callers should only replace their current pick
+// when MoreUrgent returns true.
+func (p TargetPolicy) MoreUrgent(a, b Kana, floor float32) bool {
+	switch p {
+	case TargetOldest:
+		return false
+	case TargetSoonest:
+		return a.TicksToFloor(floor) < b.TicksToFloor(floor)
+	default:
+		return a.Y > b.Y
+	}
+}
+
+// SelectTarget returns the index of the kana in kanas whose answer is input
+// and that input should clear, or -1 when nothing matches.
+func SelectTarget(kanas []Kana, input string, floor float32, policy TargetPolicy) int {
+	best := -1
+	for i, k := range kanas {
+		if k.Answer() != input {
+			continue
+		}
+		if best < 0 || policy.MoreUrgent(k, kanas[best], floor) {
+			best = i
+		}
+	}
+	return best
+}
+
+// MostUrgent returns the index of the kana the policy would clear first
+// regardless of its answer, or -1 when kanas is empty.
+func MostUrgent(kanas []Kana, floor float32, policy TargetPolicy) int {
+	best := -1
+	for i, k := range kanas {
+		if best < 0 || policy.MoreUrgent(k, kanas[best], floor) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,73 @@
+package kanacore
+
+import "testing"
+
+func TestSelectTargetDuplicates(t *testing.T) {
+	kanas := []Kana{
+		{Char: "か", Romaji: "ka", Y: 2, Speed: 0.25},
+		{Char: "き", Romaji: "ki", Y: 18, Speed: 0.2},
+		{Char: "か", Romaji: "ka", Y: 10, Speed: 0.15},
+		{Char: "か", Romaji: "ka", Y: 6, Speed: 0.5},
+	}
+
+	tests := []struct {
+		name   string
+		policy TargetPolicy
+		input  string
+		want   int
+	}{
+		{"lowest picks largest Y", TargetLowest, "ka", 2},
+		{"soonest accounts for speed", TargetSoonest, "ka", 3},
+		{"oldest keeps slice order", TargetOldest, "ka", 0},
+		{"single match", TargetLowest, "ki", 1},
+		{"no match", TargetLowest, "ku", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectTarget(kanas, tt.input, 20, tt.policy); got != tt.want {
+				t.Errorf("SelectTarget(%q, %s) = %d, want %d", tt.input, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectTargetTieKeepsFirst(t *testing.T) {
+	kanas := []Kana{
+		{Char: "か", Romaji: "ka", Y: 5, Speed: 0.2},
+		{Char: "か", Romaji: "ka", Y: 5, Speed: 0.2},
+	}
+	for _, policy := range AllTargetPolicies {
+		if got := SelectTarget(kanas, "ka", 20, policy); got != 0 {
+			t.Errorf("%s: expected tie to resolve to index 0, got %d", policy, got)
+		}
+	}
+}
+
+func TestParseTargetPolicy(t *testing.T) {
+	if got := ParseTargetPolicy("soonest"); got != TargetSoonest {
+		t.Errorf("expected soonest, got %q", got)
+	}
+	if got := ParseTargetPolicy(""); got != DefaultTargetPolicy {
+		t.Errorf("expected default for empty value, got %q", got)
+	}
+	if got := ParseTargetPolicy("bogus"); got != DefaultTargetPolicy {
+		t.Errorf("expected default for unknown value, got %q", got)
+	}
+}
+
+func TestMostUrgent(t *testing.T) {
+	kanas := []Kana{
+		{Char: "か", Romaji: "ka", Y: 10, Speed: 5},
+		{Char: "き", Romaji: "ki", Y: 50, Speed: 0.1},
+	}
+	if got := MostUrgent(kanas, 100, TargetLowest); got != 1 {
+		t.Errorf("lowest: expected 1, got %d", got)
+	}
+	if got := MostUrgent(kanas, 100, TargetSoonest); got != 0 {
+		t.Errorf("soonest: expected 0, got %d", got)
+	}
+	if got := MostUrgent(nil, 100, TargetLowest); got != -1 {
+		t.Errorf("empty: expected -1, got %d", got)
+	}
+}
@@ -0,0 +1,79 @@
+package kanacore
+
+import "fmt"
+
+// SplitScreen reports whether two players can race this mode side by side on
+// one machine. Drills follow one player's misses, multiple choice and
+// handwriting need input the players cannot share.
+func (m GameMode) SplitScreen() bool {
+	return m != ModeDrill && !m.MultipleChoice() && !m.Drawn()
+}
+
+// PlayerResult is one player's outcome in a race on the same tiles.
+type PlayerResult struct {
+	Name     string
+	Score    int
+	Hits     int
+	Wrong    int
+	Missed   int
+	MaxCombo int
+}
+
+// Accuracy returns the share of answers and tiles the player got right, from
+// 0 to 1.
+func (r PlayerResult) Accuracy() float64 {
+	total := r.Hits + r.Wrong + r.Missed
+	if total == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(total)
+}
+
+// beats reports whether r ranks above o: a higher score, then more hits,
+// then fewer misses.
+func (r PlayerResult) beats(o PlayerResult) bool {
+	if r.Score != o.Score {
+		return r.Score > o.Score
+	}
+	if r.Hits != o.Hits {
+		return r.Hits > o.Hits
+	}
+	return r.Missed < o.Missed
+}
+
+// MatchWinner returns the index of the winning player, or -1 when the best
+// players are level.
+func MatchWinner(results []PlayerResult) int {
+	best := -1
+	level := false
+	for i, r := range results {
+		switch {
+		case best < 0 || r.beats(results[best]):
+			best, level = i, false
+		case !results[best].beats(r):
+			level = true
+		}
+	}
+	if level {
+		return -1
+	}
+	return best
+}
+
+// MatchVerdict announces the winner of a race and the margin.
+func MatchVerdict(results []PlayerResult) string {
+	winner := MatchWinner(results)
+	if winner < 0 {
+		return "It's a draw!"
+	}
+	margin := results[winner].Score
+	for i, r := range results {
+		if i != winner && results[winner].Score-r.Score < margin {
+			margin = results[winner].Score - r.Score
+		}
+	}
+	if margin == 0 {
+		return fmt.Sprintf("%s wins on the tie-break!", results[winner].Name)
+	}
+	return fmt.Sprintf("%s wins by %d points!", results[winner].Name, margin)
+}
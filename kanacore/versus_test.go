@@ -0,0 +1,38 @@
+package kanacore
+
+import "testing"
+
+func TestMatchWinner(t *testing.T) {
+	cases := []struct {
+		name    string
+		results []PlayerResult
+		want    int
+		verdict string
+	}{
+		{"higher score", []PlayerResult{{Name: "Aki", Score: 80}, {Name: "Ren", Score: 120}}, 1, "Ren wins by 40 points!"},
+		{"hits break ties", []PlayerResult{{Name: "Aki", Score: 100, Hits: 9}, {Name: "Ren", Score: 100, Hits: 8}}, 0, "Aki wins on the tie-break!"},
+		{"draw", []PlayerResult{{Name: "Aki", Score: 50, Hits: 5}, {Name: "Ren", Score: 50, Hits: 5}}, -1, "It's a draw!"},
+		{"closest rival sets the margin", []PlayerResult{{Name: "Aki", Score: 90}, {Name: "Ren", Score: 40}, {Name: "Yui", Score: 70}}, 0, "Aki wins by 20 points!"},
+	}
+	for _, c := range cases {
+		if got := MatchWinner(c.results); got != c.want {
+			t.Errorf("%s: MatchWinner = %d, want %d", c.name, got, c.want)
+		}
+		if got := MatchVerdict(c.results); got != c.verdict {
+			t.Errorf("%s: MatchVerdict = %q, want %q", c.name, got, c.verdict)
+		}
+	}
+}
+
+func TestSplitScreenModes(t *testing.T) {
+	for _, m := range []GameMode{ModeChoice, ModeHandwriting, ModeDrill} {
+		if m.SplitScreen() {
+			t.Errorf("expected %s not to be playable split screen", m)
+		}
+	}
+	for _, m := range []GameMode{ModeClassic, ModeTimeAttack, ModeReverse} {
+		if !m.SplitScreen() {
+			t.Errorf("expected %s to be playable split screen", m)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package kanacore
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Word is a short vocabulary item that falls as a single multi-kana tile.
+type Word struct {
+	Kana    string
+	Romaji  string
+	Meaning string
+}
+
+//go:embed data/n5_words.txt
+var n5WordData string
+
+// N5Words returns the embedded beginner word list. Words containing kana
+// outside the basic hiragana set are skipped.
+func N5Words() []Word {
+	return parseWords(n5WordData, Hiragana())
+}
+
+func parseWords(data string, cs CharacterSet) []Word {
+	var words []Word
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kana, meaning, _ := strings.Cut(line, " ")
+		romaji, ok := cs.Romanize(kana)
+		if !ok {
+			continue
+		}
+		words = append(words, Word{Kana: kana, Romaji: romaji, Meaning: strings.TrimSpace(meaning)})
+	}
+	return words
+}
+
+// Romanize converts a string of kana from the set into romaji.
+// Returns false if any character is not in the set.
+func (cs CharacterSet) Romanize(kana string) (string, bool) {
+	var b strings.Builder
+	for _, r := range kana {
+		romaji, ok := cs.Data[string(r)]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(romaji)
+	}
+	return b.String(), true
+}
+
+// Characters splits the word into its individual kana.
+func (w Word) Characters() []string {
+	return SplitKana(w.Kana)
+}
+
+// SplitKana splits a tile's text into individual kana characters.
+func SplitKana(text string) []string {
+	chars := make([]string, 0, len(text)/3)
+	for _, r := range text {
+		chars = append(chars, string(r))
+	}
+	return chars
+}
+
+// FilterWords returns the words made only of characters in allowed.
+func FilterWords(words []Word, allowed []string) []Word {
+	set := make(map[string]bool, len(allowed))
+	for _, c := range allowed {
+		set[c] = true
+	}
+	filtered := make([]Word, 0, len(words))
+	for _, w := range words {
+		ok := true
+		for _, c := range w.Characters() {
+			if !set[c] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			filtered = append(filtered, w)
+		}
+	}
+	return filtered
+}
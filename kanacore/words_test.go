@@ -0,0 +1,48 @@
+package kanacore
+
+import "testing"
+
+func TestN5WordsAreBasicHiragana(t *testing.T) {
+	words := N5Words()
+	if len(words) < 100 {
+		t.Fatalf("expected at least 100 embedded words, got %d", len(words))
+	}
+	cs := Hiragana()
+	for _, w := range words {
+		for _, c := range w.Characters() {
+			if _, ok := cs.GetRomaji(c); !ok {
+				t.Errorf("word %s contains non-basic kana %s", w.Kana, c)
+			}
+		}
+		if w.Meaning == "" {
+			t.Errorf("word %s has no meaning", w.Kana)
+		}
+	}
+}
+
+func TestRomanize(t *testing.T) {
+	tests := map[string]string{
+		"ねこ":   "neko",
+		"さかな":  "sakana",
+		"ちかてつ": "chikatetsu",
+		"ほん":   "hon",
+	}
+	cs := Hiragana()
+	for kana, want := range tests {
+		got, ok := cs.Romanize(kana)
+		if !ok || got != want {
+			t.Errorf("Romanize(%s) = %q, %v; want %q", kana, got, ok, want)
+		}
+	}
+	if _, ok := cs.Romanize("ねこカ"); ok {
+		t.Error("expected katakana to be rejected")
+	}
+}
+
+func TestFilterWords(t *testing.T) {
+	words := []Word{{Kana: "ねこ"}, {Kana: "いえ"}, {Kana: "うえ"}, {Kana: "さかな"}}
+	got := FilterWords(words, []string{"あ", "い", "う", "え", "お"})
+	if len(got) != 2 || got[0].Kana != "いえ" || got[1].Kana != "うえ" {
+		t.Errorf("expected vowel-only words, got %+v", got)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"kana/kanacore"
+	"kana/netplay"
+)
+
+// LANModel races players on other machines over the local network. It waits
+// in the lobby until the host starts the race, then plays the host's match on
+// the local player's own field beside everyone's standings, and ends on the
+// results table. The local player's session counts towards their statistics
+// like any other.
+type LANModel struct {
+	Game     *Model
+	Name     string
+	Peer     netplay.Peer
+	Host     *netplay.Host // set when this instance hosts the race
+	Players  []netplay.PlayerState
+	Started  bool
+	Results  []kanacore.PlayerResult // set once every player is done
+	Verdict  string
+	Status   string // why the lobby could not start, or why the race was cut short
+	Width    int
+	Height   int
+	reported kanacore.PlayerResult // progress last shared with the others
+	finished bool                  // the final result was shared
+}
+
+// raceMsg delivers a race event to the update loop.
+type raceMsg netplay.Event
+
+// NewLANModel sets up the lobby of a race. game holds the local player's
+// settings; a host's also sets the rules everyone plays.
+func NewLANModel(game Model, name string, peer netplay.Peer, host *netplay.Host) LANModel {
+	l := LANModel{Game: &game, Name: name, Peer: peer, Host: host, Width: 80, Height: 24}
+	if host != nil {
+		l.Players = host.Players()
+	}
+	return l
+}
+
+// waitForRace waits for the race's next event. Nothing arrives once a
+// joined race's events end.
+func waitForRace(peer netplay.Peer) tea.Cmd {
+	return func() tea.Msg {
+		e, ok := <-peer.Events()
+		if !ok {
+			return nil
+		}
+		return raceMsg(e)
+	}
+}
+
+// raceMatch returns the match a hosted race plays: the host's mode, rules
+// and kana with a fresh seed. Modes others cannot share play as classic.
+func raceMatch(m Model) netplay.Match {
+	if !netplay.Raceable(m.Mode) {
+		m.Mode = kanacore.DefaultGameMode
+	}
+	return netplay.Match{
+		Mode:       m.Mode,
+		Seed:       kanacore.SessionSeed(m.Mode, time.Now()),
+		TimeLimit:  int(m.TimeLimit / time.Second),
+		ScoreLimit: m.ScoreLimit,
+		ZenGoal:    m.ZenGoal.String(),
+		Chars:      m.availableCharacters(),
+	}
+}
+
+// playMatch sets the model up to play a race's match from start: the host's
+// rules, kana and seed over the player's own statistics. None of it is saved
+// as the player's settings, and auto-progression and ghosts sit the race out
+// so every player keeps getting the same tiles.
+func (m *Model) playMatch(match netplay.Match, start time.Time) {
+	m.AutoProgress = false
+	m.GhostRace = false
+	m.Ghost = nil
+	m.applySelectedChars(match.Chars)
+	if match.Mode.Production() != m.Mode.Production() {
+		m.mergeSessionStats()
+		m.Mode = match.Mode
+		m.loadOverallStats()
+	}
+	m.Mode = match.Mode
+	m.TimeLimit = kanacore.NormalizeTimeLimit(time.Duration(match.TimeLimit) * time.Second)
+	m.ScoreLimit = match.ScoreLimit
+	m.ZenGoal = kanacore.ParseGoal(match.ZenGoal)
+	m.SessionStart = start
+	m.Seed = match.Seed
+	m.Rand = kanacore.NewRand(m.Seed)
+	m.Recorder = kanacore.NewRecorder(m.Mode, m.Seed, m.SessionStart)
+	m.refreshChoices()
+	m.Practice.Start(start)
+}
+
+// raceResult returns the player's progress to share with the others.
+func (m *Model) raceResult() kanacore.PlayerResult {
+	b := m.Scoring.Breakdown
+	return kanacore.PlayerResult{
+		Score:    m.Score,
+		Hits:     b.Hits,
+		Wrong:    b.Wrong,
+		Missed:   m.Missed,
+		MaxCombo: b.MaxCombo,
+	}
+}
+
+func (l LANModel) Init() tea.Cmd {
+	return waitForRace(l.Peer)
+}
+
+func (l LANModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		l.Width, l.Height = msg.Width, msg.Height
+		l.Game.Width = msg.Width
+		l.Game.Height = msg.Height - 3 // Reserve space for status bar and instructions
+		l.Game.GameWidth = msg.Width / 3
+
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "ctrl+c":
+			l.Game.mergeSessionStats()
+			return l, tea.Quit
+		case l.Results != nil || !l.Started:
+			return l.updateLobby(msg)
+		case l.Game.GameOver:
+			if msg.String() == "esc" {
+				return l, tea.Quit
+			}
+			return l, nil
+		}
+		switch msg.String() {
+		case "esc":
+			l.Game.endGame("quit")
+		case "enter":
+			l.Game.checkAnswer()
+			l.Game.Input = ""
+		default:
+			l.Game.typeKey(msg)
+		}
+		l.Game.Recorder.Input(l.Game.now(), l.Game.Input)
+		return l, l.report()
+
+	case tickMsg:
+		if !l.Started || l.Game.GameOver {
+			return l, nil
+		}
+		l.Game.update()
+		return l, tea.Batch(l.report(), tickCmd(0))
+
+	case spawnMsg:
+		if !l.Started || l.Game.GameOver {
+			return l, nil
+		}
+		l.Game.spawnKana()
+		return l, spawnCmd(l.Game.Mode.SpawnInterval(), 0)
+
+	case raceMsg:
+		return l.updateRace(netplay.Event(msg))
+	}
+	return l, nil
+}
+
+// updateLobby handles keys in the lobby and on the results table.
+func (l LANModel) updateLobby(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return l, tea.Quit
+	case "enter":
+		if l.Results != nil {
+			return l, tea.Quit
+		}
+		if l.Host != nil {
+			if err := l.Host.Start(raceMatch(*l.Game)); err != nil {
+				l.Status = err.Error()
+			}
+		}
+	}
+	return l, nil
+}
+
+// updateRace follows the race: the lobby filling, the start, everyone's
+// standings and the results.
+func (l LANModel) updateRace(e netplay.Event) (tea.Model, tea.Cmd) {
+	if e.Players != nil {
+		l.Players = e.Players
+	}
+	switch e.Kind {
+	case netplay.EventStart:
+		l.Started = true
+		l.Status = ""
+		l.Game.playMatch(e.Match, time.Now())
+		return l, tea.Batch(waitForRace(l.Peer), tickCmd(0), spawnCmd(l.Game.Mode.SpawnInterval(), 0))
+	case netplay.EventResults:
+		l.Results = netplay.Results(e.Players)
+		l.Verdict = kanacore.MatchVerdict(l.Results)
+		return l, nil
+	case netplay.EventClosed:
+		l.Status = e.Err.Error()
+		return l, nil
+	}
+	return l, waitForRace(l.Peer)
+}
+
+// report shares the player's progress when it changed, and their final
+// result once they are done. Sharing blocks until it is sent, so it runs
+// outside the update loop.
+func (l *LANModel) report() tea.Cmd {
+	if l.finished {
+		return nil
+	}
+	r := l.Game.raceResult()
+	peer := l.Peer
+	if l.Game.GameOver {
+		l.finished = true
+		return func() tea.Msg {
+			peer.Finish(r)
+			return nil
+		}
+	}
+	if r == l.reported {
+		return nil
+	}
+	l.reported = r
+	return func() tea.Msg {
+		peer.Report(r)
+		return nil
+	}
+}
+
+func (l LANModel) View() string {
+	switch {
+	case l.Results != nil:
+		return renderLANResults(l)
+	case !l.Started:
+		return renderLANLobby(l)
+	case l.Game.GameOver:
+		lines := []string{"You're done! Waiting for the others to finish…", ""}
+		lines = append(lines, renderStandings(l.Players, l.Name)...)
+		if l.Status != "" {
+			lines = append(lines, "", l.Status)
+		}
+		box := gameOverStyle.Render(strings.Join(lines, "\n"))
+		return lipgloss.Place(l.Width, l.Height, lipgloss.Center, lipgloss.Center, box)
+	}
+	standings := renderStandings(l.Players, l.Name)
+	if l.Status != "" {
+		standings = append(standings, "", l.Status)
+	}
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		renderGameArea(*l.Game), renderVerticalBorder(l.Game.Height), " "+strings.Join(standings, "\n "))
+	return lipgloss.JoinVertical(lipgloss.Left, top, renderStatus(*l.Game))
+}
+
+// renderLANLobby draws the lobby: who has joined and how to join or start.
+func renderLANLobby(l LANModel) string {
+	lines := []string{tableHeaderStyle.Render("LAN RACE LOBBY"), ""}
+	if l.Host != nil {
+		_, port, _ := net.SplitHostPort(l.Host.Addr().String())
+		if joins := netplay.JoinAddresses(port); len(joins) > 0 {
+			lines = append(lines, "Others join at "+strings.Join(joins, " or "), "")
+		}
+	}
+	for _, line := range netplay.LobbyLines(l.Players, l.Name) {
+		lines = append(lines, "  "+line)
+	}
+	lines = append(lines, "")
+	if l.Status != "" {
+		lines = append(lines, l.Status, "")
+	}
+	if l.Host != nil {
+		lines = append(lines, "Press ENTER to start the race, ESC to leave")
+	} else {
+		lines = append(lines, "Waiting for the host to start the race… ESC to leave")
+	}
+	box := gameOverStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(l.Width, l.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderStandings lists every player's progress, marking the local player.
+func renderStandings(players []netplay.PlayerState, self string) []string {
+	lines := []string{tableHeaderStyle.Render("STANDINGS"), ""}
+	return append(lines, netplay.StandingLines(players, self)...)
+}
+
+// renderLANResults draws the race's results table.
+func renderLANResults(l LANModel) string {
+	lines := []string{tableHeaderStyle.Render("RACE RESULTS"), "", l.Verdict, ""}
+	lines = append(lines, renderResultsTable(l.Results)...)
+	lines = append(lines, "", "Press ENTER or ESC to exit")
+	box := gameOverStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(l.Width, l.Height, lipgloss.Center, lipgloss.Center, box)
+}
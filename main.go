@@ -3,18 +3,16 @@ package main
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 	"os"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
+	"kana/kanacore"
+	"kana/netplay"
 	"kana/store"
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
-
 	st, err := store.Open("kana.db")
 	if err != nil {
 		fmt.Printf("Error opening store: %v\n", err)
@@ -22,7 +20,31 @@ func main() {
 	}
 	defer st.Close()
 
-	rows, autoProgress, scoreLimit, err := setupSettingsForm(st)
+	curricula, err := kanacore.LoadCurricula(st.CurriculumDir())
+	if err != nil {
+		fmt.Printf("Warning: skipped curricula that could not be loaded:\n%v\n", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(st, os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if needsPlacement(st) {
+		if err := runPlacementTest(st); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				fmt.Println("Setup cancelled. Goodbye!")
+				return
+			}
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	settings, err := setupSettingsForm(st, curricula)
 	if err != nil {
 		if errors.Is(err, huh.ErrUserAborted) {
 			fmt.Println("Setup cancelled. Goodbye!")
@@ -32,14 +54,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	model := InitialModel(st)
-	if len(rows) > 0 {
-		model.SetSelectedRows(rows)
+	model := InitialModel(st, curricula)
+	if len(settings.Rows) > 0 {
+		model.SetSelectedRows(settings.Rows)
 	}
-	model.SetAutoProgress(autoProgress)
-	model.SetScoreLimit(scoreLimit)
+	model.SetSelectedChars(settings.Chars)
+	if settings.SaveSetAs != "" {
+		if err := st.SaveCustomSet(settings.SaveSetAs, settings.Chars); err != nil {
+			fmt.Printf("Error saving custom set: %v\n", err)
+		}
+	}
+	model.SetAutoProgress(settings.AutoProgress)
+	model.SetCurriculum(settings.Curriculum)
+	model.SetScoreLimit(settings.ScoreLimit)
+	model.SetTargetPolicy(settings.TargetPolicy)
+	model.SetMasteryPolicy(settings.Mastery)
+	// SetGameMode sets up the first session and picks its ghost, so ghost
+	// racing is set before it.
+	model.SetGhostRace(settings.GhostRace)
+	model.SetGameMode(settings.Mode, settings.TimeLimit)
+	model.SetZenGoal(settings.ZenGoal)
+	model.SetDailyGoal(settings.DailyGoal)
+	model.SetLookalikeGroup(settings.Lookalike)
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	var program tea.Model = model
+	switch {
+	case len(settings.Players) == 2:
+		program = NewVersusModel(st, [2]string{settings.Players[0], settings.Players[1]}, settings)
+	case settings.LAN != "":
+		peer, host, err := netplay.Open(settings.LAN == lanHost, settings.LANAddr, settings.LANName)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer peer.Close()
+		program = NewLANModel(model, settings.LANName, peer, host)
+	}
+	p := tea.NewProgram(program, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,142 @@
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"kana/kanacore"
+)
+
+// errHostClosed is reported when the connection to the host drops before the
+// results.
+var errHostClosed = errors.New("the host closed the race")
+
+// Client is a player who joined a race hosted elsewhere.
+type Client struct {
+	conn   *conn
+	name   string
+	events chan Event
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Join joins the race hosted at addr as name.
+func Join(addr, name string) (*Client, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("netplay: enter a player name")
+	}
+	nc, err := net.DialTimeout("tcp", addr, handshakeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("netplay: join %s: %w", addr, err)
+	}
+	c := newConn(nc)
+	if err := c.send(message{Type: msgHello, Version: ProtocolVersion, Name: name}); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: %w", addr, err)
+	}
+	if err := nc.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: %w", addr, err)
+	}
+	reply, err := c.receive()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: %w", addr, err)
+	}
+	switch {
+	case reply.Type == msgReject:
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: %s", addr, reply.Reason)
+	case reply.Type != msgWelcome:
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: expected a welcome, got %q", addr, reply.Type)
+	}
+	if err := nc.SetReadDeadline(time.Time{}); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("netplay: join %s: %w", addr, err)
+	}
+
+	cl := &Client{
+		conn:   c,
+		name:   reply.Name,
+		events: make(chan Event, 16),
+		closed: make(chan struct{}),
+	}
+	go cl.read()
+	return cl, nil
+}
+
+// Name returns the name the client joined as.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Events delivers what happens in the race. It is closed once the results
+// are in or the connection drops.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Report shares the player's progress with the host.
+func (c *Client) Report(r kanacore.PlayerResult) {
+	state := stateOf(c.name, r, false)
+	_ = c.conn.send(message{Type: msgProgress, Player: &state})
+}
+
+// Finish shares the player's final result with the host.
+func (c *Client) Finish(r kanacore.PlayerResult) {
+	state := stateOf(c.name, r, true)
+	_ = c.conn.send(message{Type: msgFinish, Player: &state})
+}
+
+// Close leaves the race.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.conn.c.Close()
+	})
+	return err
+}
+
+// read turns the host's messages into events until the results arrive or
+// the connection drops.
+func (c *Client) read() {
+	defer close(c.events)
+	for {
+		m, err := c.conn.receive()
+		if err != nil {
+			select {
+			case c.events <- Event{Kind: EventClosed, Err: errHostClosed}:
+			case <-c.closed:
+			}
+			return
+		}
+		e := Event{Kind: EventKind(m.Type), Players: m.Players}
+		switch e.Kind {
+		case EventStart:
+			if m.Match == nil {
+				continue
+			}
+			e.Match = *m.Match
+		case EventLobby, EventStandings, EventResults:
+		default:
+			continue
+		}
+		select {
+		case c.events <- e:
+		case <-c.closed:
+			return
+		}
+		if e.Kind == EventResults {
+			c.Close()
+			return
+		}
+	}
+}
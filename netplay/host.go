@@ -0,0 +1,306 @@
+package netplay
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"kana/kanacore"
+)
+
+// Host runs a race: it keeps the lobby, plays as the first player and relays
+// everyone's progress.
+type Host struct {
+	ln     net.Listener
+	events chan Event
+	done   chan struct{}
+
+	mu       sync.Mutex
+	players  []PlayerState // the host first, then clients in joining order
+	conns    map[string]*conn
+	started  bool
+	finished bool
+
+	// publishMu keeps messages in the order the state changed.
+	publishMu sync.Mutex
+	closeOnce sync.Once
+}
+
+// Listen hosts a race on addr with name as the host's player name. Players
+// can join until Start is called.
+func Listen(addr, name string) (*Host, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("netplay: enter a player name")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("netplay: listen on %s: %w", addr, err)
+	}
+	h := &Host{
+		ln:      ln,
+		events:  make(chan Event, 16),
+		done:    make(chan struct{}),
+		players: []PlayerState{{Name: name}},
+		conns:   make(map[string]*conn),
+	}
+	go h.accept()
+	return h, nil
+}
+
+// Addr returns the address the host listens on.
+func (h *Host) Addr() net.Addr {
+	return h.ln.Addr()
+}
+
+// Events delivers what happens in the race.
+func (h *Host) Events() <-chan Event {
+	return h.events
+}
+
+// Players returns everyone in the race, the host first.
+func (h *Host) Players() []PlayerState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]PlayerState(nil), h.players...)
+}
+
+// Start closes the lobby and sends everyone match. It needs at least one
+// player besides the host.
+func (h *Host) Start(match Match) error {
+	h.mu.Lock()
+	if h.started {
+		h.mu.Unlock()
+		return fmt.Errorf("netplay: the race has already started")
+	}
+	if len(h.players) < 2 {
+		h.mu.Unlock()
+		return fmt.Errorf("netplay: wait for someone to join")
+	}
+	h.started = true
+	h.mu.Unlock()
+	h.publish(EventStart, &match)
+	return nil
+}
+
+// Report shares the host's progress with everyone.
+func (h *Host) Report(r kanacore.PlayerResult) {
+	h.update(h.hostName(), r, false)
+}
+
+// Finish shares the host's final result with everyone.
+func (h *Host) Finish(r kanacore.PlayerResult) {
+	h.update(h.hostName(), r, true)
+}
+
+// Close stops the race and disconnects everyone.
+func (h *Host) Close() error {
+	var err error
+	h.closeOnce.Do(func() {
+		close(h.done)
+		err = h.ln.Close()
+		h.mu.Lock()
+		for _, c := range h.conns {
+			c.c.Close()
+		}
+		h.mu.Unlock()
+	})
+	return err
+}
+
+func (h *Host) hostName() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.players[0].Name
+}
+
+func (h *Host) accept() {
+	for {
+		c, err := h.ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.serve(newConn(c))
+	}
+}
+
+// serve admits a client and follows its progress until it disconnects.
+func (h *Host) serve(c *conn) {
+	defer c.c.Close()
+	name, err := h.admit(c)
+	if err != nil {
+		_ = c.send(message{Type: msgReject, Reason: err.Error()})
+		return
+	}
+	h.publish(EventLobby, nil)
+
+	for {
+		m, err := c.receive()
+		if err != nil {
+			h.leave(name)
+			return
+		}
+		if m.Player == nil {
+			continue
+		}
+		switch m.Type {
+		case msgProgress:
+			h.update(name, m.Player.Result(), false)
+		case msgFinish:
+			h.update(name, m.Player.Result(), true)
+		}
+	}
+}
+
+// admit reads a client's hello and adds it to the lobby, or says why it
+// cannot join.
+func (h *Host) admit(c *conn) (string, error) {
+	if err := c.c.SetReadDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return "", err
+	}
+	hello, err := c.receive()
+	if err != nil {
+		return "", fmt.Errorf("expected a hello")
+	}
+	if err := c.c.SetReadDeadline(time.Time{}); err != nil {
+		return "", err
+	}
+	if hello.Type != msgHello {
+		return "", fmt.Errorf("expected a hello, got %q", hello.Type)
+	}
+	if hello.Version != ProtocolVersion {
+		return "", fmt.Errorf("the host speaks protocol version %d, not %d", ProtocolVersion, hello.Version)
+	}
+	name := strings.TrimSpace(hello.Name)
+	if name == "" {
+		return "", fmt.Errorf("enter a player name")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.started:
+		return "", fmt.Errorf("the race has already started")
+	case len(h.players) >= MaxPlayers:
+		return "", fmt.Errorf("the race is full")
+	}
+	for _, p := range h.players {
+		if p.Name == name {
+			return "", fmt.Errorf("someone called %s has already joined", name)
+		}
+	}
+	// Welcome before the player shows up in any lobby message, so the client
+	// reads its welcome first.
+	if err := c.send(message{Type: msgWelcome, Version: ProtocolVersion, Name: name}); err != nil {
+		return "", err
+	}
+	h.players = append(h.players, PlayerState{Name: name})
+	h.conns[name] = c
+	return name, nil
+}
+
+// leave drops a disconnected client: from the lobby before the race, or as
+// done once it has started.
+func (h *Host) leave(name string) {
+	h.mu.Lock()
+	delete(h.conns, name)
+	if !h.started {
+		for i, p := range h.players {
+			if p.Name == name {
+				h.players = append(h.players[:i], h.players[i+1:]...)
+				break
+			}
+		}
+		h.mu.Unlock()
+		h.publish(EventLobby, nil)
+		return
+	}
+	for i, p := range h.players {
+		if p.Name == name && !p.Done {
+			h.players[i].Done = true
+			h.players[i].Left = true
+		}
+	}
+	h.mu.Unlock()
+	h.publishStandings()
+}
+
+// update records a player's progress and shares it, then the results once
+// everyone is done.
+func (h *Host) update(name string, r kanacore.PlayerResult, done bool) {
+	h.mu.Lock()
+	if !h.started || h.finished {
+		h.mu.Unlock()
+		return
+	}
+	for i, p := range h.players {
+		if p.Name == name && !p.Done {
+			h.players[i] = stateOf(name, r, done)
+		}
+	}
+	h.mu.Unlock()
+	h.publishStandings()
+}
+
+// publishStandings shares everyone's progress, then the results if that was
+// the last player finishing.
+func (h *Host) publishStandings() {
+	h.publishMu.Lock()
+	defer h.publishMu.Unlock()
+
+	h.mu.Lock()
+	if h.finished {
+		// The results already went out with everyone's final state.
+		h.mu.Unlock()
+		return
+	}
+	last := true
+	for _, p := range h.players {
+		last = last && p.Done
+	}
+	h.finished = last
+	h.mu.Unlock()
+
+	h.broadcast(EventStandings, nil)
+	if last {
+		h.broadcast(EventResults, nil)
+	}
+}
+
+// publish shares the players' current state as kind.
+func (h *Host) publish(kind EventKind, match *Match) {
+	h.publishMu.Lock()
+	defer h.publishMu.Unlock()
+	h.broadcast(kind, match)
+}
+
+// broadcast sends every client the players' current state as kind, and
+// delivers the same to the host's own Events. Must be called with publishMu
+// held.
+func (h *Host) broadcast(kind EventKind, match *Match) {
+	h.mu.Lock()
+	players := append([]PlayerState(nil), h.players...)
+	conns := make([]*conn, 0, len(h.conns))
+	for _, c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	m := message{Type: string(kind), Match: match, Players: players}
+	for _, c := range conns {
+		// A client that cannot be reached is dropped by its reader.
+		if err := c.send(m); err != nil {
+			c.c.Close()
+		}
+	}
+	e := Event{Kind: kind, Players: players}
+	if match != nil {
+		e.Match = *match
+	}
+	select {
+	case h.events <- e:
+	case <-h.done:
+	}
+}
@@ -0,0 +1,273 @@
+// Package netplay races kana sessions between instances on a local network.
+//
+// One instance hosts a lobby on a TCP port and the others join it by
+// address. When the host starts the race every player is dealt the same
+// Match, so all sessions drop the same seeded tiles, and players report their
+// progress as they play. The host relays everyone's standings and, once every
+// player is done, the results.
+//
+// Messages are JSON objects, one per line. A joining client opens with a
+// hello naming the protocol version it speaks; the host answers with a
+// welcome or a reject.
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"kana/kanacore"
+)
+
+// ProtocolVersion is the version of the message protocol. Hosts reject
+// clients speaking another version.
+const ProtocolVersion = 1
+
+// DefaultPort is the TCP port races are hosted on unless another is given.
+const DefaultPort = "7777"
+
+// MaxPlayers caps the players in one race, the host included.
+const MaxPlayers = 8
+
+// handshakeTimeout bounds how long either side waits for the other's
+// hello, welcome or reject.
+const handshakeTimeout = 5 * time.Second
+
+// writeTimeout bounds how long sending one message may block.
+const writeTimeout = 5 * time.Second
+
+// Message types.
+const (
+	msgHello     = "hello"     // client → host: join, with Version and Name
+	msgWelcome   = "welcome"   // host → client: joined
+	msgReject    = "reject"    // host → client: not joined, with Reason
+	msgLobby     = "lobby"     // host → clients: players waiting in the lobby
+	msgStart     = "start"     // host → clients: the race starts with Match
+	msgProgress  = "progress"  // client → host: the sender's Player state
+	msgFinish    = "finish"    // client → host: the sender is done, with final Player state
+	msgStandings = "standings" // host → clients: every player's state
+	msgResults   = "results"   // host → clients: every player is done
+)
+
+// message is one line of the protocol. Which fields are set depends on Type.
+type message struct {
+	Type    string        `json:"type"`
+	Version int           `json:"version,omitempty"`
+	Name    string        `json:"name,omitempty"`
+	Reason  string        `json:"reason,omitempty"`
+	Match   *Match        `json:"match,omitempty"`
+	Player  *PlayerState  `json:"player,omitempty"`
+	Players []PlayerState `json:"players,omitempty"`
+}
+
+// Match is the session every player of a race plays.
+type Match struct {
+	Mode       kanacore.GameMode `json:"mode"`
+	Seed       int64             `json:"seed"`
+	TimeLimit  int               `json:"time_limit"` // countdown in seconds, in timed modes
+	ScoreLimit int               `json:"score_limit"`
+	ZenGoal    string            `json:"zen_goal,omitempty"`
+	Chars      []string          `json:"chars"` // kana the tiles are drawn from
+}
+
+// Raceable reports whether players on different machines can race mode.
+// Look-alike drills depend on each player's confusion history, so they
+// cannot deal everyone the same tiles.
+func Raceable(mode kanacore.GameMode) bool {
+	return mode.SplitScreen() && mode != kanacore.ModeLookalike
+}
+
+// PlayerState is one player's progress in a race.
+type PlayerState struct {
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	Hits     int    `json:"hits"`
+	Wrong    int    `json:"wrong"`
+	Missed   int    `json:"missed"`
+	MaxCombo int    `json:"max_combo"`
+	Done     bool   `json:"done"`
+	Left     bool   `json:"left,omitempty"` // disconnected before finishing
+}
+
+// Result returns the player's outcome for comparing with the others.
+func (p PlayerState) Result() kanacore.PlayerResult {
+	return kanacore.PlayerResult{
+		Name:     p.Name,
+		Score:    p.Score,
+		Hits:     p.Hits,
+		Wrong:    p.Wrong,
+		Missed:   p.Missed,
+		MaxCombo: p.MaxCombo,
+	}
+}
+
+// Results returns the outcomes of players.
+func Results(players []PlayerState) []kanacore.PlayerResult {
+	results := make([]kanacore.PlayerResult, len(players))
+	for i, p := range players {
+		results[i] = p.Result()
+	}
+	return results
+}
+
+// stateOf fills a player's state from a result.
+func stateOf(name string, r kanacore.PlayerResult, done bool) PlayerState {
+	return PlayerState{
+		Name:     name,
+		Score:    r.Score,
+		Hits:     r.Hits,
+		Wrong:    r.Wrong,
+		Missed:   r.Missed,
+		MaxCombo: r.MaxCombo,
+		Done:     done,
+	}
+}
+
+// EventKind is what an Event reports.
+type EventKind string
+
+const (
+	// EventLobby is the players waiting in the lobby changing.
+	EventLobby EventKind = "lobby"
+	// EventStart is the race starting; Match is set.
+	EventStart EventKind = "start"
+	// EventStandings is a player's progress changing.
+	EventStandings EventKind = "standings"
+	// EventResults is every player being done.
+	EventResults EventKind = "results"
+	// EventClosed is the connection to the host closing before the results;
+	// Err says why.
+	EventClosed EventKind = "closed"
+)
+
+// Event is something happening in a race, delivered on a Peer's Events
+// channel.
+type Event struct {
+	Kind    EventKind
+	Players []PlayerState // the host first, then the others in joining order
+	Match   Match
+	Err     error
+}
+
+// Peer is this instance's end of a race, whether it hosts or joined.
+type Peer interface {
+	// Events delivers what happens in the race.
+	Events() <-chan Event
+	// Report shares the local player's progress.
+	Report(r kanacore.PlayerResult)
+	// Finish shares the local player's final result.
+	Finish(r kanacore.PlayerResult)
+	// Close leaves the race.
+	Close() error
+}
+
+// Open hosts a race on addr when hosting and joins the race hosted at addr
+// otherwise, playing as name. A host is also returned as the Host, which
+// starts the race.
+func Open(hosting bool, addr, name string) (Peer, *Host, error) {
+	if hosting {
+		host, err := Listen(addr, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return host, host, nil
+	}
+	client, err := Join(addr, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, nil, nil
+}
+
+// conn sends and receives messages on one connection.
+type conn struct {
+	c   net.Conn
+	dec *json.Decoder
+	mu  sync.Mutex // serialises writes
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{c: c, dec: json.NewDecoder(bufio.NewReader(c))}
+}
+
+func (c *conn) send(m message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.c.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	return json.NewEncoder(c.c).Encode(m)
+}
+
+func (c *conn) receive() (message, error) {
+	var m message
+	err := c.dec.Decode(&m)
+	return m, err
+}
+
+// JoinAddresses lists the addresses other machines can join a race hosted
+// on port at, one per network interface of this machine.
+func JoinAddresses(port string) []string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var joins []string
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() || ipnet.IP.To4() == nil {
+			continue
+		}
+		joins = append(joins, net.JoinHostPort(ipnet.IP.String(), port))
+	}
+	return joins
+}
+
+// WithDefaultPort adds DefaultPort to an address without a port.
+func WithDefaultPort(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, DefaultPort)
+	}
+	return addr
+}
+
+// LobbyLines lists the players waiting in the lobby, marking the host and
+// the local player self.
+func LobbyLines(players []PlayerState, self string) []string {
+	lines := make([]string, len(players))
+	for i, p := range players {
+		lines[i] = p.Name
+		if i == 0 {
+			lines[i] += " (host)"
+		}
+		if p.Name == self {
+			lines[i] += " ← you"
+		}
+	}
+	return lines
+}
+
+// StandingLines lists every player's progress, marking the local player
+// self and the players who are done or left.
+func StandingLines(players []PlayerState, self string) []string {
+	lines := make([]string, len(players))
+	for i, p := range players {
+		mark := "  "
+		if p.Name == self {
+			mark = "▶ "
+		}
+		lines[i] = fmt.Sprintf("%s%-12s %5d  missed %d", mark, p.Name, p.Score, p.Missed)
+		switch {
+		case p.Left:
+			lines[i] += "  left"
+		case p.Done:
+			lines[i] += "  done"
+		}
+	}
+	return lines
+}
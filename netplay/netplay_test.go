@@ -0,0 +1,185 @@
+package netplay
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"kana/kanacore"
+)
+
+// next waits for the next event of kind, skipping the others.
+func next(t *testing.T, events <-chan Event, kind EventKind) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed waiting for %s", kind)
+			}
+			if e.Kind == kind {
+				return e
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", kind)
+		}
+	}
+}
+
+// lobbyOf waits until the lobby holds n players.
+func lobbyOf(t *testing.T, events <-chan Event, n int) Event {
+	t.Helper()
+	for {
+		if e := next(t, events, EventLobby); len(e.Players) == n {
+			return e
+		}
+	}
+}
+
+func TestRaceOnLocalhost(t *testing.T) {
+	host, err := Listen("127.0.0.1:0", "Aki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+	addr := host.Addr().String()
+
+	if err := host.Start(Match{}); err == nil {
+		t.Fatal("expected the race not to start without anyone joining")
+	}
+
+	ren, err := Join(addr, "Ren")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ren.Close()
+	yui, err := Join(addr, "Yui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer yui.Close()
+	if _, err := Join(addr, "Ren"); err == nil || !strings.Contains(err.Error(), "already joined") {
+		t.Fatalf("expected a second Ren to be rejected, got %v", err)
+	}
+
+	lobby := lobbyOf(t, host.Events(), 3)
+	if lobby.Players[0].Name != "Aki" {
+		t.Fatalf("expected the host first in the lobby, got %+v", lobby.Players)
+	}
+	lobbyOf(t, yui.Events(), 3)
+
+	match := Match{Mode: kanacore.ModeTimeAttack, Seed: 42, TimeLimit: 60, Chars: []string{"あ", "い"}}
+	if err := host.Start(match); err != nil {
+		t.Fatal(err)
+	}
+	for _, events := range []<-chan Event{host.Events(), ren.Events(), yui.Events()} {
+		got := next(t, events, EventStart).Match
+		if got.Seed != 42 || got.Mode != kanacore.ModeTimeAttack || got.TimeLimit != 60 || len(got.Chars) != 2 {
+			t.Fatalf("expected every player to get the host's match, got %+v", got)
+		}
+	}
+	if _, err := Join(addr, "Late"); err == nil || !strings.Contains(err.Error(), "already started") {
+		t.Fatalf("expected joining after the start to be rejected, got %v", err)
+	}
+
+	ren.Report(kanacore.PlayerResult{Score: 30, Hits: 3})
+	for {
+		e := next(t, yui.Events(), EventStandings)
+		if e.Players[1].Score == 30 {
+			break
+		}
+	}
+
+	host.Finish(kanacore.PlayerResult{Score: 50, Hits: 5, Missed: 1})
+	ren.Finish(kanacore.PlayerResult{Score: 80, Hits: 8, MaxCombo: 6})
+	yui.Finish(kanacore.PlayerResult{Score: 20, Hits: 2, Missed: 3})
+
+	for _, events := range []<-chan Event{host.Events(), ren.Events(), yui.Events()} {
+		results := Results(next(t, events, EventResults).Players)
+		if len(results) != 3 {
+			t.Fatalf("expected results for 3 players, got %+v", results)
+		}
+		if got := kanacore.MatchVerdict(results); got != "Ren wins by 30 points!" {
+			t.Fatalf("unexpected verdict %q from %+v", got, results)
+		}
+		if results[1].MaxCombo != 6 {
+			t.Fatalf("expected Ren's combo in the results, got %+v", results[1])
+		}
+	}
+}
+
+func TestHostRejectsOtherProtocolVersions(t *testing.T) {
+	host, err := Listen("127.0.0.1:0", "Aki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+
+	nc, err := net.Dial("tcp", host.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	if err := json.NewEncoder(nc).Encode(message{Type: msgHello, Version: ProtocolVersion + 1, Name: "Ren"}); err != nil {
+		t.Fatal(err)
+	}
+	var reply message
+	if err := json.NewDecoder(nc).Decode(&reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Type != msgReject || !strings.Contains(reply.Reason, "protocol version") {
+		t.Fatalf("expected a version reject, got %+v", reply)
+	}
+}
+
+func TestPlayerLeavingMidRaceCountsAsDone(t *testing.T) {
+	host, err := Listen("127.0.0.1:0", "Aki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+	ren, err := Join(host.Addr().String(), "Ren")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lobbyOf(t, host.Events(), 2)
+	if err := host.Start(Match{Mode: kanacore.ModeClassic, Seed: 1}); err != nil {
+		t.Fatal(err)
+	}
+	next(t, ren.Events(), EventStart)
+	ren.Close()
+
+	host.Finish(kanacore.PlayerResult{Score: 10, Hits: 1})
+	e := next(t, host.Events(), EventResults)
+	if !e.Players[1].Left || !e.Players[1].Done {
+		t.Fatalf("expected Ren to be marked as having left, got %+v", e.Players[1])
+	}
+}
+
+func TestWithDefaultPort(t *testing.T) {
+	for addr, want := range map[string]string{
+		"192.168.1.10":        "192.168.1.10:" + DefaultPort,
+		" 192.168.1.10:9000 ": "192.168.1.10:9000",
+		":8000":               ":8000",
+	} {
+		if got := WithDefaultPort(addr); got != want {
+			t.Errorf("WithDefaultPort(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestLinesMarkTheLocalPlayer(t *testing.T) {
+	players := []PlayerState{{Name: "Aki", Score: 120}, {Name: "Ren", Missed: 2, Done: true}, {Name: "Mio", Left: true}}
+
+	lobby := LobbyLines(players, "Ren")
+	if lobby[0] != "Aki (host)" || lobby[1] != "Ren ← you" || lobby[2] != "Mio" {
+		t.Errorf("unexpected lobby %q", lobby)
+	}
+	standings := StandingLines(players, "Ren")
+	if !strings.HasPrefix(standings[1], "▶ Ren") || !strings.HasSuffix(standings[1], "done") || !strings.HasSuffix(standings[2], "left") {
+		t.Errorf("unexpected standings %q", standings)
+	}
+}
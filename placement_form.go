@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"kana/kanacore"
+	"kana/store"
+)
+
+// needsPlacement reports whether this is a first launch: no placement test was
+// taken or skipped and there are no statistics yet.
+func needsPlacement(st *store.Store) bool {
+	if st == nil {
+		return false
+	}
+	if placement, err := st.Placement(); err != nil || !placement.At.IsZero() {
+		return false
+	}
+	stats, err := st.TrackStatistics(store.TrackRecognition)
+	return err == nil && len(stats) == 0
+}
+
+// runPlacementTest offers the placement test, asks its questions one form at
+// a time and applies the result before the setup form opens.
+func runPlacementTest(st *store.Store) error {
+	take := true
+	intro := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title("Welcome! Take a quick placement test?").
+			Description(fmt.Sprintf(
+				"Type the romaji for up to %d kana per row so practice starts with the rows you don't know yet.",
+				kanacore.PlacementSamples)).
+			Affirmative("Take the test").
+			Negative("Skip").
+			Value(&take),
+	))
+	if isAccessibleMode() {
+		intro.WithAccessible(true)
+	}
+	if err := intro.Run(); err != nil {
+		return err
+	}
+	if !take {
+		return st.SavePlacement(store.Placement{At: time.Now(), Skipped: true})
+	}
+
+	test := kanacore.NewPlacementTest(kanacore.Hiragana(), kanacore.NewRand(time.Now().UnixNano()))
+	for {
+		k, ok := test.Current()
+		if !ok {
+			break
+		}
+		row, rows := test.Progress()
+		answer := ""
+		question := huh.NewForm(huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Row %d of %d: what is the romaji for %s?", row, rows, k.Char)).
+				Description("Leave it empty if you don't know.").
+				Value(&answer),
+		))
+		if isAccessibleMode() {
+			question.WithAccessible(true)
+		}
+		if err := question.Run(); err != nil {
+			return err
+		}
+		test.Answer(answer)
+	}
+
+	result := test.Result()
+	if err := applyPlacement(st, result); err != nil {
+		return err
+	}
+
+	summary := huh.NewForm(huh.NewGroup(
+		huh.NewNote().
+			Title("Placement done").
+			Description(result.Summary() + "\nYou can change the rows in the next step.").
+			Next(true).
+			NextLabel("Continue"),
+	))
+	if isAccessibleMode() {
+		summary.WithAccessible(true)
+	}
+	return summary.Run()
+}
+
+// applyPlacement selects the rows the placement result suggests, seeds
+// statistics for kana that have none and records the placement.
+func applyPlacement(st *store.Store, result kanacore.PlacementResult) error {
+	if err := st.SaveSelectedRows(result.Rows()); err != nil {
+		return err
+	}
+	if err := st.SaveSelectedChars(nil); err != nil {
+		return err
+	}
+
+	policy := kanacore.DefaultMasteryPolicy()
+	if stored, err := st.MasteryPolicy(); err == nil && stored != "" {
+		policy = kanacore.ParseMasteryPolicy(stored)
+	}
+	existing, err := st.TrackStatistics(store.TrackRecognition)
+	if err != nil {
+		return err
+	}
+	for char, r := range result.Baseline(policy, time.Now()) {
+		if _, ok := existing[char]; ok {
+			continue
+		}
+		if err := st.SaveTrackStats(store.TrackRecognition, char, r.Correct, r.Missed, r.Streak, r.LastSeen); err != nil {
+			return err
+		}
+	}
+	return st.SavePlacement(store.Placement{At: time.Now(), Known: result.Known})
+}
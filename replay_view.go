@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"kana/kanacore"
+)
+
+// replayFrameInterval is how often the replay viewer advances and redraws.
+const replayFrameInterval = 50 * time.Millisecond
+
+// replaySeekStep is how far the arrow keys seek in a replay.
+const replaySeekStep = 5 * time.Second
+
+// ReplayViewer plays a recorded session back in place of the game-over screen.
+type ReplayViewer struct {
+	Replay *kanacore.Replay
+	At     time.Duration // playback position
+	Speed  int           // playback speed, one of kanacore.ReplaySpeeds
+	Paused bool
+}
+
+// replayTickMsg advances the viewer it was scheduled for.
+type replayTickMsg struct {
+	viewer *ReplayViewer
+}
+
+func newReplayViewer(r *kanacore.Replay) *ReplayViewer {
+	return &ReplayViewer{Replay: r, Speed: kanacore.ReplaySpeeds[0]}
+}
+
+// replayTickCmd schedules the viewer's next frame. Ticks for a viewer that
+// has since been closed are dropped, so reopening never doubles the speed.
+func replayTickCmd(v *ReplayViewer) tea.Cmd {
+	return tea.Tick(replayFrameInterval, func(time.Time) tea.Msg {
+		return replayTickMsg{viewer: v}
+	})
+}
+
+// seek moves playback to at, kept within the replay.
+func (v *ReplayViewer) seek(at time.Duration) {
+	switch {
+	case at < 0:
+		at = 0
+	case at > v.Replay.Length:
+		at = v.Replay.Length
+	}
+	v.At = at
+}
+
+// advance plays one frame, pausing at the end.
+func (v *ReplayViewer) advance() {
+	if v.Paused {
+		return
+	}
+	v.seek(v.At + replayFrameInterval*time.Duration(v.Speed))
+	if v.At == v.Replay.Length {
+		v.Paused = true
+	}
+}
+
+// updateViewer handles keys while a replay is shown: space pauses, the speed
+// numbers change speed, arrows seek and ESC returns to the game-over screen.
+func (m *Model) updateViewer(msg tea.KeyMsg) tea.Cmd {
+	v := m.Viewer
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		m.mergeSessionStats()
+		return tea.Quit
+	case "esc", "q", "v":
+		m.Viewer = nil
+	case " ":
+		if v.Paused && v.At == v.Replay.Length {
+			v.At = 0
+		}
+		v.Paused = !v.Paused
+	case "left":
+		v.seek(v.At - replaySeekStep)
+	case "right":
+		v.seek(v.At + replaySeekStep)
+	case "home":
+		v.seek(0)
+	case "end":
+		v.seek(v.Replay.Length)
+	default:
+		for _, speed := range kanacore.ReplaySpeeds {
+			if key == strconv.Itoa(speed) {
+				v.Speed = speed
+			}
+		}
+	}
+	return nil
+}
+
+// renderReplayScreen draws the replayed field with the recorded input buffer
+// and a seek bar.
+func renderReplayScreen(m Model) string {
+	v := m.Viewer
+	f := v.Replay.Frame(v.At)
+
+	field := m
+	field.MissReveals = nil
+	field.Kanas = make([]*kanacore.Kana, len(f.Tiles))
+	for i, k := range f.Tiles {
+		k.X *= float32(m.GameWidth)
+		k.Y *= float32(m.Height)
+		field.Kanas[i] = &k
+	}
+
+	info := []string{
+		tableHeaderStyle.Render("REPLAY"),
+		"",
+		fmt.Sprintf("%s, %s", v.Replay.Mode.Label(), v.Replay.Start.Format("2006-01-02 15:04")),
+		fmt.Sprintf("Score: %d", f.Score),
+		fmt.Sprintf("Missed: %d", f.Missed),
+		"",
+		"Typing: " + inputStyle.Render(f.Input),
+		"Last answer: " + f.Answer,
+	}
+	infoArea := lipgloss.NewStyle().Padding(0, 1).Height(m.Height).Render(strings.Join(info, "\n"))
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top, renderGameArea(field), renderVerticalBorder(m.Height), infoArea)
+	state := "▶"
+	if v.Paused {
+		state = "⏸"
+	}
+	seek := statusStyle.Render(fmt.Sprintf("%s %s %s / %s  %dx", state,
+		renderSeekBar(v.At, v.Replay.Length, max(10, m.Width-30)),
+		kanacore.FormatClock(v.At), kanacore.FormatClock(v.Replay.Length), v.Speed))
+	help := "SPACE play/pause | 1 2 4 speed | ←/→ seek 5s | ESC back"
+	return lipgloss.JoinVertical(lipgloss.Left, top, seek, help)
+}
+
+// renderSeekBar draws playback progress as a bar width cells wide.
+func renderSeekBar(at, length time.Duration, width int) string {
+	filled := width
+	if length > 0 {
+		filled = int(int64(width) * int64(at) / int64(length))
+	}
+	filled = min(filled, width-1)
+	return "[" + strings.Repeat("=", filled) + "●" + strings.Repeat("-", width-1-filled) + "]"
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"kana/api"
+	"kana/store"
+)
+
+// shutdownTimeout bounds how long `kana serve` waits for requests in flight
+// when stopped.
+const shutdownTimeout = 5 * time.Second
+
+// runServe serves the store's progress as read-only JSON until interrupted.
+// args are the command line after `serve`.
+func runServe(st *store.Store, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", api.DefaultAddr, "address to listen on")
+	token := flags.String("token", os.Getenv("KANA_API_TOKEN"),
+		"token clients must send as \"Authorization: Bearer <token>\" or ?token= (default $KANA_API_TOKEN)")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", *addr, err)
+	}
+	if *token == "" && !loopback(ln.Addr()) {
+		fmt.Println("Warning: serving beyond this machine without a token; anyone on the network can read your progress.")
+	}
+	fmt.Printf("Serving kana progress at http://%s/api/ (Ctrl+C to stop)\n", ln.Addr())
+
+	srv := &http.Server{Handler: api.NewHandler(st, *token), ReadHeaderTimeout: 5 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loopback reports whether addr can only be reached from this machine.
+func loopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
@@ -2,31 +2,136 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"kana/kanacore"
+	"kana/netplay"
 	"kana/store"
 )
 
+// sessionSettings holds the preferences collected by the setup form.
+type sessionSettings struct {
+	Rows         []string
+	Chars        []string // individually picked characters; empty means rows apply
+	SaveSetAs    string   // name to save Chars under as a custom set, if any
+	AutoProgress bool
+	GhostRace    bool
+	ScoreLimit   int
+	TargetPolicy kanacore.TargetPolicy
+	Mode         kanacore.GameMode
+	TimeLimit    time.Duration
+	ZenGoal      kanacore.Goal
+	Lookalike    string // look-alike group ID; "" drills the most confused group
+	Mastery      kanacore.MasteryPolicy
+	Curriculum   string // curriculum ID driving auto-progression
+	DailyGoal    kanacore.Goal
+	Players      []string // profile names of split-screen players; empty for a solo session
+	LAN          string   // lanHost or lanJoin to race over the local network; empty otherwise
+	LANName      string   // the local player's name in a LAN race
+	LANAddr      string   // address a LAN race is hosted on or joined at
+}
+
+// Values of the setup form's players select beyond the player counts.
+const (
+	playersLANHost = 3
+	playersLANJoin = 4
+)
+
+// Roles in a LAN race.
+const (
+	lanHost = "host"
+	lanJoin = "join"
+)
+
+// Values of the setup form's practice source select.
+const (
+	charSourceRows      = "rows"
+	charSourceChars     = "chars"
+	charSourceSetPrefix = "set:"
+)
+
 // setupSettingsForm displays a terminal form to collect user preferences.
-func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
+func setupSettingsForm(st *store.Store, curricula []kanacore.Curriculum) (sessionSettings, error) {
 	selectedRows := kanacore.DefaultRowIDs()
 	autoProgress := false
+	ghostRace := false
 	scoreLimit := store.DefaultScoreLimit
+	targetPolicy := kanacore.DefaultTargetPolicy
+	mode := kanacore.DefaultGameMode
+	timeLimit := kanacore.DefaultTimeAttackDuration
+	zenGoal := kanacore.DefaultZenGoal.String()
+	dailyGoal := kanacore.DefaultDailyGoal.String()
+	var selectedChars []string
+	var customSets []store.CustomSet
+	lookalike := ""
+	mastery := kanacore.DefaultMasteryPolicy()
+	curriculum := kanacore.DefaultCurriculumID
+	var confusions map[string]map[string]int
+	players := 1
+	playerNames := [2]string{"Player 1", "Player 2"}
+	var profiles []string
+	lanName := ""
+	lanListen := ":" + netplay.DefaultPort
+	lanJoinAddr := ""
 
 	if st != nil {
 		if rows, err := st.SelectedRows(); err == nil && len(rows) > 0 {
 			selectedRows = rows
 		}
+		if chars, err := st.SelectedChars(); err == nil {
+			selectedChars = kanacore.NormalizeCharSelection(chars)
+		}
+		if sets, err := st.CustomSets(); err == nil {
+			customSets = sets
+		}
 		if auto, err := st.AutoProgress(); err == nil {
 			autoProgress = auto
 		}
+		if race, err := st.GhostRace(); err == nil {
+			ghostRace = race
+		}
 		if limit, err := st.ScoreLimit(); err == nil {
 			scoreLimit = limit
 		}
+		if policy, err := st.TargetPolicy(); err == nil {
+			targetPolicy = kanacore.ParseTargetPolicy(policy)
+		}
+		if stored, err := st.GameMode(); err == nil {
+			mode = kanacore.ParseGameMode(stored)
+		}
+		if mode.Drawn() {
+			// Drawing needs the desktop app's pad; start the terminal in classic mode.
+			mode = kanacore.DefaultGameMode
+		}
+		if seconds, err := st.TimeLimit(); err == nil {
+			timeLimit = kanacore.NormalizeTimeLimit(time.Duration(seconds) * time.Second)
+		}
+		if stored, err := st.ZenGoal(); err == nil && stored != "" {
+			zenGoal = kanacore.ParseGoal(stored).String()
+		}
+		if stored, err := st.DailyGoal(); err == nil && stored != "" {
+			dailyGoal = kanacore.ParseDailyGoal(stored).String()
+		}
+		if stored, err := st.MasteryPolicy(); err == nil && stored != "" {
+			mastery = kanacore.ParseMasteryPolicy(stored)
+		}
+		if stored, err := st.LookalikeGroup(); err == nil {
+			lookalike = stored
+		}
+		if stored, err := st.Curriculum(); err == nil && stored != "" {
+			curriculum = stored
+		}
+		if stored, err := st.Confusions(); err == nil {
+			confusions = stored
+		}
+		if stored, err := st.Profiles(); err == nil {
+			profiles = stored
+		}
 	}
 
 	selection := append([]string(nil), selectedRows...)
@@ -45,11 +150,113 @@ func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
 		options = append(options, option)
 	}
 
+	// The practice source is whole rows, a hand-picked list of characters or
+	// one of the saved custom sets ("set:" followed by its name).
+	source := charSourceRows
+	if len(selectedChars) > 0 {
+		source = charSourceChars
+	}
+	sourceOptions := []huh.Option[string]{
+		huh.NewOption("Whole rows", charSourceRows),
+		huh.NewOption("Individual characters", charSourceChars),
+	}
+	setChars := make(map[string][]string, len(customSets))
+	for _, set := range customSets {
+		sourceOptions = append(sourceOptions, huh.NewOption("Custom set: "+set.Name, charSourceSetPrefix+set.Name))
+		setChars[charSourceSetPrefix+set.Name] = set.Chars
+	}
+
+	charSelection := append([]string(nil), selectedChars...)
+	pickedChars := make(map[string]bool, len(charSelection))
+	for _, char := range charSelection {
+		pickedChars[char] = true
+	}
+	charOptions := make([]huh.Option[string], 0, len(kanacore.CharToRow))
+	hiragana := kanacore.Hiragana()
+	for _, row := range kanacore.AllKanaRows {
+		for _, char := range row.Characters {
+			romaji, _ := hiragana.GetRomaji(char)
+			option := huh.NewOption(fmt.Sprintf("%s (%s)", char, romaji), char)
+			if pickedChars[char] {
+				option = option.Selected(true)
+			}
+			charOptions = append(charOptions, option)
+		}
+	}
+	setName := ""
+
+	policyOptions := make([]huh.Option[kanacore.TargetPolicy], 0, len(kanacore.AllTargetPolicies))
+	for _, policy := range kanacore.AllTargetPolicies {
+		policyOptions = append(policyOptions, huh.NewOption(policy.Label(), policy))
+	}
+
+	modeOptions := make([]huh.Option[kanacore.GameMode], 0, len(kanacore.AllGameModes))
+	for _, m := range kanacore.AllGameModes {
+		if m.Drawn() {
+			continue
+		}
+		modeOptions = append(modeOptions, huh.NewOption(m.Label(), m))
+	}
+
+	durationOptions := make([]huh.Option[time.Duration], 0, len(kanacore.TimeAttackDurations))
+	for _, d := range kanacore.TimeAttackDurations {
+		durationOptions = append(durationOptions, huh.NewOption(fmt.Sprintf("%d seconds", int(d/time.Second)), d))
+	}
+
+	goalOptions := make([]huh.Option[string], 0, len(kanacore.ZenGoals))
+	for _, g := range kanacore.ZenGoals {
+		goalOptions = append(goalOptions, huh.NewOption(g.Label(), g.String()))
+	}
+
+	dailyGoalOptions := make([]huh.Option[string], 0, len(kanacore.DailyGoals))
+	for _, g := range kanacore.DailyGoals {
+		dailyGoalOptions = append(dailyGoalOptions, huh.NewOption(g.DailyLabel(), g.String()))
+	}
+
+	groups := kanacore.ExtendLookalikes(kanacore.LookalikeGroups, confusions)
+	lookalikeOptions := []huh.Option[string]{huh.NewOption("The pair I confuse most", "")}
+	for _, g := range groups {
+		lookalikeOptions = append(lookalikeOptions, huh.NewOption(g.Label(), g.ID()))
+	}
+	if _, ok := kanacore.FindLookalikeGroup(groups, lookalike); !ok {
+		lookalike = ""
+	}
+
+	curriculumOptions := make([]huh.Option[string], 0, len(curricula))
+	for _, c := range curricula {
+		curriculumOptions = append(curriculumOptions, huh.NewOption(c.Name, c.ID))
+	}
+	if _, ok := kanacore.FindCurriculum(curricula, curriculum); !ok {
+		curriculum = kanacore.DefaultCurriculumID
+	}
+
+	masteryCorrect := strconv.Itoa(mastery.MinCorrect)
+	masteryAccuracy := strconv.Itoa(int(mastery.MinAccuracy*100 + 0.5))
+	masteryStreak := strconv.Itoa(mastery.MinStreak)
+	masteryDays := strconv.Itoa(int(mastery.MaxAge / (24 * time.Hour)))
+
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewNote().
 				Title("Kana Practice Setup").
 				Description("Select the rows you want to study. You can change these later."),
+			huh.NewSelect[kanacore.GameMode]().
+				Title("Game mode").
+				Options(modeOptions...).
+				Value(&mode),
+			huh.NewSelect[int]().
+				Title("Players").
+				Options(
+					huh.NewOption("1 player", 1),
+					huh.NewOption("2 players, split screen", 2),
+					huh.NewOption("Host a LAN race", playersLANHost),
+					huh.NewOption("Join a LAN race", playersLANJoin),
+				).
+				Value(&players),
+			huh.NewSelect[string]().
+				Title("Practise").
+				Options(sourceOptions...).
+				Value(&source),
 			huh.NewMultiSelect[string]().
 				Title("Kana Rows").
 				Options(options...).
@@ -61,6 +268,11 @@ func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
 				Affirmative("Yes").
 				Negative("No").
 				Value(&autoProgress),
+			huh.NewSelect[string]().
+				Title("Curriculum").
+				Description("The order auto-progression unlocks kana in. Add your own in the curricula folder.").
+				Options(curriculumOptions...).
+				Value(&curriculum),
 			huh.NewInput().
 				Title("Score limit before the session ends").
 				Description("Reach this score to finish. Use 0 for endless practice.").
@@ -79,7 +291,128 @@ func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
 					}
 					return nil
 				}),
+			huh.NewSelect[kanacore.TargetPolicy]().
+				Title("When several tiles match, clear the").
+				Options(policyOptions...).
+				Value(&targetPolicy),
+			huh.NewSelect[string]().
+				Title("Daily goal").
+				Description("Meet it every day to build a streak.").
+				Options(dailyGoalOptions...).
+				Value(&dailyGoal),
+			huh.NewConfirm().
+				Title("Race a ghost of your best run?").
+				Description("Replays the kana of your best recorded session in this mode and shows its score next to yours.").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&ghostRace),
 		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Mastery").
+				Description("A kana turns green once it meets all of these; the curriculum sets how much of each unlocked lesson must be green to unlock the next."),
+			huh.NewInput().
+				Title("Correct answers needed").
+				Value(&masteryCorrect).
+				Validate(wholeNumberUpTo(0)),
+			huh.NewInput().
+				Title("Minimum accuracy (%)").
+				Value(&masteryAccuracy).
+				Validate(wholeNumberUpTo(100)),
+			huh.NewInput().
+				Title("Correct answers in a row needed").
+				Value(&masteryStreak).
+				Validate(wholeNumberUpTo(0)),
+			huh.NewInput().
+				Title("Days before mastery fades without practice").
+				Description("Use 0 to keep mastered kana green forever.").
+				Value(&masteryDays).
+				Validate(wholeNumberUpTo(0)),
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Split screen").
+				Description("Each player's results are kept under their name. Multiple choice plays as classic."),
+			huh.NewInput().
+				Title("Left player (types after [)").
+				Suggestions(profiles).
+				Value(&playerNames[0]).
+				Validate(profileName),
+			huh.NewInput().
+				Title("Right player (types after ])").
+				Suggestions(profiles).
+				Value(&playerNames[1]).
+				Validate(func(v string) error {
+					if strings.TrimSpace(v) == strings.TrimSpace(playerNames[0]) {
+						return errors.New("pick a different name from the left player")
+					}
+					return profileName(v)
+				}),
+		).WithHideFunc(func() bool { return players != 2 }),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Host a LAN race").
+				Description("Everyone plays your mode, rules and kana on the same tiles. Multiple choice and look-alike drills play as classic."),
+			huh.NewInput().
+				Title("Your name").
+				Suggestions(profiles).
+				Value(&lanName).
+				Validate(profileName),
+			huh.NewInput().
+				Title("Listen on").
+				Description("Others join with this machine's address and the port.").
+				Value(&lanListen).
+				Validate(lanAddress),
+		).WithHideFunc(func() bool { return players != playersLANHost }),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Join a LAN race").
+				Description("You play the host's mode, rules and kana on the same tiles as everyone else."),
+			huh.NewInput().
+				Title("Your name").
+				Suggestions(profiles).
+				Value(&lanName).
+				Validate(profileName),
+			huh.NewInput().
+				Title("Host address").
+				Description("The address the host's lobby shows, such as 192.168.1.10:"+netplay.DefaultPort+".").
+				Value(&lanJoinAddr).
+				Validate(lanAddress),
+		).WithHideFunc(func() bool { return players != playersLANJoin }),
+		huh.NewGroup(
+			huh.NewSelect[time.Duration]().
+				Title("Time attack length").
+				Description("Clear as many kana as you can before the clock runs out.").
+				Options(durationOptions...).
+				Value(&timeLimit),
+		).WithHideFunc(func() bool { return mode != kanacore.ModeTimeAttack }),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Characters").
+				Description("Pick the kana to practise, such as the ones you keep missing.").
+				Options(charOptions...).
+				Value(&charSelection).
+				Limit(len(charOptions)).
+				Height(min(len(charOptions), 10)),
+			huh.NewInput().
+				Title("Save as custom set").
+				Description("Name this selection to reuse it later. Leave empty to skip.").
+				Value(&setName),
+		).WithHideFunc(func() bool { return source != charSourceChars }),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Zen goal").
+				Description("Misses never end a zen session; it finishes when you reach this goal.").
+				Options(goalOptions...).
+				Value(&zenGoal),
+		).WithHideFunc(func() bool { return mode != kanacore.ModeZen }),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Look-alike group").
+				Description(fmt.Sprintf("These kana drop in quick alternation until you clear %d.", kanacore.LookalikeGoal.Count)).
+				Options(lookalikeOptions...).
+				Value(&lookalike),
+		).WithHideFunc(func() bool { return mode != kanacore.ModeLookalike }),
 	)
 
 	if isAccessibleMode() {
@@ -87,10 +420,32 @@ func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
 	}
 
 	if err := form.Run(); err != nil {
-		return nil, false, 0, err
+		return sessionSettings{}, err
 	}
 
 	normalized := normalizeRowSelection(selection)
+	var chars []string
+	switch {
+	case source == charSourceChars:
+		chars = kanacore.NormalizeCharSelection(charSelection)
+	case strings.HasPrefix(source, charSourceSetPrefix):
+		chars = kanacore.NormalizeCharSelection(setChars[source])
+	}
+	saveAs := ""
+	if source == charSourceChars && len(chars) > 0 {
+		saveAs = strings.TrimSpace(setName)
+	}
+	var versusPlayers []string
+	if players == 2 {
+		versusPlayers = []string{strings.TrimSpace(playerNames[0]), strings.TrimSpace(playerNames[1])}
+	}
+	var lan, lanAddr string
+	switch players {
+	case playersLANHost:
+		lan, lanAddr = lanHost, netplay.WithDefaultPort(lanListen)
+	case playersLANJoin:
+		lan, lanAddr = lanJoin, netplay.WithDefaultPort(lanJoinAddr)
+	}
 	limit := store.DefaultScoreLimit
 	if trimmed := strings.TrimSpace(scoreLimitStr); trimmed != "" {
 		if parsed, err := strconv.Atoi(trimmed); err == nil {
@@ -98,7 +453,70 @@ func setupSettingsForm(st *store.Store) ([]string, bool, int, error) {
 		}
 	}
 
-	return normalized, autoProgress, limit, nil
+	return sessionSettings{
+		Rows:         normalized,
+		Chars:        chars,
+		SaveSetAs:    saveAs,
+		AutoProgress: autoProgress,
+		GhostRace:    ghostRace,
+		ScoreLimit:   limit,
+		TargetPolicy: targetPolicy,
+		Mode:         mode,
+		TimeLimit:    timeLimit,
+		ZenGoal:      kanacore.ParseGoal(zenGoal),
+		Lookalike:    lookalike,
+		Curriculum:   curriculum,
+		DailyGoal:    kanacore.ParseDailyGoal(dailyGoal),
+		Players:      versusPlayers,
+		LAN:          lan,
+		LANName:      strings.TrimSpace(lanName),
+		LANAddr:      lanAddr,
+		Mastery: kanacore.MasteryPolicy{
+			MinCorrect:  parseWholeNumber(masteryCorrect, mastery.MinCorrect),
+			MinAccuracy: float64(parseWholeNumber(masteryAccuracy, int(mastery.MinAccuracy*100+0.5))) / 100,
+			MinStreak:   parseWholeNumber(masteryStreak, mastery.MinStreak),
+			MaxAge:      time.Duration(parseWholeNumber(masteryDays, int(mastery.MaxAge/(24*time.Hour)))) * 24 * time.Hour,
+		},
+	}, nil
+}
+
+// lanAddress validates the address a LAN race is hosted on or joined at.
+func lanAddress(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return errors.New("enter an address")
+	}
+	return nil
+}
+
+// profileName validates a split-screen player's name.
+func profileName(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return errors.New("enter a name")
+	}
+	return nil
+}
+
+// wholeNumberUpTo validates a non-negative whole number no larger than max.
+// A max of 0 means no upper bound.
+func wholeNumberUpTo(max int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n < 0 {
+			return errors.New("enter a whole number of zero or more")
+		}
+		if max > 0 && n > max {
+			return fmt.Errorf("enter a number up to %d", max)
+		}
+		return nil
+	}
+}
+
+// parseWholeNumber reads a validated form number, falling back when it cannot be parsed.
+func parseWholeNumber(v string, fallback int) int {
+	if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n >= 0 {
+		return n
+	}
+	return fallback
 }
 
 func normalizeRowSelection(selection []string) []string {
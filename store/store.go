@@ -19,6 +19,18 @@ const (
 	selectedRowsKey   = "selected_rows"
 	autoProgressKey   = "auto_progress"
 	scoreLimitKey     = "score_limit"
+	targetPolicyKey   = "target_policy"
+	gameModeKey       = "game_mode"
+	timeLimitKey      = "time_limit"
+	zenGoalKey        = "zen_goal"
+	selectedCharsKey  = "selected_chars"
+	lookalikeGroupKey = "lookalike_group"
+	masteryPolicyKey  = "mastery_policy"
+	regressionKey     = "regression"
+	curriculumKey     = "curriculum"
+	placementKey      = "placement"
+	dailyGoalKey      = "daily_goal"
+	ghostRaceKey      = "ghost_race"
 	databaseFilePerm  = 0o644
 	databaseDirPerm   = 0o755
 	defaultOpenTimout = 5 * time.Second
@@ -28,7 +40,8 @@ const DefaultScoreLimit = 1000
 
 // Store provides persisted access to user settings and kana statistics.
 type Store struct {
-	db *sql.DB
+	db  *sql.DB
+	dir string // directory holding the database
 }
 
 // KanaStats represents the aggregated statistics for a single kana character.
@@ -37,6 +50,26 @@ type KanaStats struct {
 	CorrectCount int
 	MissCount    int
 	Streak       int
+	LastSeen     time.Time // when the kana was last answered or missed; zero if unknown
+}
+
+// SessionRecord captures the outcome of one finished session.
+type SessionRecord struct {
+	ID            int64
+	StartedAt     time.Time
+	EndedAt       time.Time
+	Mode          string
+	TimeLimit     int // countdown length in seconds, 0 when untimed
+	Reason        string
+	Score         int
+	Missed        int
+	BasePoints    int
+	ComboPoints   int
+	SpeedPoints   int
+	PenaltyPoints int
+	Hits          int
+	Wrong         int
+	MaxCombo      int
 }
 
 // Open initialises the SQLite database located at path and applies migrations.
@@ -63,7 +96,25 @@ func Open(path string) (*Store, error) {
 		return nil, err
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: db, dir: filepath.Dir(path)}, nil
+}
+
+// replayDirName is the directory next to the database that holds replays.
+const replayDirName = "replays"
+
+// curriculumDirName is the directory next to the database that holds the
+// user's curriculum files.
+const curriculumDirName = "curricula"
+
+// CurriculumDir returns the directory the user's curriculum files are loaded
+// from. Each *.txt file in it is one curriculum, named by its file name.
+func (s *Store) CurriculumDir() string {
+	return filepath.Join(s.dir, curriculumDirName)
+}
+
+// ReplayPath returns the file the replay of a saved session is kept in.
+func (s *Store) ReplayPath(sessionID int64) string {
+	return filepath.Join(s.dir, replayDirName, fmt.Sprintf("session-%d.replay", sessionID))
 }
 
 // Close releases the underlying database resources.
@@ -102,6 +153,142 @@ func (s *Store) SaveSelectedRows(rows []string) error {
 	return s.setSetting(selectedRowsKey, string(payload))
 }
 
+// SelectedChars loads the individually selected characters. Returns nil if
+// unset, in which case row selection applies.
+func (s *Store) SelectedChars() ([]string, error) {
+	value, err := s.getSetting(selectedCharsKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var chars []string
+	if err := json.Unmarshal([]byte(value), &chars); err != nil {
+		return nil, fmt.Errorf("store: decode selected chars: %w", err)
+	}
+	return chars, nil
+}
+
+// SaveSelectedChars persists the individually selected characters. Passing
+// nil or an empty slice clears the value so row selection applies again.
+func (s *Store) SaveSelectedChars(chars []string) error {
+	if len(chars) == 0 {
+		return s.deleteSetting(selectedCharsKey)
+	}
+	payload, err := json.Marshal(chars)
+	if err != nil {
+		return fmt.Errorf("store: encode selected chars: %w", err)
+	}
+	return s.setSetting(selectedCharsKey, string(payload))
+}
+
+// CustomSet is a named selection of characters saved by the player.
+type CustomSet struct {
+	Name  string
+	Chars []string
+}
+
+// SaveCustomSet creates or replaces the named character set.
+func (s *Store) SaveCustomSet(name string, chars []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("store: set name is required")
+	}
+	payload, err := json.Marshal(chars)
+	if err != nil {
+		return fmt.Errorf("store: encode custom set: %w", err)
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO custom_sets (name, chars)
+		VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			chars = excluded.chars
+	`, name, string(payload))
+	if err != nil {
+		return fmt.Errorf("store: save custom set %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteCustomSet removes the named character set if it exists.
+func (s *Store) DeleteCustomSet(name string) error {
+	if _, err := s.db.Exec(`DELETE FROM custom_sets WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("store: delete custom set %s: %w", name, err)
+	}
+	return nil
+}
+
+// CustomSets returns every saved character set ordered by name.
+func (s *Store) CustomSets() ([]CustomSet, error) {
+	rows, err := s.db.Query(`SELECT name, chars FROM custom_sets ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query custom sets: %w", err)
+	}
+	defer rows.Close()
+
+	var sets []CustomSet
+	for rows.Next() {
+		var set CustomSet
+		var payload string
+		if err := rows.Scan(&set.Name, &payload); err != nil {
+			return nil, fmt.Errorf("store: scan custom set: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &set.Chars); err != nil {
+			return nil, fmt.Errorf("store: decode custom set %s: %w", set.Name, err)
+		}
+		sets = append(sets, set)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate custom sets: %w", err)
+	}
+	return sets, nil
+}
+
+// Mnemonics returns the player's own memory hints keyed by kana.
+func (s *Store) Mnemonics() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT char, hint FROM mnemonics`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query mnemonics: %w", err)
+	}
+	defer rows.Close()
+
+	hints := make(map[string]string)
+	for rows.Next() {
+		var char, hint string
+		if err := rows.Scan(&char, &hint); err != nil {
+			return nil, fmt.Errorf("store: scan mnemonic: %w", err)
+		}
+		hints[char] = hint
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate mnemonics: %w", err)
+	}
+	return hints, nil
+}
+
+// SaveMnemonic stores the player's memory hint for char. An empty hint
+// removes it so the bundled hint applies again.
+func (s *Store) SaveMnemonic(char, hint string) error {
+	hint = strings.TrimSpace(hint)
+	if hint == "" {
+		if _, err := s.db.Exec(`DELETE FROM mnemonics WHERE char = ?`, char); err != nil {
+			return fmt.Errorf("store: delete mnemonic %s: %w", char, err)
+		}
+		return nil
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO mnemonics (char, hint)
+		VALUES (?, ?)
+		ON CONFLICT(char) DO UPDATE SET
+			hint = excluded.hint
+	`, char, hint)
+	if err != nil {
+		return fmt.Errorf("store: save mnemonic %s: %w", char, err)
+	}
+	return nil
+}
+
 // AutoProgress returns the persisted auto progression flag.
 func (s *Store) AutoProgress() (bool, error) {
 	value, err := s.getSetting(autoProgressKey)
@@ -127,6 +314,26 @@ func (s *Store) SaveAutoProgress(enabled bool) error {
 	return s.setSetting(autoProgressKey, "0")
 }
 
+// GhostRace returns whether sessions race a ghost of the best recorded run.
+func (s *Store) GhostRace() (bool, error) {
+	value, err := s.getSetting(ghostRaceKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return value == "1", nil
+}
+
+// SaveGhostRace toggles racing a ghost of the best recorded run.
+func (s *Store) SaveGhostRace(enabled bool) error {
+	if enabled {
+		return s.setSetting(ghostRaceKey, "1")
+	}
+	return s.setSetting(ghostRaceKey, "0")
+}
+
 // ScoreLimit returns the configured score threshold for ending a session.
 func (s *Store) ScoreLimit() (int, error) {
 	value, err := s.getSetting(scoreLimitKey)
@@ -154,8 +361,315 @@ func (s *Store) SaveScoreLimit(limit int) error {
 	return s.setSetting(scoreLimitKey, strconv.Itoa(limit))
 }
 
-// SaveKanaStats upserts the aggregated statistics for the provided kana.
+// TargetPolicy returns the stored tile targeting policy. Returns "" if unset.
+func (s *Store) TargetPolicy() (string, error) {
+	value, err := s.getSetting(targetPolicyKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveTargetPolicy persists the tile targeting policy.
+func (s *Store) SaveTargetPolicy(policy string) error {
+	return s.setSetting(targetPolicyKey, policy)
+}
+
+// GameMode returns the stored game mode. Returns "" if unset.
+func (s *Store) GameMode() (string, error) {
+	value, err := s.getSetting(gameModeKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveGameMode persists the game mode.
+func (s *Store) SaveGameMode(mode string) error {
+	return s.setSetting(gameModeKey, mode)
+}
+
+// TimeLimit returns the stored countdown length in seconds. Returns 0 if unset.
+func (s *Store) TimeLimit() (int, error) {
+	value, err := s.getSetting(timeLimitKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0, nil
+	}
+	return seconds, nil
+}
+
+// SaveTimeLimit persists the countdown length in seconds.
+func (s *Store) SaveTimeLimit(seconds int) error {
+	if seconds < 0 {
+		seconds = 0
+	}
+	return s.setSetting(timeLimitKey, strconv.Itoa(seconds))
+}
+
+// ZenGoal returns the stored zen goal encoding. Returns "" if unset.
+func (s *Store) ZenGoal() (string, error) {
+	value, err := s.getSetting(zenGoalKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveZenGoal persists the zen goal encoding.
+func (s *Store) SaveZenGoal(goal string) error {
+	return s.setSetting(zenGoalKey, goal)
+}
+
+// DailyGoal returns the stored daily goal encoding. Returns "" if unset.
+func (s *Store) DailyGoal() (string, error) {
+	value, err := s.getSetting(dailyGoalKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveDailyGoal persists the daily goal encoding.
+func (s *Store) SaveDailyGoal(goal string) error {
+	return s.setSetting(dailyGoalKey, goal)
+}
+
+// DailyTotal is one day's practice totals.
+type DailyTotal struct {
+	Day     string // local date as YYYY-MM-DD
+	Correct int
+	Played  time.Duration
+	GoalMet bool
+}
+
+// DailyTotals returns every practised day, oldest first.
+func (s *Store) DailyTotals() ([]DailyTotal, error) {
+	rows, err := s.db.Query(`SELECT day, correct, played_seconds, goal_met FROM daily_totals ORDER BY day`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query daily totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []DailyTotal
+	for rows.Next() {
+		var d DailyTotal
+		var seconds int64
+		var met int
+		if err := rows.Scan(&d.Day, &d.Correct, &seconds, &met); err != nil {
+			return nil, fmt.Errorf("store: scan daily total: %w", err)
+		}
+		d.Played = time.Duration(seconds) * time.Second
+		d.GoalMet = met != 0
+		totals = append(totals, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate daily totals: %w", err)
+	}
+	return totals, nil
+}
+
+// SaveDailyTotal stores a day's totals, replacing any saved before.
+func (s *Store) SaveDailyTotal(d DailyTotal) error {
+	met := 0
+	if d.GoalMet {
+		met = 1
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO daily_totals (day, correct, played_seconds, goal_met)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(day) DO UPDATE SET
+			correct = excluded.correct,
+			played_seconds = excluded.played_seconds,
+			goal_met = excluded.goal_met
+	`, d.Day, d.Correct, int64(d.Played/time.Second), met)
+	if err != nil {
+		return fmt.Errorf("store: save daily total %s: %w", d.Day, err)
+	}
+	return nil
+}
+
+// Achievements returns when each earned achievement was earned, keyed by ID.
+func (s *Store) Achievements() (map[string]time.Time, error) {
+	rows, err := s.db.Query(`SELECT id, earned_at FROM achievements`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query achievements: %w", err)
+	}
+	defer rows.Close()
+
+	earned := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var at int64
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("store: scan achievement: %w", err)
+		}
+		earned[id] = time.Unix(at, 0)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate achievements: %w", err)
+	}
+	return earned, nil
+}
+
+// SaveAchievement records that an achievement was earned at the given time.
+// An achievement already recorded keeps its original time.
+func (s *Store) SaveAchievement(id string, at time.Time) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO achievements (id, earned_at) VALUES (?, ?)`, id, at.Unix())
+	if err != nil {
+		return fmt.Errorf("store: save achievement %s: %w", id, err)
+	}
+	return nil
+}
+
+// LookalikeGroup returns the ID of the group chosen for look-alike drills.
+// Returns "" when unset, meaning the most confused group is drilled.
+func (s *Store) LookalikeGroup() (string, error) {
+	value, err := s.getSetting(lookalikeGroupKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveLookalikeGroup persists the look-alike drill group ID.
+func (s *Store) SaveLookalikeGroup(id string) error {
+	return s.setSetting(lookalikeGroupKey, id)
+}
+
+// MasteryPolicy returns the encoded mastery policy, or "" when unset.
+func (s *Store) MasteryPolicy() (string, error) {
+	value, err := s.getSetting(masteryPolicyKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveMasteryPolicy persists the mastery policy encoding.
+func (s *Store) SaveMasteryPolicy(policy string) error {
+	return s.setSetting(masteryPolicyKey, policy)
+}
+
+// Curriculum returns the active curriculum ID, or "" when unset.
+func (s *Store) Curriculum() (string, error) {
+	value, err := s.getSetting(curriculumKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveCurriculum persists the active curriculum ID.
+func (s *Store) SaveCurriculum(id string) error {
+	return s.setSetting(curriculumKey, id)
+}
+
+// Placement records the outcome of the first-launch placement test.
+type Placement struct {
+	At      time.Time `json:"at"`
+	Skipped bool      `json:"skipped,omitempty"`
+	Known   []string  `json:"known,omitempty"` // row IDs the test found known
+}
+
+// Placement loads the placement record. A zero At means no placement was done.
+func (s *Store) Placement() (Placement, error) {
+	value, err := s.getSetting(placementKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Placement{}, nil
+	}
+	if err != nil {
+		return Placement{}, err
+	}
+	var p Placement
+	if err := json.Unmarshal([]byte(value), &p); err != nil {
+		return Placement{}, fmt.Errorf("store: decode placement: %w", err)
+	}
+	return p, nil
+}
+
+// SavePlacement records that a placement test was taken or skipped.
+func (s *Store) SavePlacement(p Placement) error {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("store: encode placement: %w", err)
+	}
+	return s.setSetting(placementKey, string(payload))
+}
+
+// Regression returns the encoded active auto-regression, or "" when none is active.
+func (s *Store) Regression() (string, error) {
+	value, err := s.getSetting(regressionKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// SaveRegression persists the active auto-regression. An empty value clears it.
+func (s *Store) SaveRegression(regression string) error {
+	if regression == "" {
+		return s.deleteSetting(regressionKey)
+	}
+	return s.setSetting(regressionKey, regression)
+}
+
+// StatsTrack separates per-character statistics for different kinds of practice.
+type StatsTrack string
+
+const (
+	// TrackRecognition covers reading kana and answering with romaji.
+	TrackRecognition StatsTrack = "recognition"
+	// TrackProduction covers reading romaji and answering with kana.
+	TrackProduction StatsTrack = "production"
+)
+
+func (t StatsTrack) table() string {
+	if t == TrackProduction {
+		return "kana_production_stats"
+	}
+	return "kana_stats"
+}
+
+// SaveKanaStats upserts the aggregated recognition statistics for the provided kana.
 func (s *Store) SaveKanaStats(char string, correctCount, missCount, streak int) error {
+	return s.SaveTrackStats(TrackRecognition, char, correctCount, missCount, streak, time.Time{})
+}
+
+// SaveTrackStats upserts the aggregated statistics for the provided kana on a
+// track. The stored last-seen time only moves forward; pass the zero time to
+// keep it.
+func (s *Store) SaveTrackStats(track StatsTrack, char string, correctCount, missCount, streak int, lastSeen time.Time) error {
 	if char == "" {
 		return errors.New("store: char is required")
 	}
@@ -168,14 +682,19 @@ func (s *Store) SaveKanaStats(char string, correctCount, missCount, streak int)
 	if streak < 0 {
 		streak = 0
 	}
+	seen := int64(0)
+	if !lastSeen.IsZero() {
+		seen = lastSeen.Unix()
+	}
 	_, err := s.db.Exec(`
-		INSERT INTO kana_stats (char, correct_count, miss_count, streak)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO `+track.table()+` (char, correct_count, miss_count, streak, last_seen)
+		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(char) DO UPDATE SET
 			correct_count = excluded.correct_count,
 			miss_count = excluded.miss_count,
-			streak = excluded.streak
-	`, char, correctCount, missCount, streak)
+			streak = excluded.streak,
+			last_seen = MAX(last_seen, excluded.last_seen)
+	`, char, correctCount, missCount, streak, seen)
 	if err != nil {
 		return fmt.Errorf("store: save kana stats %s: %w", char, err)
 	}
@@ -229,12 +748,16 @@ func (s *Store) SetStreak(char string, streak int) error {
 	return nil
 }
 
-// KanaStatistics returns the stats for all tracked characters.
+// KanaStatistics returns the recognition stats for all tracked characters.
 func (s *Store) KanaStatistics() (map[string]KanaStats, error) {
+	return s.TrackStatistics(TrackRecognition)
+}
+
+// TrackStatistics returns the stats for all characters tracked on a track.
+func (s *Store) TrackStatistics(track StatsTrack) (map[string]KanaStats, error) {
 	rows, err := s.db.Query(`
-		SELECT char, correct_count, miss_count, streak
-		FROM kana_stats
-	`)
+		SELECT char, correct_count, miss_count, streak, last_seen
+		FROM ` + track.table())
 	if err != nil {
 		return nil, fmt.Errorf("store: query kana stats: %w", err)
 	}
@@ -243,9 +766,13 @@ func (s *Store) KanaStatistics() (map[string]KanaStats, error) {
 	stats := make(map[string]KanaStats)
 	for rows.Next() {
 		var ks KanaStats
-		if err := rows.Scan(&ks.Char, &ks.CorrectCount, &ks.MissCount, &ks.Streak); err != nil {
+		var seen int64
+		if err := rows.Scan(&ks.Char, &ks.CorrectCount, &ks.MissCount, &ks.Streak, &seen); err != nil {
 			return nil, fmt.Errorf("store: scan kana stats: %w", err)
 		}
+		if seen > 0 {
+			ks.LastSeen = time.Unix(seen, 0)
+		}
 		stats[ks.Char] = ks
 	}
 	if err := rows.Err(); err != nil {
@@ -254,6 +781,308 @@ func (s *Store) KanaStatistics() (map[string]KanaStats, error) {
 	return stats, nil
 }
 
+// AddProfileStats adds one session's per-character results to a player
+// profile's totals on a track. CorrectCount and MissCount are added, Streak
+// replaces the stored streak and LastSeen only moves forward. Profiles keep
+// split-screen players' statistics apart from the main player's.
+func (s *Store) AddProfileStats(profile string, track StatsTrack, session []KanaStats) error {
+	if profile == "" {
+		return errors.New("store: profile is required")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: begin profile stats: %w", err)
+	}
+	defer tx.Rollback()
+	for _, ks := range session {
+		seen := int64(0)
+		if !ks.LastSeen.IsZero() {
+			seen = ks.LastSeen.Unix()
+		}
+		_, err := tx.Exec(`
+			INSERT INTO profile_stats (profile, track, char, correct_count, miss_count, streak, last_seen)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(profile, track, char) DO UPDATE SET
+				correct_count = correct_count + excluded.correct_count,
+				miss_count = miss_count + excluded.miss_count,
+				streak = excluded.streak,
+				last_seen = MAX(last_seen, excluded.last_seen)
+		`, profile, string(track), ks.Char, ks.CorrectCount, ks.MissCount, ks.Streak, seen)
+		if err != nil {
+			return fmt.Errorf("store: save profile stats %s: %w", ks.Char, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: commit profile stats: %w", err)
+	}
+	return nil
+}
+
+// ProfileStatistics returns a player profile's stats for all characters
+// tracked on a track.
+func (s *Store) ProfileStatistics(profile string, track StatsTrack) (map[string]KanaStats, error) {
+	rows, err := s.db.Query(`
+		SELECT char, correct_count, miss_count, streak, last_seen
+		FROM profile_stats WHERE profile = ? AND track = ?
+	`, profile, string(track))
+	if err != nil {
+		return nil, fmt.Errorf("store: query profile stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]KanaStats)
+	for rows.Next() {
+		var ks KanaStats
+		var seen int64
+		if err := rows.Scan(&ks.Char, &ks.CorrectCount, &ks.MissCount, &ks.Streak, &seen); err != nil {
+			return nil, fmt.Errorf("store: scan profile stats: %w", err)
+		}
+		if seen > 0 {
+			ks.LastSeen = time.Unix(seen, 0)
+		}
+		stats[ks.Char] = ks
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate profile stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Profiles returns the names of the player profiles that have played a
+// match, in alphabetical order.
+func (s *Store) Profiles() ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT player_a FROM matches
+		UNION SELECT player_b FROM matches
+		ORDER BY 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("store: scan profile: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate profiles: %w", err)
+	}
+	return names, nil
+}
+
+// RecordConfusion counts one occasion where answer was given for char.
+func (s *Store) RecordConfusion(char, answer string) error {
+	if char == "" || answer == "" {
+		return errors.New("store: char and answer are required")
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO confusions (char, answer, count)
+		VALUES (?, ?, 1)
+		ON CONFLICT(char, answer) DO UPDATE SET
+			count = count + 1
+	`, char, answer)
+	if err != nil {
+		return fmt.Errorf("store: record confusion: %w", err)
+	}
+	return nil
+}
+
+// Confusions returns, per character, how often each wrong answer was given for it.
+func (s *Store) Confusions() (map[string]map[string]int, error) {
+	rows, err := s.db.Query(`SELECT char, answer, count FROM confusions`)
+	if err != nil {
+		return nil, fmt.Errorf("store: query confusions: %w", err)
+	}
+	defer rows.Close()
+
+	confusions := make(map[string]map[string]int)
+	for rows.Next() {
+		var char, answer string
+		var count int
+		if err := rows.Scan(&char, &answer, &count); err != nil {
+			return nil, fmt.Errorf("store: scan confusion: %w", err)
+		}
+		if confusions[char] == nil {
+			confusions[char] = make(map[string]int)
+		}
+		confusions[char][answer] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate confusions: %w", err)
+	}
+	return confusions, nil
+}
+
+// SaveSession appends a finished session and returns its ID.
+func (s *Store) SaveSession(rec SessionRecord) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO sessions (
+			started_at, ended_at, mode, time_limit, reason, score, missed,
+			base_points, combo_points, speed_points, penalty_points,
+			hits, wrong, max_combo
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, rec.StartedAt.UTC().Unix(), rec.EndedAt.UTC().Unix(), rec.Mode, rec.TimeLimit, rec.Reason, rec.Score, rec.Missed,
+		rec.BasePoints, rec.ComboPoints, rec.SpeedPoints, rec.PenaltyPoints,
+		rec.Hits, rec.Wrong, rec.MaxCombo)
+	if err != nil {
+		return 0, fmt.Errorf("store: save session: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("store: session id: %w", err)
+	}
+	return id, nil
+}
+
+// MatchRecord captures the outcome of one split-screen race between two
+// player profiles.
+type MatchRecord struct {
+	PlayedAt time.Time
+	Mode     string
+	Players  [2]string
+	Scores   [2]int
+	Winner   int // index into Players, or -1 for a draw
+}
+
+// SaveMatch appends a finished split-screen race.
+func (s *Store) SaveMatch(rec MatchRecord) error {
+	winner := ""
+	if rec.Winner >= 0 && rec.Winner < len(rec.Players) {
+		winner = rec.Players[rec.Winner]
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO matches (played_at, mode, player_a, score_a, player_b, score_b, winner)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, rec.PlayedAt.UTC().Unix(), rec.Mode, rec.Players[0], rec.Scores[0], rec.Players[1], rec.Scores[1], winner)
+	if err != nil {
+		return fmt.Errorf("store: save match: %w", err)
+	}
+	return nil
+}
+
+// HeadToHead counts the matches profiles a and b played against each other:
+// the wins of each and the draws.
+func (s *Store) HeadToHead(a, b string) (winsA, winsB, draws int, err error) {
+	err = s.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(winner = ?), 0),
+			COALESCE(SUM(winner = ?), 0),
+			COALESCE(SUM(winner = ''), 0)
+		FROM matches
+		WHERE (player_a = ? AND player_b = ?) OR (player_a = ? AND player_b = ?)
+	`, a, b, a, b, b, a).Scan(&winsA, &winsB, &draws)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("store: head to head: %w", err)
+	}
+	return winsA, winsB, draws, nil
+}
+
+// Sessions returns the most recent sessions, newest first. A limit of zero
+// or less returns every session.
+func (s *Store) Sessions(limit int) ([]SessionRecord, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(`
+		SELECT id, started_at, ended_at, mode, time_limit, reason, score, missed,
+			base_points, combo_points, speed_points, penalty_points,
+			hits, wrong, max_combo
+		FROM sessions
+		ORDER BY id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("store: query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []SessionRecord
+	for rows.Next() {
+		var rec SessionRecord
+		var started, ended int64
+		if err := rows.Scan(&rec.ID, &started, &ended, &rec.Mode, &rec.TimeLimit, &rec.Reason, &rec.Score, &rec.Missed,
+			&rec.BasePoints, &rec.ComboPoints, &rec.SpeedPoints, &rec.PenaltyPoints,
+			&rec.Hits, &rec.Wrong, &rec.MaxCombo); err != nil {
+			return nil, fmt.Errorf("store: scan session: %w", err)
+		}
+		rec.StartedAt = time.Unix(started, 0)
+		rec.EndedAt = time.Unix(ended, 0)
+		sessions = append(sessions, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// BestScore returns the highest session score recorded for the mode and
+// countdown length. Returns 0 when no session matches.
+func (s *Store) BestScore(mode string, timeLimit int) (int, error) {
+	return s.bestSessionValue("score", mode, timeLimit)
+}
+
+// BestHits returns the most correct answers recorded in a single session for
+// the mode and countdown length. Returns 0 when no session matches.
+func (s *Store) BestHits(mode string, timeLimit int) (int, error) {
+	return s.bestSessionValue("hits", mode, timeLimit)
+}
+
+// BestHitsSince is BestHits over the sessions started at or after since.
+func (s *Store) BestHitsSince(mode string, timeLimit int, since time.Time) (int, error) {
+	var best sql.NullInt64
+	err := s.db.QueryRow(`
+		SELECT MAX(hits) FROM sessions WHERE mode = ? AND time_limit = ? AND started_at >= ?
+	`, mode, timeLimit, since.UTC().Unix()).Scan(&best)
+	if err != nil {
+		return 0, fmt.Errorf("store: best hits since: %w", err)
+	}
+	return int(best.Int64), nil
+}
+
+// BestSessionIDs returns the IDs of the sessions played in the mode and
+// countdown length, highest score first. They rank the runs a ghost race
+// replays, and a ghost is raced on score in every mode, so score ranks them
+// even where personal bests count hits.
+func (s *Store) BestSessionIDs(mode string, timeLimit int) ([]int64, error) {
+	rows, err := s.db.Query(`
+		SELECT id FROM sessions WHERE mode = ? AND time_limit = ?
+		ORDER BY score DESC, id
+	`, mode, timeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("store: best sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("store: scan session id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: iterate sessions: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *Store) bestSessionValue(column, mode string, timeLimit int) (int, error) {
+	var best sql.NullInt64
+	err := s.db.QueryRow(`
+		SELECT MAX(`+column+`) FROM sessions WHERE mode = ? AND time_limit = ?
+	`, mode, timeLimit).Scan(&best)
+	if err != nil {
+		return 0, fmt.Errorf("store: best %s: %w", column, err)
+	}
+	return int(best.Int64), nil
+}
+
 func (s *Store) getSetting(key string) (string, error) {
 	var value string
 	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
@@ -304,6 +1133,71 @@ func migrate(ctx context.Context, db *sql.DB) error {
 			miss_count INTEGER NOT NULL DEFAULT 0,
 			streak INTEGER NOT NULL DEFAULT 0
 		);`,
+		`CREATE TABLE IF NOT EXISTS kana_production_stats (
+			char TEXT PRIMARY KEY,
+			correct_count INTEGER NOT NULL DEFAULT 0,
+			miss_count INTEGER NOT NULL DEFAULT 0,
+			streak INTEGER NOT NULL DEFAULT 0
+		);`,
+		`CREATE TABLE IF NOT EXISTS sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at INTEGER NOT NULL,
+			ended_at INTEGER NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			score INTEGER NOT NULL DEFAULT 0,
+			missed INTEGER NOT NULL DEFAULT 0,
+			base_points INTEGER NOT NULL DEFAULT 0,
+			combo_points INTEGER NOT NULL DEFAULT 0,
+			speed_points INTEGER NOT NULL DEFAULT 0,
+			penalty_points INTEGER NOT NULL DEFAULT 0,
+			hits INTEGER NOT NULL DEFAULT 0,
+			wrong INTEGER NOT NULL DEFAULT 0,
+			max_combo INTEGER NOT NULL DEFAULT 0
+		);`,
+		`CREATE TABLE IF NOT EXISTS custom_sets (
+			name TEXT PRIMARY KEY,
+			chars TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS mnemonics (
+			char TEXT PRIMARY KEY,
+			hint TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS daily_totals (
+			day TEXT PRIMARY KEY,
+			correct INTEGER NOT NULL DEFAULT 0,
+			played_seconds INTEGER NOT NULL DEFAULT 0,
+			goal_met INTEGER NOT NULL DEFAULT 0
+		);`,
+		`CREATE TABLE IF NOT EXISTS achievements (
+			id TEXT PRIMARY KEY,
+			earned_at INTEGER NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS profile_stats (
+			profile TEXT NOT NULL,
+			track TEXT NOT NULL,
+			char TEXT NOT NULL,
+			correct_count INTEGER NOT NULL DEFAULT 0,
+			miss_count INTEGER NOT NULL DEFAULT 0,
+			streak INTEGER NOT NULL DEFAULT 0,
+			last_seen INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (profile, track, char)
+		);`,
+		`CREATE TABLE IF NOT EXISTS matches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			played_at INTEGER NOT NULL,
+			mode TEXT NOT NULL,
+			player_a TEXT NOT NULL,
+			score_a INTEGER NOT NULL DEFAULT 0,
+			player_b TEXT NOT NULL,
+			score_b INTEGER NOT NULL DEFAULT 0,
+			winner TEXT NOT NULL DEFAULT ''
+		);`,
+		`CREATE TABLE IF NOT EXISTS confusions (
+			char TEXT NOT NULL,
+			answer TEXT NOT NULL,
+			count INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (char, answer)
+		);`,
 	}
 
 	for _, stmt := range stmts {
@@ -311,5 +1205,45 @@ func migrate(ctx context.Context, db *sql.DB) error {
 			return fmt.Errorf("store: migrate statement failed: %w", err)
 		}
 	}
+
+	// Columns added after a table was first released.
+	columns := []struct{ table, name, def string }{
+		{"sessions", "mode", "TEXT NOT NULL DEFAULT 'classic'"},
+		{"sessions", "time_limit", "INTEGER NOT NULL DEFAULT 0"},
+		{"kana_stats", "last_seen", "INTEGER NOT NULL DEFAULT 0"},
+		{"kana_production_stats", "last_seen", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, col := range columns {
+		if err := ensureColumn(ctx, db, col.table, col.name, col.def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureColumn adds a column to table unless it already exists.
+func ensureColumn(ctx context.Context, db *sql.DB, table, name, def string) error {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("store: inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return fmt.Errorf("store: inspect %s: %w", table, err)
+		}
+		if existing == name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("store: inspect %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.ExecContext(ctx, `ALTER TABLE `+table+` ADD COLUMN `+name+` `+def); err != nil {
+		return fmt.Errorf("store: add column %s.%s: %w", table, name, err)
+	}
 	return nil
 }
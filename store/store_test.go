@@ -0,0 +1,363 @@
+package store
+
+import (
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// openTestStore opens an empty store in a temporary directory.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "kana.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+	return st
+}
+
+func TestOpenAddsColumnsToAnOlderDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kana.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE kana_stats (
+			char TEXT PRIMARY KEY,
+			correct_count INTEGER NOT NULL DEFAULT 0,
+			miss_count INTEGER NOT NULL DEFAULT 0,
+			streak INTEGER NOT NULL DEFAULT 0
+		);`,
+		`CREATE TABLE sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at INTEGER NOT NULL,
+			ended_at INTEGER NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			score INTEGER NOT NULL DEFAULT 0,
+			missed INTEGER NOT NULL DEFAULT 0,
+			base_points INTEGER NOT NULL DEFAULT 0,
+			combo_points INTEGER NOT NULL DEFAULT 0,
+			speed_points INTEGER NOT NULL DEFAULT 0,
+			penalty_points INTEGER NOT NULL DEFAULT 0,
+			hits INTEGER NOT NULL DEFAULT 0,
+			wrong INTEGER NOT NULL DEFAULT 0,
+			max_combo INTEGER NOT NULL DEFAULT 0
+		);`,
+		`INSERT INTO kana_stats (char, correct_count, miss_count, streak) VALUES ('あ', 4, 1, 2);`,
+		`INSERT INTO sessions (started_at, ended_at, reason, score) VALUES (100, 160, 'misses', 250);`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("set up old schema: %v", err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("open older database: %v", err)
+	}
+	t.Cleanup(func() { _ = st.Close() })
+
+	stats, err := st.KanaStatistics()
+	if err != nil {
+		t.Fatalf("kana statistics: %v", err)
+	}
+	if got := stats["あ"]; got.CorrectCount != 4 || got.MissCount != 1 || got.Streak != 2 || !got.LastSeen.IsZero() {
+		t.Errorf("expected the old stats with no last-seen time, got %+v", got)
+	}
+
+	sessions, err := st.Sessions(0)
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Mode != "classic" || sessions[0].TimeLimit != 0 || sessions[0].Score != 250 {
+		t.Fatalf("expected the old session as an untimed classic run, got %+v", sessions)
+	}
+
+	// Opening again must find the added columns and leave them alone.
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+	st, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen migrated database: %v", err)
+	}
+}
+
+func TestSessionsRoundTrip(t *testing.T) {
+	st := openTestStore(t)
+	start := time.Unix(1_700_000_000, 0)
+	rec := SessionRecord{
+		StartedAt:     start,
+		EndedAt:       start.Add(90 * time.Second),
+		Mode:          "time-attack",
+		TimeLimit:     60,
+		Reason:        "time",
+		Score:         420,
+		Missed:        2,
+		BasePoints:    300,
+		ComboPoints:   80,
+		SpeedPoints:   60,
+		PenaltyPoints: 20,
+		Hits:          30,
+		Wrong:         4,
+		MaxCombo:      12,
+	}
+	id, err := st.SaveSession(rec)
+	if err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+
+	sessions, err := st.Sessions(10)
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	rec.ID = id
+	if len(sessions) != 1 || sessions[0] != rec {
+		t.Errorf("expected %+v, got %+v", rec, sessions)
+	}
+}
+
+func TestTrackStatisticsAreKeptApart(t *testing.T) {
+	st := openTestStore(t)
+	seen := time.Unix(1_700_000_000, 0)
+	if err := st.SaveTrackStats(TrackProduction, "か", 3, 1, 2, seen); err != nil {
+		t.Fatalf("save production stats: %v", err)
+	}
+	if err := st.SaveTrackStats(TrackProduction, "か", 4, 1, 3, time.Time{}); err != nil {
+		t.Fatalf("update production stats: %v", err)
+	}
+
+	production, err := st.TrackStatistics(TrackProduction)
+	if err != nil {
+		t.Fatalf("production stats: %v", err)
+	}
+	want := KanaStats{Char: "か", CorrectCount: 4, MissCount: 1, Streak: 3, LastSeen: seen}
+	if got := production["か"]; got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	recognition, err := st.TrackStatistics(TrackRecognition)
+	if err != nil {
+		t.Fatalf("recognition stats: %v", err)
+	}
+	if len(recognition) != 0 {
+		t.Errorf("expected production answers to stay off the recognition track, got %v", recognition)
+	}
+}
+
+func TestConfusionsRoundTrip(t *testing.T) {
+	st := openTestStore(t)
+	for _, answer := range []string{"shi", "shi", "tsu"} {
+		if err := st.RecordConfusion("ツ", answer); err != nil {
+			t.Fatalf("record confusion: %v", err)
+		}
+	}
+
+	confusions, err := st.Confusions()
+	if err != nil {
+		t.Fatalf("confusions: %v", err)
+	}
+	if got := confusions["ツ"]; got["shi"] != 2 || got["tsu"] != 1 || len(got) != 2 {
+		t.Errorf("expected shi twice and tsu once, got %v", got)
+	}
+}
+
+func TestCustomSetsRoundTrip(t *testing.T) {
+	st := openTestStore(t)
+	if err := st.SaveCustomSet("  dakuten ", []string{"が", "ぎ"}); err != nil {
+		t.Fatalf("save set: %v", err)
+	}
+	if err := st.SaveCustomSet("basics", []string{"あ"}); err != nil {
+		t.Fatalf("save set: %v", err)
+	}
+	if err := st.SaveCustomSet("dakuten", []string{"ざ"}); err != nil {
+		t.Fatalf("replace set: %v", err)
+	}
+
+	sets, err := st.CustomSets()
+	if err != nil {
+		t.Fatalf("custom sets: %v", err)
+	}
+	want := []CustomSet{{Name: "basics", Chars: []string{"あ"}}, {Name: "dakuten", Chars: []string{"ざ"}}}
+	if !slices.EqualFunc(sets, want, func(a, b CustomSet) bool {
+		return a.Name == b.Name && slices.Equal(a.Chars, b.Chars)
+	}) {
+		t.Fatalf("expected %v, got %v", want, sets)
+	}
+
+	if err := st.DeleteCustomSet("basics"); err != nil {
+		t.Fatalf("delete set: %v", err)
+	}
+	if sets, _ := st.CustomSets(); len(sets) != 1 || sets[0].Name != "dakuten" {
+		t.Errorf("expected only dakuten left, got %v", sets)
+	}
+}
+
+func TestMnemonicsRoundTrip(t *testing.T) {
+	st := openTestStore(t)
+	if err := st.SaveMnemonic("ぬ", " noodles "); err != nil {
+		t.Fatalf("save mnemonic: %v", err)
+	}
+	if err := st.SaveMnemonic("め", "an eye"); err != nil {
+		t.Fatalf("save mnemonic: %v", err)
+	}
+	if err := st.SaveMnemonic("め", ""); err != nil {
+		t.Fatalf("clear mnemonic: %v", err)
+	}
+
+	hints, err := st.Mnemonics()
+	if err != nil {
+		t.Fatalf("mnemonics: %v", err)
+	}
+	if len(hints) != 1 || hints["ぬ"] != "noodles" {
+		t.Errorf("expected only the trimmed ぬ hint, got %v", hints)
+	}
+}
+
+func TestDailyTotalsRoundTrip(t *testing.T) {
+	st := openTestStore(t)
+	days := []DailyTotal{
+		{Day: "2024-05-02", Correct: 12, Played: 3 * time.Minute},
+		{Day: "2024-05-01", Correct: 60, Played: 9 * time.Minute, GoalMet: true},
+	}
+	for _, d := range days {
+		if err := st.SaveDailyTotal(d); err != nil {
+			t.Fatalf("save daily total: %v", err)
+		}
+	}
+	days[0].Correct, days[0].GoalMet = 55, true
+	if err := st.SaveDailyTotal(days[0]); err != nil {
+		t.Fatalf("replace daily total: %v", err)
+	}
+
+	totals, err := st.DailyTotals()
+	if err != nil {
+		t.Fatalf("daily totals: %v", err)
+	}
+	want := []DailyTotal{days[1], days[0]}
+	if !slices.Equal(totals, want) {
+		t.Errorf("expected %v oldest first, got %v", want, totals)
+	}
+}
+
+func TestAchievementsKeepTheirFirstTime(t *testing.T) {
+	st := openTestStore(t)
+	first := time.Unix(1_700_000_000, 0)
+	if err := st.SaveAchievement("first-hit", first); err != nil {
+		t.Fatalf("save achievement: %v", err)
+	}
+	if err := st.SaveAchievement("first-hit", first.Add(time.Hour)); err != nil {
+		t.Fatalf("save achievement again: %v", err)
+	}
+
+	earned, err := st.Achievements()
+	if err != nil {
+		t.Fatalf("achievements: %v", err)
+	}
+	if len(earned) != 1 || !earned["first-hit"].Equal(first) {
+		t.Errorf("expected first-hit earned at %v, got %v", first, earned)
+	}
+}
+
+func TestBestSessionIDsRankByScore(t *testing.T) {
+	st := openTestStore(t)
+	start := time.Unix(1_700_000_000, 0)
+	runs := []SessionRecord{
+		{Mode: "classic", Score: 200, Hits: 40},
+		{Mode: "classic", Score: 500, Hits: 10},
+		{Mode: "classic", Score: 200, Hits: 50},
+		{Mode: "classic", TimeLimit: 60, Score: 900},
+		{Mode: "time-attack", Score: 999},
+	}
+	ids := make([]int64, len(runs))
+	for i, rec := range runs {
+		rec.StartedAt, rec.EndedAt = start, start.Add(time.Minute)
+		id, err := st.SaveSession(rec)
+		if err != nil {
+			t.Fatalf("save session: %v", err)
+		}
+		ids[i] = id
+	}
+
+	best, err := st.BestSessionIDs("classic", 0)
+	if err != nil {
+		t.Fatalf("best sessions: %v", err)
+	}
+	// Equal scores keep the order they were played in.
+	want := []int64{ids[1], ids[0], ids[2]}
+	if !slices.Equal(best, want) {
+		t.Errorf("expected %v, got %v", want, best)
+	}
+}
+
+func TestProfileStatsAddUp(t *testing.T) {
+	st := openTestStore(t)
+	earlier := time.Unix(1_700_000_000, 0)
+	later := earlier.Add(time.Hour)
+	if err := st.AddProfileStats("Aki", TrackRecognition, []KanaStats{{Char: "あ", CorrectCount: 3, MissCount: 1, Streak: 2, LastSeen: later}}); err != nil {
+		t.Fatalf("add profile stats: %v", err)
+	}
+	if err := st.AddProfileStats("Aki", TrackRecognition, []KanaStats{{Char: "あ", CorrectCount: 2, MissCount: 2, Streak: 0, LastSeen: earlier}}); err != nil {
+		t.Fatalf("add profile stats again: %v", err)
+	}
+	if err := st.AddProfileStats("Ren", TrackRecognition, []KanaStats{{Char: "あ", CorrectCount: 9}}); err != nil {
+		t.Fatalf("add other profile: %v", err)
+	}
+	if err := st.AddProfileStats("", TrackRecognition, nil); err == nil {
+		t.Error("expected a profile to be required")
+	}
+
+	stats, err := st.ProfileStatistics("Aki", TrackRecognition)
+	if err != nil {
+		t.Fatalf("profile stats: %v", err)
+	}
+	want := KanaStats{Char: "あ", CorrectCount: 5, MissCount: 3, Streak: 0, LastSeen: later}
+	if got := stats["あ"]; got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if production, _ := st.ProfileStatistics("Aki", TrackProduction); len(production) != 0 {
+		t.Errorf("expected nothing on the production track, got %v", production)
+	}
+	if own, _ := st.KanaStatistics(); len(own) != 0 {
+		t.Errorf("expected profile results to stay out of the main player's stats, got %v", own)
+	}
+}
+
+func TestMatchesCountHeadToHead(t *testing.T) {
+	st := openTestStore(t)
+	at := time.Unix(1_700_000_000, 0)
+	matches := []MatchRecord{
+		{PlayedAt: at, Mode: "classic", Players: [2]string{"Aki", "Ren"}, Scores: [2]int{300, 200}, Winner: 0},
+		{PlayedAt: at, Mode: "classic", Players: [2]string{"Ren", "Aki"}, Scores: [2]int{400, 100}, Winner: 0},
+		{PlayedAt: at, Mode: "classic", Players: [2]string{"Ren", "Aki"}, Scores: [2]int{150, 150}, Winner: -1},
+		{PlayedAt: at, Mode: "classic", Players: [2]string{"Aki", "Mio"}, Scores: [2]int{250, 100}, Winner: 0},
+	}
+	for _, rec := range matches {
+		if err := st.SaveMatch(rec); err != nil {
+			t.Fatalf("save match: %v", err)
+		}
+	}
+
+	winsA, winsB, draws, err := st.HeadToHead("Aki", "Ren")
+	if err != nil {
+		t.Fatalf("head to head: %v", err)
+	}
+	if winsA != 1 || winsB != 1 || draws != 1 {
+		t.Errorf("expected 1 – 1 with 1 drawn, got %d – %d with %d drawn", winsA, winsB, draws)
+	}
+
+	profiles, err := st.Profiles()
+	if err != nil {
+		t.Fatalf("profiles: %v", err)
+	}
+	if want := []string{"Aki", "Mio", "Ren"}; !slices.Equal(profiles, want) {
+		t.Errorf("expected profiles %v, got %v", want, profiles)
+	}
+}
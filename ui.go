@@ -12,6 +12,14 @@ import (
 
 const kanaCellWidth = 4
 
+// kanaCellWidthFor returns the cell width for a tile, widening it for words.
+func kanaCellWidthFor(text string) int {
+	if w := lipgloss.Width(text) + 2; w > kanaCellWidth {
+		return w
+	}
+	return kanaCellWidth
+}
+
 var (
 	kanaStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -39,6 +47,41 @@ var (
 	tableCellStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFFFFF"))
 
+	revealStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF8888"))
+
+	ghostStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("#2A2A3A")).
+			Foreground(lipgloss.Color("#B0B0FF")).
+			Padding(0, 1)
+
+	hintStyle = lipgloss.NewStyle().
+			Italic(true).
+			Foreground(lipgloss.Color("#FFD27F"))
+
+	// levelStyles colour progress cells by mastery level.
+	levelStyles = map[kanacore.MasteryLevel]lipgloss.Style{
+		kanacore.LevelRed:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5555")),
+		kanacore.LevelYellow: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFF55")),
+		kanacore.LevelGreen:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#55FF55")),
+	}
+
+	// heatStyles shade practice calendar days by kanacore.HeatLevel.
+	heatStyles = [kanacore.HeatLevels]lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#0E4429")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#006D32")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#26A641")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#39D353")),
+	}
+
+	compareStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#00FFFF")).
+			Padding(0, 2).
+			Align(lipgloss.Center)
+
 	gameOverStyle = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			BorderForeground(lipgloss.Color("#FFFF00")).
@@ -69,6 +112,12 @@ var kanaTable = []struct {
 
 // View renders the game state to a string
 func (m Model) View() string {
+	if m.Viewer != nil {
+		return renderReplayScreen(m)
+	}
+	if m.GameOver && m.ShowCalendar {
+		return renderCalendarScreen(m)
+	}
 	if m.GameOver {
 		return renderGameOverScreen(m)
 	}
@@ -88,20 +137,31 @@ func renderGameScreen(m Model) string {
 
 func renderGameOverScreen(m Model) string {
 	title := "GAME OVER!"
-	if m.GameOverReason == "score" {
+	switch m.GameOverReason {
+	case "score":
 		title = "SESSION COMPLETE!"
+	case "time":
+		title = "TIME'S UP!"
+	case "goal":
+		title = "GOAL REACHED!"
 	}
 
 	scoreLine := fmt.Sprintf("Final Score: %d", m.Score)
-	if m.ScoreLimit > 0 {
+	if m.ScoreLimit > 0 && !m.Mode.Timed() {
 		scoreLine = fmt.Sprintf("Final Score: %d/%d", m.Score, m.ScoreLimit)
 	}
 
 	lines := []string{
 		title,
 		scoreLine,
-		fmt.Sprintf("Missed: %d/10", m.Missed),
+		fmt.Sprintf("Missed: %s", missedDisplay(m)),
+	}
+	lines = append(lines, renderHighScore(m)...)
+	if m.GhostResult != "" {
+		lines = append(lines, fmt.Sprintf("👻 Ghost: %d | %s", m.Ghost.Final(), m.GhostResult))
 	}
+	lines = append(lines, "")
+	lines = append(lines, renderScoreBreakdown(m.Scoring.Breakdown)...)
 
 	switch m.GameOverReason {
 	case "score":
@@ -110,6 +170,14 @@ func renderGameOverScreen(m Model) string {
 		lines = append(lines, "", "10 kana slipped through. Review them and try again.")
 	case "quit":
 		lines = append(lines, "", "You ended the session early. Review your progress below.")
+	case "time":
+		lines = append(lines, "", "The clock ran out. Review your progress below.")
+	case "goal":
+		if m.Mode == kanacore.ModeDrill || m.Mode == kanacore.ModeLookalike {
+			lines = append(lines, "", "Drill complete. Well done!")
+		} else {
+			lines = append(lines, "", fmt.Sprintf("You practised %s. Well done!", m.ZenGoal.Label()))
+		}
 	default:
 		lines = append(lines, "", "Session ended.")
 	}
@@ -130,16 +198,51 @@ func renderGameOverScreen(m Model) string {
 		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
 		for _, char := range keys {
 			k := unique[char]
-			lines = append(lines, fmt.Sprintf("  %s -> %s", k.Char, k.Romaji))
+			line := fmt.Sprintf("  %s -> %s", k.Char, k.Romaji)
+			if hintChar, hint := kanacore.KanaMnemonic(k.Char, m.Mnemonics); hint != "" {
+				if hintChar != k.Char {
+					hint = hintChar + ": " + hint
+				}
+				line += "  " + hintStyle.Render(hint)
+			}
+			lines = append(lines, line)
 		}
 	} else {
 		lines = append(lines, "", "No missed characters this round!")
 	}
 
-	lines = append(lines, "", "Press ESC to exit")
+	if m.Mode == kanacore.ModeDrill {
+		lines = append(lines, "", "Drill results (before → after):")
+		lines = append(lines, renderDrillResults(m.DrillResults())...)
+	}
+
+	if mixups := m.LookalikeMixups(); len(mixups) > 0 {
+		lines = append(lines, "", "Look-alikes you mixed up:")
+		lines = append(lines, renderLookalikeMixups(m.CharacterSet, mixups)...)
+	}
+
+	if len(m.SessionBadges) > 0 {
+		lines = append(lines, "", "Badges earned:")
+		for _, a := range m.SessionBadges {
+			lines = append(lines, fmt.Sprintf("  🏅 %s — %s", a.Title, a.Description))
+		}
+	}
 
-	box := gameOverStyle.Render(strings.Join(lines, "\n"))
+	lines = append(lines, "", renderDailyProgress(m))
+	keys := "Press C for the practice calendar | ESC to exit"
+	if m.Replay != nil {
+		keys = "Press V to watch the replay | " + strings.TrimPrefix(keys, "Press ")
+	}
+	if len(unique) > 0 {
+		keys = "Press D to drill the missed characters | " + strings.TrimPrefix(keys, "Press ")
+	}
+	lines = append(lines, "", keys)
 
+	return placeBox(m, gameOverStyle.Render(strings.Join(lines, "\n")))
+}
+
+// placeBox centres a box on the screen.
+func placeBox(m Model, box string) string {
 	height := m.Height + 2
 	width := m.Width
 	if height <= 0 {
@@ -154,6 +257,45 @@ func renderGameOverScreen(m Model) string {
 		lipgloss.WithWhitespaceForeground(lipgloss.Color("#1C1C1C")))
 }
 
+// renderHighScore compares the session with the best earlier result for its mode.
+func renderHighScore(m Model) []string {
+	if m.Store == nil {
+		return nil
+	}
+	if m.Mode == kanacore.ModeDaily {
+		hits := m.Scoring.Breakdown.Hits
+		lines := []string{fmt.Sprintf("Daily challenge %s: %d correct kana", kanacore.DayKey(m.SessionStart), hits)}
+		if hits > m.PreviousBest {
+			return append(lines, "New best today!")
+		}
+		return append(lines, fmt.Sprintf("Best today: %d", m.PreviousBest))
+	}
+	if m.Mode.Timed() {
+		hits := m.Scoring.Breakdown.Hits
+		secs := int(m.Countdown() / time.Second)
+		lines := []string{fmt.Sprintf("Correct kana: %d in %ds", hits, secs)}
+		if hits > m.PreviousBest {
+			return append(lines, "New time attack record!")
+		}
+		return append(lines, fmt.Sprintf("Best: %d in %ds", m.PreviousBest, secs))
+	}
+	if m.Score > m.PreviousBest {
+		return []string{"New high score!"}
+	}
+	return []string{fmt.Sprintf("High score: %d", m.PreviousBest)}
+}
+
+// renderScoreBreakdown lists where the session's points came from.
+func renderScoreBreakdown(b kanacore.ScoreBreakdown) []string {
+	return []string{
+		"Score breakdown:",
+		fmt.Sprintf("  Correct answers:  %+d (%d hits)", b.Base, b.Hits),
+		fmt.Sprintf("  Combo bonus:      %+d (best combo %d)", b.Combo, b.MaxCombo),
+		fmt.Sprintf("  Speed bonus:      %+d", b.Speed),
+		fmt.Sprintf("  Wrong answers:    %+d (%d wrong)", -b.Penalty, b.Wrong),
+	}
+}
+
 func renderGameArea(m Model) string {
 	if m.Height <= 0 || m.GameWidth <= 0 {
 		return ""
@@ -179,15 +321,19 @@ func renderGameArea(m Model) string {
 		var builder strings.Builder
 		current := 0
 		for _, k := range kanas {
+			width := kanaCellWidthFor(k.Display())
 			x := int(k.X)
-			if x > m.GameWidth-kanaCellWidth {
-				x = m.GameWidth - kanaCellWidth
+			if x > m.GameWidth-width {
+				x = m.GameWidth - width
+			}
+			if x < current {
+				x = current
 			}
 			if x > current {
 				builder.WriteString(strings.Repeat(" ", x-current))
 			}
-			builder.WriteString(kanaStyle.Render(k.Char))
-			current = x + kanaCellWidth
+			builder.WriteString(kanaStyle.Width(width).Render(k.Display()))
+			current = x + width
 		}
 		if current < m.GameWidth {
 			builder.WriteString(strings.Repeat(" ", m.GameWidth-current))
@@ -201,9 +347,80 @@ func renderGameArea(m Model) string {
 		rows[row] = line
 	}
 
+	if len(m.MissReveals) > 0 {
+		rows[m.Height-1] = renderMissReveals(m)
+	}
+
 	return strings.Join(rows, "\n")
 }
 
+// renderMissReveals shows the romaji of recently missed kana along the bottom row.
+func renderMissReveals(m Model) string {
+	parts := make([]string, 0, len(m.MissReveals))
+	for _, r := range m.MissReveals {
+		parts = append(parts, r.Kana.Char+" = "+r.Kana.Romaji)
+	}
+	// Reveals past the field's width are cut off so the border stays put.
+	line := revealStyle.MaxWidth(m.GameWidth).Render(strings.Join(parts, "  "))
+	if width := lipgloss.Width(line); width < m.GameWidth {
+		line += strings.Repeat(" ", m.GameWidth-width)
+	}
+	return line
+}
+
+// renderDrillResults lists each drilled character's accuracy before and during the drill.
+func renderDrillResults(results []kanacore.DrillComparison) []string {
+	lines := make([]string, 0, len(results))
+	for _, r := range results {
+		mark := ""
+		if r.Improved() {
+			mark = " ↑"
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s → %s%s", r.Char, r.Before, r.After, mark))
+	}
+	return lines
+}
+
+// renderMasteryLegend explains the progress grid's colours.
+func renderMasteryLegend() string {
+	return strings.Join([]string{
+		levelStyles[kanacore.LevelRed].Render("■ missed"),
+		levelStyles[kanacore.LevelYellow].Render("■ learning"),
+		levelStyles[kanacore.LevelGreen].Render("■ mastered"),
+	}, " ")
+}
+
+// maxMixupComparisons caps how many look-alike pairs the game-over screen compares.
+const maxMixupComparisons = 3
+
+// renderLookalikeMixups shows each confused look-alike pair side by side.
+func renderLookalikeMixups(set kanacore.CharacterSet, mixups []kanacore.LookalikeMixup) []string {
+	var lines []string
+	for i, mix := range mixups {
+		if i == maxMixupComparisons {
+			break
+		}
+		shownRomaji, _ := set.GetRomaji(mix.Shown)
+		givenRomaji, _ := set.GetRomaji(mix.Given)
+		pair := lipgloss.JoinHorizontal(lipgloss.Center,
+			compareStyle.Render(mix.Shown+"\n"+shownRomaji),
+			"  ≠  ",
+			compareStyle.Render(mix.Given+"\n"+givenRomaji),
+			fmt.Sprintf("  %s answered as %s ×%d", mix.Shown, givenRomaji, mix.Count),
+		)
+		lines = append(lines, strings.Split(pair, "\n")...)
+	}
+	return lines
+}
+
+// missedDisplay formats the miss counter, omitting the limit in modes without one.
+func missedDisplay(m Model) string {
+	if !m.Mode.EndsOnMisses() {
+		return fmt.Sprintf("%d", m.Missed)
+	}
+	return fmt.Sprintf("%d/10", m.Missed)
+}
+
 func renderVerticalBorder(height int) string {
 	if height <= 0 {
 		return ""
@@ -218,28 +435,126 @@ func renderVerticalBorder(height int) string {
 
 func renderStatus(m Model) string {
 	scoreDisplay := fmt.Sprintf("%d", m.Score)
-	if m.ScoreLimit > 0 {
+	if m.Mode.Timed() {
+		scoreDisplay = fmt.Sprintf("%d | Time: %s | Correct: %d",
+			m.Score, kanacore.FormatClock(m.TimeRemaining()), m.Scoring.Breakdown.Hits)
+	} else if goal, ok := m.sessionGoal(); ok {
+		scoreDisplay = fmt.Sprintf("%d | Goal: %s", m.Score,
+			goal.Progress(m.Scoring.Breakdown.Hits, m.now().Sub(m.SessionStart)))
+	} else if m.ScoreLimit > 0 {
 		scoreDisplay = fmt.Sprintf("%d/%d", m.Score, m.ScoreLimit)
 	}
-	statusLine := statusStyle.Render(fmt.Sprintf("Score: %s | Missed: %d/10 | Type: %s",
-		scoreDisplay, m.Missed, inputStyle.Render(m.Input)))
+	if m.Scoring.Combo > 1 {
+		scoreDisplay = fmt.Sprintf("%s | Combo: %d (x%d)", scoreDisplay, m.Scoring.Combo, m.Scoring.Multiplier())
+	}
+	typed := "Type: " + inputStyle.Render(m.Input)
+	if m.Mode.MultipleChoice() {
+		typed = "Choose: " + renderChoices(m.Choices)
+	}
+	statusLine := statusStyle.Render(fmt.Sprintf("Score: %s | Missed: %s | %s | %s",
+		scoreDisplay, missedDisplay(m), typed, renderDailyProgress(m)))
 
 	// Show unlock message for 5 seconds after it's set
 	instructions := "Type the romaji and press ENTER | ESC to quit"
-	if m.UnlockMessage != "" && time.Since(m.UnlockMessageAt) < 5*time.Second {
+	if m.Mode.Production() {
+		instructions = "Type the kana (romaji converts as you type) and press ENTER | ESC to quit"
+	} else if m.Mode.MultipleChoice() {
+		instructions = "Press the number of the romaji for the next kana to land | ESC to quit"
+	}
+	if m.UnlockMessage != "" && m.now().Sub(m.UnlockMessageAt) < unlockMessageDuration {
 		unlockStyle := lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("#00FF00")).
 			Background(lipgloss.Color("#444444")).
 			Padding(0, 1)
 		instructions = unlockStyle.Render(m.UnlockMessage)
+	} else if m.Hint != "" && m.now().Sub(m.HintAt) < hintDuration {
+		instructions = hintStyle.Render("💡 " + m.Hint)
+	} else if m.Mode == kanacore.ModeDaily {
+		instructions = fmt.Sprintf("Daily challenge %s: same kana for everyone today | %s", kanacore.DayKey(m.SessionStart), instructions)
+	} else if m.Mode.Timed() {
+		instructions = fmt.Sprintf("Goal: most kana in %ds | %s", int(m.Countdown()/time.Second), instructions)
+	} else if m.Mode == kanacore.ModeZen {
+		instructions = "Zen mode: misses never end the session | " + instructions
+	} else if m.Mode == kanacore.ModeDrill {
+		instructions = fmt.Sprintf("Drilling %d missed kana | %s", len(m.DrillChars), instructions)
+	} else if m.Mode == kanacore.ModeLookalike {
+		instructions = fmt.Sprintf("Look-alikes: %s | %s", m.Lookalike.Label(), instructions)
 	} else if m.ScoreLimit > 0 {
 		instructions = fmt.Sprintf("Goal: %d points | %s", m.ScoreLimit, instructions)
 	}
 
+	if m.Ghost != nil {
+		return lipgloss.JoinVertical(lipgloss.Left, statusLine, renderGhostStatus(m), instructions)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, statusLine, instructions)
 }
 
+// renderGhostStatus shows the ghost's score at the same point of its run
+// and how far the player leads or trails it.
+func renderGhostStatus(m Model) string {
+	ghost := m.GhostScore()
+	return ghostStyle.Render(fmt.Sprintf("👻 Ghost: %d/%d | %s",
+		ghost, m.Ghost.Final(), kanacore.GhostGap(m.Score, ghost)))
+}
+
+// renderDailyProgress shows today's progress towards the daily goal and the
+// day streaks.
+func renderDailyProgress(m Model) string {
+	now := m.now()
+	current, longest := m.Practice.Streaks(now)
+	return fmt.Sprintf("Daily: %s | Streak: %d (best %d)", m.Practice.Progress(now), current, longest)
+}
+
+// renderCalendarScreen shows the practice calendar over the last weeks, one
+// column per week and one row per weekday.
+func renderCalendarScreen(m Model) string {
+	weeks := kanacore.CalendarWeeks(m.now(), kanacore.CalendarWeekCount)
+	lines := []string{
+		"PRACTICE CALENDAR",
+		"Daily goal: " + m.Practice.Goal.DailyLabel(),
+		"",
+	}
+	for day, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		var b strings.Builder
+		b.WriteString(name + " ")
+		for _, week := range weeks {
+			if week[day] == "" {
+				b.WriteString("  ")
+				continue
+			}
+			b.WriteString(heatStyles[m.Practice.HeatLevel(week[day])].Render("■") + " ")
+		}
+		lines = append(lines, b.String())
+	}
+
+	legend := make([]string, len(heatStyles))
+	for i, style := range heatStyles {
+		legend[i] = style.Render("■")
+	}
+	lines = append(lines,
+		"",
+		"Less "+strings.Join(legend, " ")+" More (brightest: goal met)",
+		"",
+		renderDailyProgress(m),
+		"",
+		"Press C to go back | ESC to exit",
+	)
+	return placeBox(m, gameOverStyle.Render(strings.Join(lines, "\n")))
+}
+
+// renderChoices numbers the multiple-choice answers for the status line.
+func renderChoices(choices []string) string {
+	if len(choices) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(choices))
+	for i, c := range choices {
+		parts[i] = fmt.Sprintf("%d) %s", i+1, inputStyle.Render(c))
+	}
+	return strings.Join(parts, "  ")
+}
+
 func renderInfoArea(m Model) string {
 	if m.Height <= 0 {
 		return ""
@@ -267,19 +582,32 @@ func renderInfoArea(m Model) string {
 			case char == "":
 				rowBuilder.WriteString(tableCellStyle.Render("   |"))
 			default:
-				count := m.sessionCorrectCount(char)
-				if count > 0 {
-					rowBuilder.WriteString(tableCellStyle.Render(fmt.Sprintf(" %2d|", count)))
-				} else {
-					rowBuilder.WriteString(tableCellStyle.Render("  -|"))
+				cell := "  -"
+				if count := m.sessionCorrectCount(char); count > 0 {
+					cell = fmt.Sprintf(" %2d", count)
+				}
+				style, ok := levelStyles[m.MasteryLevel(char)]
+				if !ok {
+					style = tableCellStyle
 				}
+				rowBuilder.WriteString(style.Render(cell))
+				rowBuilder.WriteString(tableCellStyle.Render("|"))
 			}
 		}
 		lines = append(lines, rowBuilder.String())
 	}
 
+	lines = append(lines, "", renderMasteryLegend())
+
 	// Display active rows if using auto-progression or custom selection
-	if m.AutoProgress || len(m.SelectedRows) > 0 {
+	if len(m.SelectedChars) > 0 {
+		lines = append(lines, "", tableHeaderStyle.Render("ACTIVE CHARACTERS"), "")
+		chars := m.SelectedCharList()
+		for start := 0; start < len(chars); start += 10 {
+			end := min(start+10, len(chars))
+			lines = append(lines, tableCellStyle.Render(strings.Join(chars[start:end], " ")))
+		}
+	} else if m.AutoProgress || len(m.SelectedRows) > 0 {
 		lines = append(lines, "", tableHeaderStyle.Render("ACTIVE ROWS"), "")
 		activeLabels := m.getActiveRowLabels()
 		if len(activeLabels) > 0 {
@@ -289,6 +617,15 @@ func renderInfoArea(m Model) string {
 		} else {
 			lines = append(lines, tableCellStyle.Render("All rows"))
 		}
+		if m.Regression != nil {
+			lines = append(lines, "", tableCellStyle.Render("Focused: "+m.Regression.Reason))
+		}
+	}
+
+	if m.AutoProgress && m.Regression == nil {
+		if lesson, ok := m.NextLesson(); ok {
+			lines = append(lines, "", tableCellStyle.Render(m.Curriculum.Name+" | next: "+lesson.Title))
+		}
 	}
 
 	lines = append(lines, "", tableHeaderStyle.Render("MISSED CHARACTERS"), "")
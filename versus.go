@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"kana/kanacore"
+	"kana/store"
+)
+
+// versusPrefixKeys route the keys typed after them to a player: the left
+// field's player, then the right's.
+var versusPrefixKeys = [2]string{"[", "]"}
+
+// VersusModel races two players on one keyboard, each on their own field fed
+// the same seeded tiles. Keys go to the player last picked with a prefix key.
+// The players' results are kept under their profiles rather than the main
+// statistics.
+type VersusModel struct {
+	Players [2]*Model
+	Names   [2]string
+	Active  int // player the typed keys go to
+	Store   *store.Store
+	Width   int
+	Height  int
+	Results []kanacore.PlayerResult // set once both players are done
+	Verdict string
+	Record  string // head-to-head record of the two profiles
+}
+
+// NewVersusModel sets up a split-screen race between the named profiles
+// with the rules from settings. Modes that cannot be shared play as classic.
+func NewVersusModel(st *store.Store, names [2]string, settings sessionSettings) VersusModel {
+	v := VersusModel{Names: names, Store: st, Width: 80, Height: 24}
+	start := time.Now()
+	for i := range v.Players {
+		v.Players[i] = newVersusPlayer(settings, start)
+	}
+	return v
+}
+
+// newVersusPlayer builds one player's field. Players have no store of their
+// own; starting together gives both the same seed.
+func newVersusPlayer(settings sessionSettings, start time.Time) *Model {
+	m := InitialModel(nil, nil)
+	m.SessionStart = start
+	if len(settings.Rows) > 0 {
+		m.SetSelectedRows(settings.Rows)
+	}
+	m.SetSelectedChars(settings.Chars)
+	m.SetScoreLimit(settings.ScoreLimit)
+	m.SetTargetPolicy(settings.TargetPolicy)
+	m.SetZenGoal(settings.ZenGoal)
+	m.SetLookalikeGroup(settings.Lookalike)
+	mode := settings.Mode
+	if !mode.SplitScreen() {
+		mode = kanacore.DefaultGameMode
+	}
+	m.SetGameMode(mode, settings.TimeLimit)
+	return &m
+}
+
+// Init starts the race's loops. A race is a single session, so its loops are
+// never left over from an earlier one.
+func (v VersusModel) Init() tea.Cmd {
+	return tea.Batch(tickCmd(0), spawnCmd(v.Players[0].Mode.SpawnInterval(), 0))
+}
+
+func (v VersusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.Width, v.Height = msg.Width, msg.Height
+		for _, p := range v.Players {
+			p.Width = (msg.Width - 1) / 2
+			p.GameWidth = p.Width
+			p.Height = msg.Height - 3 // Reserve space for the players' status and the key help
+		}
+
+	case tea.KeyMsg:
+		if v.Results != nil {
+			switch msg.String() {
+			case "ctrl+c", "esc", "q", "enter":
+				return v, tea.Quit
+			}
+			return v, nil
+		}
+		switch key := msg.String(); key {
+		case "ctrl+c":
+			return v, tea.Quit
+		case "esc":
+			for _, p := range v.Players {
+				p.endGame("quit")
+			}
+			v.finish()
+		case versusPrefixKeys[0], versusPrefixKeys[1]:
+			if key == versusPrefixKeys[0] {
+				v.Active = 0
+			} else {
+				v.Active = 1
+			}
+		case "tab":
+			v.Active = 1 - v.Active
+		case "enter":
+			if p := v.Players[v.Active]; !p.GameOver {
+				p.checkAnswer()
+				p.Input = ""
+			}
+		default:
+			if p := v.Players[v.Active]; !p.GameOver {
+				p.typeKey(msg)
+			}
+		}
+
+	case tickMsg:
+		if v.Results != nil {
+			return v, nil
+		}
+		for _, p := range v.Players {
+			if !p.GameOver {
+				p.update()
+			}
+		}
+		if v.Players[0].GameOver && v.Players[1].GameOver {
+			v.finish()
+			return v, nil
+		}
+		return v, tickCmd(0)
+
+	case spawnMsg:
+		if v.Results != nil {
+			return v, nil
+		}
+		// Each player draws from their own generator; equal seeds keep the
+		// tiles the same even after one player is done.
+		for _, p := range v.Players {
+			if !p.GameOver {
+				p.spawnKana()
+			}
+		}
+		return v, spawnCmd(v.Players[0].Mode.SpawnInterval(), 0)
+	}
+	return v, nil
+}
+
+// finish compares the players and keeps the match and each player's
+// results under their profiles.
+func (v *VersusModel) finish() {
+	now := time.Now()
+	v.Results = make([]kanacore.PlayerResult, len(v.Players))
+	for i, p := range v.Players {
+		b := p.Scoring.Breakdown
+		v.Results[i] = kanacore.PlayerResult{
+			Name:     v.Names[i],
+			Score:    p.Score,
+			Hits:     b.Hits,
+			Wrong:    b.Wrong,
+			Missed:   p.Missed,
+			MaxCombo: b.MaxCombo,
+		}
+	}
+	v.Verdict = kanacore.MatchVerdict(v.Results)
+	if v.Store == nil {
+		return
+	}
+	for i, p := range v.Players {
+		_ = v.Store.AddProfileStats(v.Names[i], p.statsTrack(), profileStats(p.Tallies, p.CurrentStreak, now))
+	}
+	_ = v.Store.SaveMatch(store.MatchRecord{
+		PlayedAt: now,
+		Mode:     string(v.Players[0].Mode),
+		Players:  v.Names,
+		Scores:   [2]int{v.Results[0].Score, v.Results[1].Score},
+		Winner:   kanacore.MatchWinner(v.Results),
+	})
+	if a, b, draws, err := v.Store.HeadToHead(v.Names[0], v.Names[1]); err == nil {
+		v.Record = fmt.Sprintf("Head to head: %s %d – %d %s, %d drawn", v.Names[0], a, b, v.Names[1], draws)
+	}
+}
+
+// profileStats turns a session's results per character into the increments
+// kept under a player profile.
+func profileStats(tallies map[string]kanacore.CharTally, streaks map[string]int, at time.Time) []store.KanaStats {
+	stats := make([]store.KanaStats, 0, len(tallies))
+	for char, t := range tallies {
+		stats = append(stats, store.KanaStats{
+			Char:         char,
+			CorrectCount: t.Correct,
+			MissCount:    t.Missed,
+			Streak:       streaks[char],
+			LastSeen:     at,
+		})
+	}
+	return stats
+}
+
+func (v VersusModel) View() string {
+	if v.Results != nil {
+		return renderVersusResults(v)
+	}
+	fields := lipgloss.JoinHorizontal(lipgloss.Top,
+		renderGameArea(*v.Players[0]), renderVerticalBorder(v.Players[0].Height), renderGameArea(*v.Players[1]))
+	statuses := lipgloss.JoinHorizontal(lipgloss.Top,
+		renderVersusStatus(v, 0), borderStyle.Render("│"), renderVersusStatus(v, 1))
+	help := fmt.Sprintf("%s left player | %s right player | TAB switch | ENTER submit | ESC end the race",
+		versusPrefixKeys[0], versusPrefixKeys[1])
+	return lipgloss.JoinVertical(lipgloss.Left, fields, statuses, help)
+}
+
+// renderVersusStatus shows one player's name, score, misses and input, marking
+// the player the keys go to.
+func renderVersusStatus(v VersusModel, i int) string {
+	p := v.Players[i]
+	name := v.Names[i]
+	if i == v.Active {
+		name = "▶ " + name
+	}
+	score := fmt.Sprintf("%d", p.Score)
+	if p.Mode.Timed() {
+		score = fmt.Sprintf("%d | %s", p.Score, kanacore.FormatClock(p.TimeRemaining()))
+	}
+	line := fmt.Sprintf("%s | Score: %s | Missed: %s", name, score, missedDisplay(*p))
+	if p.GameOver {
+		line += " | done"
+	} else {
+		line += " | " + inputStyle.Render(p.Input)
+	}
+	return statusStyle.Width(p.GameWidth).MaxWidth(p.GameWidth).Render(line)
+}
+
+// renderVersusResults draws the head-to-head results screen.
+func renderVersusResults(v VersusModel) string {
+	lines := []string{
+		tableHeaderStyle.Render("HEAD TO HEAD"),
+		"",
+		v.Verdict,
+		"",
+	}
+	lines = append(lines, renderResultsTable(v.Results)...)
+	if v.Record != "" {
+		lines = append(lines, "", v.Record)
+	}
+	lines = append(lines, "", "Press ENTER or ESC to exit")
+	box := gameOverStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(v.Width, v.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderResultsTable lists each player's results, marking the winner.
+func renderResultsTable(results []kanacore.PlayerResult) []string {
+	lines := []string{
+		fmt.Sprintf("%-12s %6s %5s %6s %6s %6s %9s", "Player", "Score", "Hits", "Wrong", "Missed", "Combo", "Accuracy"),
+	}
+	winner := kanacore.MatchWinner(results)
+	for i, r := range results {
+		mark := "  "
+		if i == winner {
+			mark = "🏆"
+		}
+		lines = append(lines, fmt.Sprintf("%-12s %6d %5d %6d %6d %6d %8.0f%% %s",
+			r.Name, r.Score, r.Hits, r.Wrong, r.Missed, r.MaxCombo, r.Accuracy()*100, mark))
+	}
+	return lines
+}
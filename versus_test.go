@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"kana/kanacore"
+)
+
+func newTestVersus() VersusModel {
+	return NewVersusModel(nil, [2]string{"Aki", "Ren"}, sessionSettings{Mode: kanacore.DefaultGameMode})
+}
+
+// press sends keys to v, one KeyMsg each.
+func press(v VersusModel, keys ...tea.KeyMsg) VersusModel {
+	for _, k := range keys {
+		next, _ := v.Update(k)
+		v = next.(VersusModel)
+	}
+	return v
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestVersusKeysGoToTheActivePlayer(t *testing.T) {
+	v := newTestVersus()
+
+	v = press(v, runes("k"))
+	if v.Players[0].Input != "k" || v.Players[1].Input != "" {
+		t.Fatalf("expected the left player to type first, got %q and %q", v.Players[0].Input, v.Players[1].Input)
+	}
+
+	v = press(v, runes("]"), runes("s"), runes("a"))
+	if v.Active != 1 || v.Players[0].Input != "k" || v.Players[1].Input != "sa" {
+		t.Fatalf("expected ] to route keys to the right player, got %q and %q", v.Players[0].Input, v.Players[1].Input)
+	}
+
+	v = press(v, tea.KeyMsg{Type: tea.KeyTab}, runes("a"))
+	if v.Active != 0 || v.Players[0].Input != "ka" || v.Players[1].Input != "sa" {
+		t.Fatalf("expected tab to switch back to the left player, got %q and %q", v.Players[0].Input, v.Players[1].Input)
+	}
+
+	v = press(v, runes("]"), runes("["), tea.KeyMsg{Type: tea.KeyBackspace})
+	if v.Active != 0 || v.Players[0].Input != "k" || v.Players[1].Input != "sa" {
+		t.Fatalf("expected [ to pick the left player again, got %q and %q", v.Players[0].Input, v.Players[1].Input)
+	}
+
+	v = press(v, tea.KeyMsg{Type: tea.KeyEnter})
+	if v.Players[0].Input != "" || v.Players[1].Input != "sa" {
+		t.Errorf("expected enter to submit only the left player's answer, got %q and %q", v.Players[0].Input, v.Players[1].Input)
+	}
+}
+
+func TestVersusPlayersGetTheSameTiles(t *testing.T) {
+	v := newTestVersus()
+	if v.Players[0].Seed != v.Players[1].Seed {
+		t.Fatalf("expected both players to share a seed, got %d and %d", v.Players[0].Seed, v.Players[1].Seed)
+	}
+
+	for range 8 {
+		next, _ := v.Update(spawnMsg{})
+		v = next.(VersusModel)
+	}
+	left, right := v.Players[0].Kanas, v.Players[1].Kanas
+	if len(left) != 8 || len(right) != 8 {
+		t.Fatalf("expected 8 tiles each, got %d and %d", len(left), len(right))
+	}
+	for i := range left {
+		if left[i].Char != right[i].Char || left[i].X != right[i].X || left[i].Speed != right[i].Speed {
+			t.Errorf("tile %d differs: %+v and %+v", i, *left[i], *right[i])
+		}
+	}
+}